@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/syeeel/koto-cli-go/internal/config"
+	"github.com/syeeel/koto-cli-go/internal/history"
+	"github.com/syeeel/koto-cli-go/internal/notify"
+	"github.com/syeeel/koto-cli-go/internal/reminder"
 	"github.com/syeeel/koto-cli-go/internal/repository"
 	"github.com/syeeel/koto-cli-go/internal/service"
 	"github.com/syeeel/koto-cli-go/internal/tui"
@@ -30,7 +37,7 @@ func main() {
 		return
 	}
 	// Get configuration
-	cfg, err := config.GetDefaultConfig()
+	cfg, err := config.Load()
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error: failed to get configuration: %v\n", err)
 		os.Exit(1)
@@ -51,8 +58,49 @@ func main() {
 	// Initialize service
 	svc := service.NewTodoService(repo)
 
+	// Purge todos that have been in the trash longer than the retention period
+	if _, err := svc.PurgeExpiredTrash(context.Background(), time.Now(), cfg.TrashRetention()); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to purge trash: %v\n", err)
+	}
+
+	// Archive todos that were completed longer ago than the auto-archive period
+	if _, err := svc.AutoArchive(context.Background(), time.Now(), cfg.AutoArchiveAfter()); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to archive completed todos: %v\n", err)
+	}
+
+	// Initialize notifier
+	notifier, err := notify.New(cfg.Notifications)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: failed to initialize notifications: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Run the reminder daemon instead of the TUI
+	if len(os.Args) > 1 && os.Args[1] == "remind" {
+		if err := runReminderDaemon(svc, notifier, cfg); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Undo or redo the latest change without starting the TUI
+	if len(os.Args) > 1 && (os.Args[1] == "undo" || os.Args[1] == "redo") {
+		if err := runUndo(svc, os.Args[1] == "redo"); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Load the input history; without it commands are still recalled within the session
+	hist, err := history.Load(cfg.HistoryPath(), cfg.HistorySize)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to load input history: %v\n", err)
+	}
+
 	// Create TUI model
-	model := tui.NewModel(svc)
+	model := tui.NewModel(svc, cfg, notifier, hist)
 
 	// Start the application
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -61,3 +109,41 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runReminderDaemon runs the reminder daemon until SIGINT or SIGTERM
+func runReminderDaemon(svc *service.TodoService, notifier notify.Notifier, cfg *config.Config) error {
+	offsets, err := cfg.Reminders.Offsets()
+	if err != nil {
+		return err
+	}
+	interval, err := cfg.Reminders.Interval()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	daemon := reminder.NewDaemon(svc, notifier, offsets, interval, os.Stdout)
+	return daemon.Run(ctx)
+}
+
+// runUndo undoes (or redoes) the latest change and reports it
+func runUndo(svc *service.TodoService, redo bool) error {
+	ctx := context.Background()
+	if redo {
+		entry, err := svc.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Redid %s\n", entry.Summary())
+		return nil
+	}
+
+	entry, err := svc.Undo(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Undid %s\n", entry.Summary())
+	return nil
+}
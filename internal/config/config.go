@@ -1,14 +1,55 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/notify"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
 )
 
 // Config holds the application configuration
+// Settings are persisted as JSON in ~/.koto/config.json; DBPath is derived
+// from the home directory and is not stored.
 type Config struct {
-	DBPath string
+	DBPath string `json:"-"`
+
+	// DailyGoal is the daily focus target (zero fields are not tracked)
+	DailyGoal model.DailyGoal `json:"daily_goal"`
+
+	// DaySummaryTime is the time of day ("18:00") from which the end-of-day
+	// summary is shown; empty disables the summary
+	DaySummaryTime string `json:"day_summary_time"`
+
+	// Notifications configures how alerts reach the user
+	Notifications notify.Config `json:"notifications"`
+
+	// Reminders configures due date reminders
+	Reminders ReminderConfig `json:"reminders"`
+
+	// TrashRetentionDays is how long deleted todos stay in the trash before
+	// they are purged permanently; 0 keeps them forever
+	TrashRetentionDays int `json:"trash_retention_days"`
+
+	// AutoArchiveDays is how long completed todos stay in the list before they
+	// are archived automatically; 0 disables automatic archiving
+	AutoArchiveDays int `json:"auto_archive_days"`
+
+	// HistorySize is how many commands the input history keeps in
+	// ~/.koto/history; 0 disables the history
+	HistorySize int `json:"history_size"`
+
+	// Confirm configures the confirmations asked before deleting, bulk
+	// operations and imports
+	Confirm ConfirmConfig `json:"confirm"`
+
+	path string // Location of the config file (empty if it cannot be saved)
 }
 
 // GetDefaultConfig returns the default configuration
@@ -19,10 +60,124 @@ func GetDefaultConfig() (*Config, error) {
 	}
 
 	return &Config{
-		DBPath: dbPath,
+		DBPath:         dbPath,
+		DaySummaryTime: "18:00",
+		Notifications:  notify.DefaultConfig(),
+		Reminders: ReminderConfig{
+			BeforeDue:    []string{"1h"},
+			PollInterval: "1m",
+		},
+		TrashRetentionDays: 30,
+		AutoArchiveDays:    30,
+		HistorySize:        1000,
+		Confirm:            ConfirmConfig{TypedAbove: 10},
 	}, nil
 }
 
+// TrashRetention returns how long deleted todos are kept (0 keeps them forever)
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
+
+// AutoArchiveAfter returns how long after completion todos are archived (0 disables it)
+func (c *Config) AutoArchiveAfter() time.Duration {
+	return time.Duration(c.AutoArchiveDays) * 24 * time.Hour
+}
+
+// HistoryPath returns the path of the input history file next to the database
+func (c *Config) HistoryPath() string {
+	return filepath.Join(filepath.Dir(c.DBPath), "history")
+}
+
+// ReminderConfig holds the due date reminder settings
+type ReminderConfig struct {
+	BeforeDue    []string `json:"before_due"`    // Durations before the due date to remind at (e.g. "1h", "15m")
+	PollInterval string   `json:"poll_interval"` // How often the reminder daemon checks for due todos
+}
+
+// Offsets returns the reminder offsets in minutes before the due date
+func (c ReminderConfig) Offsets() ([]int, error) {
+	offsets := make([]int, 0, len(c.BeforeDue))
+	for _, s := range c.BeforeDue {
+		minutes, err := timeutil.ParseMinutes(s)
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("invalid reminder offset %q: must be a positive duration", s)
+		}
+		offsets = append(offsets, minutes)
+	}
+	return offsets, nil
+}
+
+// Interval returns the reminder daemon polling interval
+func (c ReminderConfig) Interval() (time.Duration, error) {
+	interval, err := time.ParseDuration(c.PollInterval)
+	if err != nil || interval < time.Second {
+		return 0, fmt.Errorf("invalid reminder poll interval %q: must be at least 1s", c.PollInterval)
+	}
+	return interval, nil
+}
+
+// ConfirmConfig holds the confirmation settings
+type ConfirmConfig struct {
+	Skip       []string `json:"skip"`        // Operations ("delete", "bulk", "import") that run without asking
+	TypedAbove int      `json:"typed_above"` // Operations on more todos than this require typing their count; 0 disables it
+}
+
+// Skips reports whether the operation runs without asking
+// Permanent deletions ("purge") cannot be undone and are always confirmed.
+func (c ConfirmConfig) Skips(operation string) bool {
+	return operation != "purge" && slices.Contains(c.Skip, operation)
+}
+
+// RequiresTyping reports whether an operation on count todos requires typing the count
+// This applies even to operations that otherwise run without asking.
+func (c ConfirmConfig) RequiresTyping(count int) bool {
+	return c.TypedAbove > 0 && count > c.TypedAbove
+}
+
+// Load returns the default configuration overridden by ~/.koto/config.json
+// A missing config file is not an error.
+func Load() (*Config, error) {
+	cfg, err := GetDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.path = filepath.Join(filepath.Dir(cfg.DBPath), "config.json")
+
+	data, err := os.ReadFile(cfg.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", cfg.path, err)
+	}
+
+	return cfg, nil
+}
+
+// Save writes the configuration to the file it was loaded from
+func (c *Config) Save() error {
+	if c.path == "" {
+		return errors.New("configuration was not loaded from a file")
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(c.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
 // GetDatabasePath returns the path to the database file
 func GetDatabasePath() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+func TestLoadAndSave(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Without a config file the defaults are used
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.DailyGoal.IsSet() {
+		t.Errorf("expected no daily goal by default, got %+v", cfg.DailyGoal)
+	}
+	if cfg.DaySummaryTime != "18:00" {
+		t.Errorf("expected default day summary time 18:00, got %q", cfg.DaySummaryTime)
+	}
+	if cfg.TrashRetention() != 30*24*time.Hour {
+		t.Errorf("expected default trash retention of 30 days, got %v", cfg.TrashRetention())
+	}
+	if cfg.AutoArchiveAfter() != 30*24*time.Hour {
+		t.Errorf("expected default auto-archive after 30 days, got %v", cfg.AutoArchiveAfter())
+	}
+	if cfg.HistorySize != 1000 {
+		t.Errorf("expected default history size of 1000, got %d", cfg.HistorySize)
+	}
+	if cfg.Confirm.TypedAbove != 10 || cfg.Confirm.Skips("delete") {
+		t.Errorf("expected confirmations for everything and typing above 10 todos, got %+v", cfg.Confirm)
+	}
+	if filepath.Dir(cfg.HistoryPath()) != filepath.Dir(cfg.DBPath) {
+		t.Errorf("expected history next to the database, got %q", cfg.HistoryPath())
+	}
+
+	cfg.DailyGoal = model.DailyGoal{Pomodoros: 8, Minutes: 240}
+	cfg.Confirm.Skip = []string{"delete"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if reloaded.DailyGoal != cfg.DailyGoal {
+		t.Errorf("expected daily goal %+v, got %+v", cfg.DailyGoal, reloaded.DailyGoal)
+	}
+	if !reloaded.Confirm.Skips("delete") || reloaded.Confirm.Skips("import") {
+		t.Errorf("expected only delete to skip the confirmation, got %v", reloaded.Confirm.Skip)
+	}
+	if reloaded.DBPath != cfg.DBPath {
+		t.Errorf("expected DB path %q, got %q", cfg.DBPath, reloaded.DBPath)
+	}
+}
+
+func TestConfirmConfig(t *testing.T) {
+	cfg := ConfirmConfig{TypedAbove: 10}
+	if cfg.RequiresTyping(10) || !cfg.RequiresTyping(11) {
+		t.Error("expected typing to be required above 10 todos only")
+	}
+	if (ConfirmConfig{}).RequiresTyping(1000) {
+		t.Error("expected a zero threshold to disable typed confirmation")
+	}
+	skipAll := ConfirmConfig{Skip: []string{"delete", "purge"}}
+	if !skipAll.Skips("delete") || skipAll.Skips("purge") {
+		t.Error("expected purges to be confirmed even when skipped")
+	}
+}
+
+func TestReminderConfig(t *testing.T) {
+	cfg := ReminderConfig{BeforeDue: []string{"1h", "15m", "90"}, PollInterval: "30s"}
+
+	offsets, err := cfg.Offsets()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(offsets) != 3 || offsets[0] != 60 || offsets[1] != 15 || offsets[2] != 90 {
+		t.Errorf("unexpected offsets: %v", offsets)
+	}
+
+	interval, err := cfg.Interval()
+	if err != nil || interval != 30*time.Second {
+		t.Errorf("expected 30s interval, got %v (%v)", interval, err)
+	}
+
+	if _, err := (ReminderConfig{BeforeDue: []string{"-1h"}}).Offsets(); err == nil {
+		t.Error("expected error for negative offset")
+	}
+	if _, err := (ReminderConfig{PollInterval: "10ms"}).Interval(); err == nil {
+		t.Error("expected error for too short interval")
+	}
+}
@@ -0,0 +1,114 @@
+// Package history keeps the command input history across sessions
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// History is the list of entered commands, persisted one per line (oldest first)
+// Entering a command again moves it to the end, so every command appears once.
+type History struct {
+	path    string
+	size    int
+	entries []string
+}
+
+// Load reads the history file, keeping at most size entries
+// A missing file starts an empty history. With an empty path the history is
+// kept in memory only; a size of 0 disables the history.
+func Load(path string, size int) (*History, error) {
+	h := &History{path: path, size: size}
+	if path == "" || size <= 0 {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		h.push(line)
+	}
+	return h, nil
+}
+
+// Len returns the number of entries
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Entries returns the entries, oldest first
+func (h *History) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
+
+// Add records the entry as the most recent one and saves the history file
+func (h *History) Add(entry string) error {
+	if h.size <= 0 || !h.push(entry) {
+		return nil
+	}
+	if h.path == "" {
+		return nil
+	}
+
+	data := strings.Join(h.entries, "\n") + "\n"
+	if err := os.WriteFile(h.path, []byte(data), 0600); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// push appends the entry, dropping an earlier copy and the oldest entries over the size
+// It reports whether the entry was added (blank entries are not).
+func (h *History) push(entry string) bool {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return false
+	}
+
+	for i, e := range h.entries {
+		if e == entry {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+	return true
+}
+
+// Previous returns the newest entry before index that starts with prefix
+// Pass Len() to start from the most recent entry. ok is false if there is none.
+func (h *History) Previous(prefix string, before int) (entry string, index int, ok bool) {
+	return h.find(before, -1, func(e string) bool { return strings.HasPrefix(e, prefix) })
+}
+
+// Next returns the oldest entry after index that starts with prefix
+func (h *History) Next(prefix string, after int) (entry string, index int, ok bool) {
+	return h.find(after, 1, func(e string) bool { return strings.HasPrefix(e, prefix) })
+}
+
+// Search returns the newest entry before index that contains query (case-insensitive)
+func (h *History) Search(query string, before int) (entry string, index int, ok bool) {
+	query = strings.ToLower(query)
+	return h.find(before, -1, func(e string) bool { return strings.Contains(strings.ToLower(e), query) })
+}
+
+// find walks the entries from index in the given direction and returns the first match
+func (h *History) find(from, step int, match func(string) bool) (string, int, bool) {
+	for i := from + step; i >= 0 && i < len(h.entries); i += step {
+		if match(h.entries[i]) {
+			return h.entries[i], i, true
+		}
+	}
+	return "", from, false
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHistory_AddAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := Load(path, 3)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if h.Len() != 0 {
+		t.Fatalf("expected an empty history, got %v", h.Entries())
+	}
+
+	for _, entry := range []string{"/pomo 12", "/list", "  ", "/pomo 12", "/stats", "/report"} {
+		if err := h.Add(entry); err != nil {
+			t.Fatalf("Add(%q) failed: %v", entry, err)
+		}
+	}
+
+	// Blank entries are skipped, duplicates move to the end and the oldest entries are dropped
+	want := []string{"/pomo 12", "/stats", "/report"}
+	if !slices.Equal(h.Entries(), want) {
+		t.Errorf("expected %v, got %v", want, h.Entries())
+	}
+
+	reloaded, err := Load(path, 3)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !slices.Equal(reloaded.Entries(), want) {
+		t.Errorf("expected %v after reload, got %v", want, reloaded.Entries())
+	}
+
+	// A smaller size keeps the newest entries
+	smaller, err := Load(path, 2)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !slices.Equal(smaller.Entries(), want[1:]) {
+		t.Errorf("expected %v, got %v", want[1:], smaller.Entries())
+	}
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := Load(path, 0)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := h.Add("/list"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if h.Len() != 0 {
+		t.Errorf("expected a disabled history to stay empty, got %v", h.Entries())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no history file, got %v", err)
+	}
+}
+
+func TestHistory_Navigation(t *testing.T) {
+	h, _ := Load("", 10)
+	for _, entry := range []string{"/pomo 3", "/list", "/pomo 12", "/report --week=last"} {
+		_ = h.Add(entry)
+	}
+
+	entry, index, ok := h.Previous("/pomo", h.Len())
+	if !ok || entry != "/pomo 12" {
+		t.Fatalf("expected /pomo 12, got %q (ok=%v)", entry, ok)
+	}
+	entry, index, ok = h.Previous("/pomo", index)
+	if !ok || entry != "/pomo 3" {
+		t.Fatalf("expected /pomo 3, got %q (ok=%v)", entry, ok)
+	}
+	if _, _, ok = h.Previous("/pomo", index); ok {
+		t.Error("expected no older /pomo entry")
+	}
+
+	entry, _, ok = h.Next("/pomo", index)
+	if !ok || entry != "/pomo 12" {
+		t.Errorf("expected /pomo 12 going forward, got %q (ok=%v)", entry, ok)
+	}
+
+	entry, _, ok = h.Search("WEEK", h.Len())
+	if !ok || entry != "/report --week=last" {
+		t.Errorf("expected a case-insensitive search to find the report, got %q (ok=%v)", entry, ok)
+	}
+	if _, _, ok = h.Search("stats", h.Len()); ok {
+		t.Error("expected no match for stats")
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// EventSource identifies where a change to a todo was made
+type EventSource string
+
+const (
+	// EventSourceTUI indicates a change made in the interactive UI
+	EventSourceTUI EventSource = "tui"
+	// EventSourceCLI indicates a change made by a koto subcommand
+	EventSourceCLI EventSource = "cli"
+	// EventSourceAPI indicates a change made through the API
+	EventSourceAPI EventSource = "api"
+	// EventSourceImport indicates a todo created by a JSON import
+	EventSourceImport EventSource = "import"
+)
+
+// Fields recorded in the audit history
+// EventFieldCreated is not a field but marks the creation of a todo (NewValue is the title).
+const (
+	EventFieldCreated     = "created"
+	EventFieldTitle       = "title"
+	EventFieldDescription = "description"
+	EventFieldStatus      = "status"
+	EventFieldPriority    = "priority"
+	EventFieldDueDate     = "due_date"
+	EventFieldStartDate   = "start_date"
+	EventFieldDeletedAt   = "deleted_at"
+	EventFieldArchivedAt  = "archived_at"
+	EventFieldTags        = "tags"
+)
+
+// eventTimeFormat is the format of times in event values
+const eventTimeFormat = "2006-01-02 15:04"
+
+// TodoEvent is a field-level change of a todo in the audit history
+// Values are stored as display strings; an empty value means the field was unset.
+type TodoEvent struct {
+	ID        int64       `db:"id"`
+	TodoID    int64       `db:"todo_id"`
+	Field     string      `db:"field"`
+	OldValue  string      `db:"old_value"`
+	NewValue  string      `db:"new_value"`
+	Source    EventSource `db:"source"`
+	CreatedAt time.Time   `db:"created_at"`
+}
+
+// TodoChanges returns the field-level changes between two snapshots of a todo
+// A nil before yields a single created event. Work time is not included, the
+// work log already records it entry by entry.
+func TodoChanges(before, after *Todo) []*TodoEvent {
+	if after == nil {
+		return nil
+	}
+	if before == nil {
+		return []*TodoEvent{{TodoID: after.ID, Field: EventFieldCreated, NewValue: after.Title}}
+	}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{EventFieldTitle, before.Title, after.Title},
+		{EventFieldDescription, before.Description, after.Description},
+		{EventFieldStatus, statusName(before.Status), statusName(after.Status)},
+		{EventFieldPriority, priorityName(before.Priority), priorityName(after.Priority)},
+		{EventFieldDueDate, formatEventTime(before.DueDate), formatEventTime(after.DueDate)},
+		{EventFieldStartDate, formatEventTime(before.StartDate), formatEventTime(after.StartDate)},
+		{EventFieldDeletedAt, formatEventTime(before.DeletedAt), formatEventTime(after.DeletedAt)},
+		{EventFieldArchivedAt, formatEventTime(before.ArchivedAt), formatEventTime(after.ArchivedAt)},
+		{EventFieldTags, strings.Join(before.Tags, " "), strings.Join(after.Tags, " ")},
+	}
+
+	var events []*TodoEvent
+	for _, field := range fields {
+		if field.old != field.new {
+			events = append(events, &TodoEvent{
+				TodoID:   after.ID,
+				Field:    field.name,
+				OldValue: field.old,
+				NewValue: field.new,
+			})
+		}
+	}
+	return events
+}
+
+// ArchivedEvent returns the event of a todo archived at the given time
+func ArchivedEvent(todoID int64, at time.Time) *TodoEvent {
+	return &TodoEvent{TodoID: todoID, Field: EventFieldArchivedAt, NewValue: formatEventTime(&at)}
+}
+
+// statusName returns the name of a status for event values
+func statusName(status TodoStatus) string {
+	if status == StatusCompleted {
+		return "completed"
+	}
+	return "pending"
+}
+
+// priorityName returns the name of a priority for event values
+func priorityName(priority Priority) string {
+	switch priority {
+	case PriorityHigh:
+		return "high"
+	case PriorityMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// formatEventTime formats an optional time for event values (empty for nil)
+func formatEventTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(eventTimeFormat)
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTodoChanges(t *testing.T) {
+	due := time.Date(2025, 3, 14, 17, 0, 0, 0, time.UTC)
+	before := &Todo{ID: 3, Title: "Write report", Status: StatusPending, Priority: PriorityLow}
+	after := &Todo{ID: 3, Title: "Write report", Status: StatusCompleted, Priority: PriorityHigh, DueDate: &due, WorkDuration: 25, Tags: []string{"work", "q1"}}
+
+	created := TodoChanges(nil, before)
+	if len(created) != 1 || created[0].Field != EventFieldCreated || created[0].NewValue != "Write report" {
+		t.Errorf("expected a single created event, got %+v", created)
+	}
+
+	// Work time is left to the work log
+	changes := TodoChanges(before, after)
+	expected := []TodoEvent{
+		{TodoID: 3, Field: EventFieldStatus, OldValue: "pending", NewValue: "completed"},
+		{TodoID: 3, Field: EventFieldPriority, OldValue: "low", NewValue: "high"},
+		{TodoID: 3, Field: EventFieldDueDate, OldValue: "", NewValue: "2025-03-14 17:00"},
+		{TodoID: 3, Field: EventFieldTags, OldValue: "", NewValue: "work q1"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, want := range expected {
+		if *changes[i] != want {
+			t.Errorf("change %d = %+v, want %+v", i, *changes[i], want)
+		}
+	}
+
+	if unchanged := TodoChanges(before, before); len(unchanged) != 0 {
+		t.Errorf("expected no changes, got %+v", unchanged)
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DailyGoal is a daily focus target such as "8 pomodoros / 4 hours"
+// Zero fields are not tracked; the goal is met when every tracked target is reached.
+type DailyGoal struct {
+	Pomodoros int `json:"pomodoros"`
+	Minutes   int `json:"minutes"`
+}
+
+// IsSet returns true if at least one target is tracked
+func (g DailyGoal) IsSet() bool {
+	return g.Pomodoros > 0 || g.Minutes > 0
+}
+
+// Met returns true if every tracked target is reached
+func (g DailyGoal) Met(pomodoros, minutes int) bool {
+	return g.IsSet() && g.Progress(pomodoros, minutes) >= 1
+}
+
+// Progress returns how far the least advanced target is, from 0.0 to 1.0
+func (g DailyGoal) Progress(pomodoros, minutes int) float64 {
+	if !g.IsSet() {
+		return 0
+	}
+
+	progress := 1.0
+	if g.Pomodoros > 0 {
+		progress = min(progress, float64(pomodoros)/float64(g.Pomodoros))
+	}
+	if g.Minutes > 0 {
+		progress = min(progress, float64(minutes)/float64(g.Minutes))
+	}
+	return max(progress, 0)
+}
+
+// String returns a human-readable description (e.g. "8 pomodoros / 4h")
+func (g DailyGoal) String() string {
+	var parts []string
+	if g.Pomodoros > 0 {
+		parts = append(parts, fmt.Sprintf("%d pomodoros", g.Pomodoros))
+	}
+	if g.Minutes > 0 {
+		parts = append(parts, FormatMinutes(g.Minutes))
+	}
+	if len(parts) == 0 {
+		return "no goal"
+	}
+	return strings.Join(parts, " / ")
+}
+
+// GoalProgress is the progress of a day towards the daily goal
+type GoalProgress struct {
+	Goal                DailyGoal
+	Pomodoros           int  // Pomodoros finished today
+	Minutes             int  // Minutes of work logged today
+	Streak              int  // Consecutive days the goal was met
+	StreakIncludesToday bool // True if today's goal is already met
+}
+
+// Met returns true if today's goal is met
+func (p GoalProgress) Met() bool {
+	return p.Goal.Met(p.Pomodoros, p.Minutes)
+}
+
+// Percent returns today's progress from 0.0 to 1.0
+func (p GoalProgress) Percent() float64 {
+	return p.Goal.Progress(p.Pomodoros, p.Minutes)
+}
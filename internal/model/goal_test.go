@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+func TestDailyGoal_Progress(t *testing.T) {
+	tests := []struct {
+		name      string
+		goal      DailyGoal
+		pomodoros int
+		minutes   int
+		expected  float64
+		met       bool
+	}{
+		{"no goal", DailyGoal{}, 5, 300, 0, false},
+		{"pomodoros only", DailyGoal{Pomodoros: 8}, 2, 0, 0.25, false},
+		{"minutes only", DailyGoal{Minutes: 240}, 0, 240, 1, true},
+		{"least advanced target", DailyGoal{Pomodoros: 8, Minutes: 240}, 8, 120, 0.5, false},
+		{"both reached", DailyGoal{Pomodoros: 8, Minutes: 240}, 9, 300, 1, true},
+		{"negative minutes", DailyGoal{Minutes: 60}, 0, -30, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.goal.Progress(tt.pomodoros, tt.minutes); got != tt.expected {
+				t.Errorf("Progress() = %v, want %v", got, tt.expected)
+			}
+			if got := tt.goal.Met(tt.pomodoros, tt.minutes); got != tt.met {
+				t.Errorf("Met() = %v, want %v", got, tt.met)
+			}
+		})
+	}
+}
+
+func TestDailyGoal_String(t *testing.T) {
+	tests := []struct {
+		goal     DailyGoal
+		expected string
+	}{
+		{DailyGoal{}, "no goal"},
+		{DailyGoal{Pomodoros: 8}, "8 pomodoros"},
+		{DailyGoal{Pomodoros: 8, Minutes: 240}, "8 pomodoros / 4h"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.goal.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DueReminder is a notification about a todo's due date
+type DueReminder struct {
+	Todo          *Todo
+	OffsetMinutes int // Minutes before the due date (0 when the todo is overdue)
+}
+
+// IsOverdue returns true if the reminder reports an overdue todo
+func (r DueReminder) IsOverdue() bool {
+	return r.OffsetMinutes == 0
+}
+
+// Title returns the notification title
+func (r DueReminder) Title() string {
+	if r.IsOverdue() {
+		return "⚠️ Todo overdue"
+	}
+	return "⏰ Todo due in " + FormatMinutes(r.OffsetMinutes)
+}
+
+// Message returns the notification message
+func (r DueReminder) Message() string {
+	message := fmt.Sprintf("#%d %s", r.Todo.ID, r.Todo.Title)
+	if r.Todo.DueDate != nil {
+		message += " (due " + r.Todo.DueDate.Format("Mon 15:04") + ")"
+	}
+	return message
+}
+
+// Reminder is an explicit reminder time set on a todo ("remind me at 15:00")
+// A todo can have several reminders. Snoozing moves RemindAt and clears SentAt.
+type Reminder struct {
+	ID          int64      `db:"id"`
+	TodoID      int64      `db:"todo_id"`
+	TodoTitle   string     `db:"title"`        // Title of the todo (read-only)
+	RemindAt    time.Time  `db:"remind_at"`    // When the reminder is due
+	SentAt      *time.Time `db:"sent_at"`      // When a notification was sent (nil if not yet)
+	DismissedAt *time.Time `db:"dismissed_at"` // When the reminder was dismissed (nil if active)
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// IsDue returns true if the reminder is active and its time has come
+func (r Reminder) IsDue(now time.Time) bool {
+	return r.DismissedAt == nil && !now.Before(r.RemindAt)
+}
+
+// Message returns the notification message
+func (r Reminder) Message() string {
+	return fmt.Sprintf("#%d %s", r.TodoID, r.TodoTitle)
+}
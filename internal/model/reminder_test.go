@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueReminder(t *testing.T) {
+	due := time.Date(2025, 1, 15, 15, 0, 0, 0, time.Local)
+	todo := &Todo{ID: 3, Title: "Send report", DueDate: &due}
+
+	before := DueReminder{Todo: todo, OffsetMinutes: 90}
+	if before.IsOverdue() {
+		t.Error("IsOverdue() = true for a reminder before the due date")
+	}
+	if got := before.Title(); got != "⏰ Todo due in 1h 30m" {
+		t.Errorf("Title() = %q", got)
+	}
+	if got := before.Message(); got != "#3 Send report (due Wed 15:00)" {
+		t.Errorf("Message() = %q", got)
+	}
+
+	overdue := DueReminder{Todo: todo}
+	if !overdue.IsOverdue() {
+		t.Error("IsOverdue() = false for an overdue reminder")
+	}
+}
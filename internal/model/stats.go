@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// DayCount is a count of events that happened on a single day
+type DayCount struct {
+	Day   time.Time `db:"day"` // Midnight (local time) of the day
+	Count int       `db:"count"`
+}
+
+// Stats summarizes recent productivity
+type Stats struct {
+	Days                []time.Time      // Every day of the sparkline period, in order
+	CompletedPerDay     []int            // Todos completed per day (aligned with Days)
+	PomodorosPerDay     []int            // Pomodoros finished per day (aligned with Days)
+	FocusThisWeek       int              // Minutes of work logged since Monday
+	FocusLastWeek       int              // Minutes of work logged during the previous week
+	AvgCompletion       time.Duration    // Average time from creation to completion
+	CompletedTotal      int              // Number of completed todos AvgCompletion is based on
+	OpenByPriority      map[Priority]int // Number of pending todos per priority
+	CurrentStreak       int              // Consecutive days with at least one completed todo
+	StreakIncludesToday bool             // True if today already counts towards the streak
+}
+
+// DayCounts aligns per-day counts with days, filling days without events with 0
+func DayCounts(days []time.Time, counts []DayCount) []int {
+	index := make(map[string]int, len(days))
+	for i, day := range days {
+		index[day.Format("2006-01-02")] = i
+	}
+
+	result := make([]int, len(days))
+	for _, c := range counts {
+		if i, ok := index[c.Day.Format("2006-01-02")]; ok {
+			result[i] += c.Count
+		}
+	}
+	return result
+}
+
+// CurrentStreak counts the consecutive days with activity ending today
+// A day without activity yet today does not break the streak, so it is
+// counted from yesterday instead. activeDays may be in any order.
+func CurrentStreak(activeDays []time.Time, today time.Time) (int, bool) {
+	active := make(map[string]bool, len(activeDays))
+	for _, day := range activeDays {
+		active[day.Format("2006-01-02")] = true
+	}
+
+	y, m, d := today.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, today.Location())
+	includesToday := active[day.Format("2006-01-02")]
+	if !includesToday {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for active[day.Format("2006-01-02")] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak, includesToday
+}
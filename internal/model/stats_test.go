@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayCounts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.Local) }
+	days := []time.Time{day(13), day(14), day(15)}
+
+	counts := DayCounts(days, []DayCount{
+		{Day: day(12), Count: 9}, // Outside the period
+		{Day: day(13), Count: 2},
+		{Day: day(15), Count: 4},
+	})
+
+	expected := []int{2, 0, 4}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("counts[%d] = %d, want %d", i, counts[i], expected[i])
+		}
+	}
+}
+
+func TestCurrentStreak(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.Local) }
+	today := time.Date(2025, 1, 15, 18, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		active        []time.Time
+		expected      int
+		includesToday bool
+	}{
+		{"no activity", nil, 0, false},
+		{"today only", []time.Time{day(15)}, 1, true},
+		{"ending today", []time.Time{day(13), day(14), day(15)}, 3, true},
+		{"ending yesterday", []time.Time{day(14), day(13)}, 2, false},
+		{"gap", []time.Time{day(15), day(13), day(12)}, 1, true},
+		{"broken before yesterday", []time.Time{day(13)}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streak, includesToday := CurrentStreak(tt.active, today)
+			if streak != tt.expected {
+				t.Errorf("CurrentStreak() = %d, want %d", streak, tt.expected)
+			}
+			if includesToday != tt.includesToday {
+				t.Errorf("CurrentStreak() includesToday = %v, want %v", includesToday, tt.includesToday)
+			}
+		})
+	}
+}
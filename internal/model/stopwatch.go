@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Stopwatch represents a free-running work timer attached to a todo
+// Only one stopwatch can run at a time.
+type Stopwatch struct {
+	TodoID    int64     `db:"todo_id"`
+	StartedAt time.Time `db:"started_at"`
+}
+
+// Elapsed returns the time elapsed since the stopwatch was started
+func (s Stopwatch) Elapsed(now time.Time) time.Duration {
+	if now.Before(s.StartedAt) {
+		return 0
+	}
+	return now.Sub(s.StartedAt)
+}
+
+// ElapsedMinutes returns the number of whole minutes elapsed since the stopwatch was started
+func (s Stopwatch) ElapsedMinutes(now time.Time) int {
+	return int(s.Elapsed(now) / time.Minute)
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopwatch_Elapsed(t *testing.T) {
+	start := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	sw := Stopwatch{TodoID: 1, StartedAt: start}
+
+	if got := sw.ElapsedMinutes(start.Add(12*time.Minute + 59*time.Second)); got != 12 {
+		t.Errorf("ElapsedMinutes() = %d, want 12", got)
+	}
+	if got := sw.Elapsed(start.Add(-time.Minute)); got != 0 {
+		t.Errorf("Elapsed() before start = %v, want 0", got)
+	}
+}
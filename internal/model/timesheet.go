@@ -0,0 +1,79 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// DailyWork is the work time tracked for a todo on a single day
+type DailyWork struct {
+	TodoID  int64     `db:"todo_id"`
+	Title   string    `db:"title"`
+	Day     time.Time `db:"day"` // Midnight (local time) of the day the work happened
+	Minutes int       `db:"minutes"`
+}
+
+// Timesheet aggregates tracked work per day and per todo for a date range
+type Timesheet struct {
+	From      time.Time       // First day of the range (inclusive)
+	To        time.Time       // Day after the last day of the range (exclusive)
+	Days      []time.Time     // Every day in the range, in order
+	Rows      []*TimesheetRow // One row per todo with tracked work
+	DayTotals []int           // Minutes per day across all todos (aligned with Days)
+	Total     int             // Minutes across the whole range
+}
+
+// TimesheetRow is the work tracked for a single todo in a timesheet
+type TimesheetRow struct {
+	TodoID  int64
+	Title   string
+	Minutes []int // Minutes per day (aligned with Timesheet.Days)
+	Total   int   // Minutes across the whole range
+}
+
+// NewTimesheet builds a timesheet for [from, to) from per-day work entries
+// Rows are ordered by total time, largest first
+func NewTimesheet(from, to time.Time, entries []DailyWork) *Timesheet {
+	ts := &Timesheet{From: from, To: to}
+
+	dayIndex := make(map[string]int)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		dayIndex[day.Format("2006-01-02")] = len(ts.Days)
+		ts.Days = append(ts.Days, day)
+	}
+	ts.DayTotals = make([]int, len(ts.Days))
+
+	rows := make(map[int64]*TimesheetRow)
+	for _, entry := range entries {
+		i, ok := dayIndex[entry.Day.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+
+		row, exists := rows[entry.TodoID]
+		if !exists {
+			row = &TimesheetRow{
+				TodoID:  entry.TodoID,
+				Title:   entry.Title,
+				Minutes: make([]int, len(ts.Days)),
+			}
+			rows[entry.TodoID] = row
+			ts.Rows = append(ts.Rows, row)
+		}
+
+		row.Minutes[i] += entry.Minutes
+		row.Total += entry.Minutes
+		ts.DayTotals[i] += entry.Minutes
+		ts.Total += entry.Minutes
+	}
+
+	// Largest rows first, ties broken by todo ID for a stable order
+	sort.Slice(ts.Rows, func(i, j int) bool {
+		if ts.Rows[i].Total != ts.Rows[j].Total {
+			return ts.Rows[i].Total > ts.Rows[j].Total
+		}
+		return ts.Rows[i].TodoID < ts.Rows[j].TodoID
+	})
+
+	return ts
+}
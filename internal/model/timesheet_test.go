@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimesheet(t *testing.T) {
+	from := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 7)
+	day := func(offset int) time.Time { return from.AddDate(0, 0, offset) }
+
+	entries := []DailyWork{
+		{TodoID: 1, Title: "Write docs", Day: day(0), Minutes: 30},
+		{TodoID: 2, Title: "Fix bug", Day: day(0), Minutes: 60},
+		{TodoID: 1, Title: "Write docs", Day: day(2), Minutes: 45},
+		{TodoID: 2, Title: "Fix bug", Day: day(6), Minutes: 20},
+		{TodoID: 3, Title: "Out of range", Day: day(7), Minutes: 120},
+	}
+
+	ts := NewTimesheet(from, to, entries)
+
+	if len(ts.Days) != 7 {
+		t.Fatalf("expected 7 days, got %d", len(ts.Days))
+	}
+	if len(ts.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(ts.Rows))
+	}
+
+	// Rows are ordered by total, largest first
+	if ts.Rows[0].TodoID != 2 || ts.Rows[0].Total != 80 {
+		t.Errorf("expected first row todo #2 with 80 minutes, got #%d with %d", ts.Rows[0].TodoID, ts.Rows[0].Total)
+	}
+	if ts.Rows[1].Minutes[2] != 45 {
+		t.Errorf("expected 45 minutes on day 3 for todo #1, got %d", ts.Rows[1].Minutes[2])
+	}
+
+	if ts.DayTotals[0] != 90 {
+		t.Errorf("expected 90 minutes on day 1, got %d", ts.DayTotals[0])
+	}
+	if ts.Total != 155 {
+		t.Errorf("expected total 155 minutes, got %d", ts.Total)
+	}
+}
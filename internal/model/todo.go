@@ -2,6 +2,8 @@ package model
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -36,6 +38,11 @@ type Todo struct {
 	Priority     Priority   `db:"priority"`
 	DueDate      *time.Time `db:"due_date"`
 	WorkDuration int        `db:"work_duration"` // Cumulative work time in minutes
+	CompletedAt  *time.Time `db:"completed_at"`  // When the todo was completed (nil if pending)
+	StartDate    *time.Time `db:"start_date"`    // Hidden from the default list until this time (nil if not deferred)
+	DeletedAt    *time.Time `db:"deleted_at"`    // When the todo was moved to the trash (nil if not deleted)
+	ArchivedAt   *time.Time `db:"archived_at"`   // When the todo was archived (nil if not archived)
+	Tags         []string   `db:"tags"`          // Lowercase labels without the leading "#" (see NormalizeTags)
 	CreatedAt    time.Time  `db:"created_at"`
 	UpdatedAt    time.Time  `db:"updated_at"`
 }
@@ -58,6 +65,36 @@ func (t Todo) IsOverdue() bool {
 	return time.Now().After(*t.DueDate) && t.IsPending()
 }
 
+// IsDeleted returns true if the todo is in the trash
+func (t Todo) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
+// IsArchived returns true if the todo is archived
+func (t Todo) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// IsDeferred returns true if the todo is hidden until a start date after now
+func (t Todo) IsDeferred(now time.Time) bool {
+	return t.StartDate != nil && t.StartDate.After(now)
+}
+
+// NormalizeTags cleans up tags for storage
+// Tags are lowercased and stripped of a leading "#"; empty tags and duplicates
+// are dropped and the order is kept. It returns nil if no tag is left.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || strings.ContainsAny(tag, " \t") || slices.Contains(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
 // GetWorkDurationFormatted returns the work duration in human-readable format
 // Examples:
 //   - 0 minutes: ""
@@ -65,13 +102,19 @@ func (t Todo) IsOverdue() bool {
 //   - 60 minutes: "1h"
 //   - 125 minutes: "2h 5m"
 func (t Todo) GetWorkDurationFormatted() string {
+	return FormatMinutes(t.WorkDuration)
+}
+
+// FormatMinutes formats a number of minutes in human-readable format
+// (see GetWorkDurationFormatted for examples)
+func FormatMinutes(totalMinutes int) string {
 	// Defensive: handle negative or zero duration
-	if t.WorkDuration <= 0 {
+	if totalMinutes <= 0 {
 		return ""
 	}
 
-	hours := t.WorkDuration / 60
-	minutes := t.WorkDuration % 60
+	hours := totalMinutes / 60
+	minutes := totalMinutes % 60
 
 	// Hours only (no remaining minutes)
 	if hours > 0 && minutes == 0 {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -111,6 +112,32 @@ func TestTodo_IsOverdue(t *testing.T) {
 	}
 }
 
+func TestTodo_IsDeferred(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.Local)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		startDate *time.Time
+		want      bool
+	}{
+		{"todo without start date is not deferred", nil, false},
+		{"todo with past start date is not deferred", &past, false},
+		{"todo starting now is not deferred", &now, false},
+		{"todo with future start date is deferred", &future, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := Todo{StartDate: tt.startDate}
+			if got := todo.IsDeferred(now); got != tt.want {
+				t.Errorf("Todo.IsDeferred() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTodo_GetWorkDurationFormatted(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -173,3 +200,25 @@ func TestTodo_GetWorkDurationFormatted(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"strips hash and lowercases", []string{"#Auth", "UI"}, []string{"auth", "ui"}},
+		{"drops duplicates keeping order", []string{"ui", "auth", "#UI"}, []string{"ui", "auth"}},
+		{"drops empty tags", []string{"#", " ", "auth"}, []string{"auth"}},
+		{"drops tags with spaces", []string{"two words"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.tags); !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
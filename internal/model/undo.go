@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// UndoAction identifies the kind of change recorded in the undo history
+type UndoAction string
+
+const (
+	// UndoActionCreate indicates a todo was created
+	UndoActionCreate UndoAction = "create"
+	// UndoActionEdit indicates a todo's fields were changed
+	UndoActionEdit UndoAction = "edit"
+	// UndoActionDelete indicates a todo was deleted
+	UndoActionDelete UndoAction = "delete"
+	// UndoActionRestore indicates a todo was restored from the trash
+	UndoActionRestore UndoAction = "restore"
+	// UndoActionArchive indicates a todo was archived
+	UndoActionArchive UndoAction = "archive"
+	// UndoActionUnarchive indicates a todo was brought back from the archive
+	UndoActionUnarchive UndoAction = "unarchive"
+	// UndoActionComplete indicates a todo was completed
+	UndoActionComplete UndoAction = "complete"
+	// UndoActionWork indicates the effective minutes of a work log entry changed
+	UndoActionWork UndoAction = "work"
+)
+
+// UndoEntry is a change in the undo history
+// Todo changes keep snapshots of the todo before and after the change (nil when
+// the todo did not exist). Work changes keep the effective minutes of the work log
+// entry before and after, so undoing them appends a correction like any other
+// adjustment and the work history stays auditable. The changes of a bulk
+// operation share a group and are undone together.
+type UndoEntry struct {
+	ID            int64      `db:"id"`
+	Action        UndoAction `db:"action"`
+	TodoID        int64      `db:"todo_id"`
+	Title         string     `db:"title"`          // Title of the todo when the change was made
+	Before        *Todo      `db:"before_json"`    // Todo before the change (nil if created)
+	After         *Todo      `db:"after_json"`     // Todo after the change (nil if deleted)
+	WorkLogID     *int64     `db:"work_log_id"`    // Work log entry whose minutes changed (work changes only)
+	MinutesBefore int        `db:"minutes_before"` // Effective minutes of the entry before the change
+	MinutesAfter  int        `db:"minutes_after"`  // Effective minutes of the entry after the change
+	CreatedAt     time.Time  `db:"created_at"`
+	UndoneAt      *time.Time `db:"undone_at"` // When the change was undone (nil if in effect)
+	GroupID       *int64     `db:"group_id"`  // Shared by the changes of a bulk change (nil for a single change)
+	GroupSize     int        `db:"-"`         // Number of changes undone or redone together (set by undo and redo)
+}
+
+// Summary describes the change for status messages
+func (e UndoEntry) Summary() string {
+	if e.GroupSize > 1 {
+		return fmt.Sprintf("bulk %s of %d todos", e.Action, e.GroupSize)
+	}
+
+	switch e.Action {
+	case UndoActionCreate:
+		return fmt.Sprintf("add #%d %s", e.TodoID, e.Title)
+	case UndoActionEdit:
+		return fmt.Sprintf("edit of #%d %s", e.TodoID, e.Title)
+	case UndoActionDelete:
+		return fmt.Sprintf("delete of #%d %s", e.TodoID, e.Title)
+	case UndoActionRestore:
+		return fmt.Sprintf("restore of #%d %s", e.TodoID, e.Title)
+	case UndoActionArchive:
+		return fmt.Sprintf("archive of #%d %s", e.TodoID, e.Title)
+	case UndoActionUnarchive:
+		return fmt.Sprintf("unarchive of #%d %s", e.TodoID, e.Title)
+	case UndoActionComplete:
+		return fmt.Sprintf("completion of #%d %s", e.TodoID, e.Title)
+	case UndoActionWork:
+		return fmt.Sprintf("work time change on #%d %s (%dm → %dm)", e.TodoID, e.Title, e.MinutesBefore, e.MinutesAfter)
+	default:
+		return fmt.Sprintf("%s of #%d %s", e.Action, e.TodoID, e.Title)
+	}
+}
+
+// UndoChange holds the writes that undo or redo a change, or all changes of a bulk
+// change, so they can be made in a single transaction
+type UndoChange struct {
+	EntryIDs []int64      // Changes in the undo history that are undone or redone
+	Undone   bool         // Whether the changes are undone (false when they are redone)
+	Restore  []*Todo      // Todos written back with their original ID
+	Delete   []int64      // Todos moved to the trash
+	WorkLogs []*WorkLog   // Corrections appended to the work log
+	Events   []*TodoEvent // Field-level changes for the audit history
+}
@@ -0,0 +1,21 @@
+package model
+
+import "testing"
+
+func TestUndoEntry_Summary(t *testing.T) {
+	tests := []struct {
+		entry    UndoEntry
+		expected string
+	}{
+		{UndoEntry{Action: UndoActionCreate, TodoID: 3, Title: "Write report"}, "add #3 Write report"},
+		{UndoEntry{Action: UndoActionDelete, TodoID: 3, Title: "Write report"}, "delete of #3 Write report"},
+		{UndoEntry{Action: UndoActionComplete, TodoID: 3, Title: "Write report"}, "completion of #3 Write report"},
+		{UndoEntry{Action: UndoActionWork, TodoID: 3, Title: "Write report", MinutesAfter: 25}, "work time change on #3 Write report (0m → 25m)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.Summary(); got != tt.expected {
+			t.Errorf("Summary() = %q, want %q", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// WorkLogSource identifies how a work log entry was recorded
+type WorkLogSource string
+
+const (
+	// WorkLogSourcePomodoro indicates time recorded by the Pomodoro timer
+	WorkLogSourcePomodoro WorkLogSource = "pomodoro"
+	// WorkLogSourcePartialPomodoro indicates time recorded by a Pomodoro timer stopped early
+	// It counts towards the work time but not as a finished Pomodoro.
+	WorkLogSourcePartialPomodoro WorkLogSource = "partial"
+	// WorkLogSourceStopwatch indicates time recorded by the free-running stopwatch
+	WorkLogSourceStopwatch WorkLogSource = "stopwatch"
+	// WorkLogSourceManual indicates time logged by hand (e.g. meetings)
+	WorkLogSourceManual WorkLogSource = "manual"
+	// WorkLogSourceCorrection indicates an entry that adjusts or voids an earlier entry
+	WorkLogSourceCorrection WorkLogSource = "correction"
+)
+
+// WorkLog represents a single entry in a todo's work time history
+// Entries are append-only: corrections are recorded as new entries
+// referencing the entry they adjust, so the history stays auditable.
+type WorkLog struct {
+	ID         int64         `db:"id"`
+	TodoID     int64         `db:"todo_id"`
+	Minutes    int           `db:"minutes"` // Positive adds time, negative removes time
+	Note       string        `db:"note"`
+	Source     WorkLogSource `db:"source"`
+	CorrectsID *int64        `db:"corrects_id"` // ID of the entry this one corrects (nil if none)
+	LoggedAt   time.Time     `db:"logged_at"`   // When the work happened
+	CreatedAt  time.Time     `db:"created_at"`  // When the entry was recorded
+}
+
+// IsCorrection returns true if the entry corrects another entry
+func (w WorkLog) IsCorrection() bool {
+	return w.CorrectsID != nil
+}
+
+// EffectiveWorkMinutes returns the effective minutes of every original entry
+// after applying the corrections that reference it
+func EffectiveWorkMinutes(logs []*WorkLog) map[int64]int {
+	effective := make(map[int64]int)
+	for _, log := range logs {
+		if !log.IsCorrection() {
+			effective[log.ID] += log.Minutes
+		}
+	}
+	for _, log := range logs {
+		if log.IsCorrection() {
+			effective[*log.CorrectsID] += log.Minutes
+		}
+	}
+	return effective
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestEffectiveWorkMinutes(t *testing.T) {
+	first := int64(1)
+	second := int64(2)
+
+	logs := []*WorkLog{
+		{ID: 1, Minutes: 25, Source: WorkLogSourcePomodoro},
+		{ID: 2, Minutes: 60, Source: WorkLogSourceManual},
+		{ID: 3, Minutes: 15, Source: WorkLogSourceCorrection, CorrectsID: &first},
+		{ID: 4, Minutes: -60, Source: WorkLogSourceCorrection, CorrectsID: &second},
+	}
+
+	effective := EffectiveWorkMinutes(logs)
+
+	if got := effective[1]; got != 40 {
+		t.Errorf("effective minutes of entry 1 = %d, want 40", got)
+	}
+	if got := effective[2]; got != 0 {
+		t.Errorf("effective minutes of entry 2 = %d, want 0", got)
+	}
+	if _, exists := effective[3]; exists {
+		t.Error("corrections should not have their own effective minutes")
+	}
+}
+
+func TestWorkLog_IsCorrection(t *testing.T) {
+	id := int64(1)
+
+	if (WorkLog{}).IsCorrection() {
+		t.Error("IsCorrection() = true for an original entry, want false")
+	}
+	if !(WorkLog{CorrectsID: &id}).IsCorrection() {
+		t.Error("IsCorrection() = false for a correction, want true")
+	}
+}
@@ -0,0 +1,150 @@
+// Package notify delivers notifications outside the terminal
+package notify
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Backend names accepted in Config.Backend
+const (
+	// BackendDesktop shows desktop notifications and plays sounds (default)
+	BackendDesktop = "desktop"
+	// BackendFile appends notifications to a log file (useful for headless setups and tests)
+	BackendFile = "file"
+	// BackendNone disables notifications
+	BackendNone = "none"
+)
+
+// Notifier delivers notifications to the user
+type Notifier interface {
+	// Notify shows a notification with a title and message
+	Notify(title, message string) error
+
+	// Beep plays the alert sound
+	Beep() error
+}
+
+// Config holds the notification settings
+type Config struct {
+	Backend        string  `json:"backend"`          // One of the Backend* constants
+	LogFile        string  `json:"log_file"`         // Log file path for the file backend
+	Sound          bool    `json:"sound"`            // Whether alert sounds are played
+	BeepFrequency  float64 `json:"beep_frequency"`   // Alert sound frequency in Hz
+	BeepDurationMs int     `json:"beep_duration_ms"` // Alert sound length in milliseconds
+	RepeatCount    int     `json:"repeat_count"`     // Alert repetitions after a Pomodoro (0 = until dismissed)
+	SnoozeMinutes  int     `json:"snooze_minutes"`   // How long a snoozed alert stays quiet
+}
+
+// DefaultConfig returns the default notification settings
+func DefaultConfig() Config {
+	return Config{
+		Backend:        BackendDesktop,
+		Sound:          true,
+		BeepFrequency:  880.0,
+		BeepDurationMs: 500,
+		RepeatCount:    0,
+		SnoozeMinutes:  5,
+	}
+}
+
+// New creates the notifier selected by cfg.Backend
+func New(cfg Config) (Notifier, error) {
+	switch cfg.Backend {
+	case BackendDesktop, "":
+		return NewDesktopNotifier(cfg), nil
+	case BackendFile:
+		if cfg.LogFile == "" {
+			return nil, fmt.Errorf("notification backend %q requires log_file", BackendFile)
+		}
+		return NewFileNotifier(cfg.LogFile), nil
+	case BackendNone:
+		return NopNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification backend: %s", cfg.Backend)
+	}
+}
+
+// DesktopNotifier shows native desktop notifications via beeep
+type DesktopNotifier struct {
+	sound     bool
+	frequency float64
+	duration  int
+}
+
+// NewDesktopNotifier creates a DesktopNotifier
+func NewDesktopNotifier(cfg Config) *DesktopNotifier {
+	beeep.AppName = "koto"
+	return &DesktopNotifier{
+		sound:     cfg.Sound,
+		frequency: cfg.BeepFrequency,
+		duration:  cfg.BeepDurationMs,
+	}
+}
+
+// Notify shows a desktop notification
+func (n *DesktopNotifier) Notify(title, message string) error {
+	return beeep.Notify(title, message, "")
+}
+
+// Beep plays the alert sound unless sound is disabled
+func (n *DesktopNotifier) Beep() error {
+	if !n.sound {
+		return nil
+	}
+	return beeep.Beep(n.frequency, n.duration)
+}
+
+// NopNotifier discards all notifications
+type NopNotifier struct{}
+
+// Notify does nothing
+func (NopNotifier) Notify(title, message string) error { return nil }
+
+// Beep does nothing
+func (NopNotifier) Beep() error { return nil }
+
+// FileNotifier appends notifications to a log file, one per line
+// Lines are tab separated: timestamp, kind ("notify" or "beep"), title, message.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier creates a FileNotifier writing to path
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+// Notify appends a notification line
+func (n *FileNotifier) Notify(title, message string) error {
+	return n.write(fmt.Sprintf("notify\t%s\t%s", title, message))
+}
+
+// Beep appends a beep line
+func (n *FileNotifier) Beep() error {
+	return n.write("beep")
+}
+
+// write appends a timestamped line to the log file
+func (n *FileNotifier) write(line string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification log: %w", err)
+	}
+	defer func() {
+		_ = file.Close() // Ignore close error, the write result is more important
+	}()
+
+	if _, err := fmt.Fprintf(file, "%s\t%s\n", time.Now().Format(time.RFC3339), line); err != nil {
+		return fmt.Errorf("failed to write notification log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	n := NewFileNotifier(path)
+
+	if err := n.Notify("Pomodoro complete", "25 minutes recorded"); err != nil {
+		t.Fatalf("failed to notify: %v", err)
+	}
+	if err := n.Beep(); err != nil {
+		t.Fatalf("failed to beep: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), data)
+	}
+	if !strings.HasSuffix(lines[0], "\tnotify\tPomodoro complete\t25 minutes recorded") {
+		t.Errorf("unexpected notify line: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "\tbeep") {
+		t.Errorf("unexpected beep line: %q", lines[1])
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    string
+		wantErr bool
+	}{
+		{"default is desktop", Config{}, "*notify.DesktopNotifier", false},
+		{"none", Config{Backend: BackendNone}, "notify.NopNotifier", false},
+		{"file", Config{Backend: BackendFile, LogFile: "/tmp/koto.log"}, "*notify.FileNotifier", false},
+		{"file without path", Config{Backend: BackendFile}, "", true},
+		{"unknown", Config{Backend: "pager"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("New(%+v) expected error", tt.cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%+v) unexpected error: %v", tt.cfg, err)
+			}
+			if got := fmt.Sprintf("%T", n); got != tt.want {
+				t.Errorf("New(%+v) = %s; expected %s", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDesktopNotifier_BeepWithoutSound(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sound = false
+
+	if err := NewDesktopNotifier(cfg).Beep(); err != nil {
+		t.Errorf("Beep() with sound disabled returned %v", err)
+	}
+}
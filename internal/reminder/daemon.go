@@ -0,0 +1,114 @@
+// Package reminder implements the background reminder daemon
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/syeeel/koto-cli-go/internal/notify"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+// Daemon periodically checks for due todos and reminders and sends notifications
+type Daemon struct {
+	service  *service.TodoService
+	notifier notify.Notifier
+	offsets  []int         // Minutes before the due date to remind at
+	interval time.Duration // Time between checks
+	log      io.Writer     // Destination of progress messages
+	now      func() time.Time
+}
+
+// NewDaemon creates a reminder daemon
+func NewDaemon(svc *service.TodoService, notifier notify.Notifier, offsets []int, interval time.Duration, log io.Writer) *Daemon {
+	return &Daemon{
+		service:  svc,
+		notifier: notifier,
+		offsets:  offsets,
+		interval: interval,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// Run checks for reminders immediately and then on every interval until ctx is cancelled
+// Failed checks are logged and retried on the next interval; cancellation is not an error.
+func (d *Daemon) Run(ctx context.Context) error {
+	d.logf("koto reminder daemon started (checking every %s)", d.interval)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Check(ctx); err != nil && ctx.Err() == nil {
+			d.logf("reminder check failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logf("koto reminder daemon stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check sends every reminder that is due and returns how many were sent
+// This covers both due date reminders and explicit reminders set on todos.
+// Reminders are claimed before sending so that a TUI running alongside does
+// not send them too; the claim of a reminder that fails to send is released,
+// so that the next check retries it.
+func (d *Daemon) Check(ctx context.Context) (int, error) {
+	now := d.now()
+
+	dueReminders, err := d.service.ClaimDueReminders(ctx, now, d.offsets)
+	if err != nil {
+		return 0, err
+	}
+	todoReminders, err := d.service.ClaimReminders(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, r := range dueReminders {
+		if d.send(r.Todo.ID, r.Title(), r.Message()) {
+			sent++
+		} else if err := d.service.ReleaseDueReminder(ctx, r); err != nil {
+			d.logf("failed to release reminder for todo #%d: %v", r.Todo.ID, err)
+		}
+	}
+	for _, r := range todoReminders {
+		if d.send(r.TodoID, "🔔 Reminder", r.Message()) {
+			sent++
+		} else if err := d.service.ReleaseReminder(ctx, r.ID); err != nil {
+			d.logf("failed to release reminder for todo #%d: %v", r.TodoID, err)
+		}
+	}
+
+	if sent > 0 {
+		_ = d.notifier.Beep() // Ignore errors - a missing sound should not stop the daemon
+	}
+
+	return sent, nil
+}
+
+// send delivers a single notification, logs the outcome and reports whether it was sent
+func (d *Daemon) send(todoID int64, title, message string) bool {
+	if err := d.notifier.Notify(title, message); err != nil {
+		d.logf("failed to send reminder for todo #%d: %v", todoID, err)
+		return false
+	}
+	d.logf("%s: %s", title, message)
+	return true
+}
+
+// logf writes a timestamped line to the daemon log
+func (d *Daemon) logf(format string, args ...any) {
+	if d.log == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(d.log, "%s %s\n", d.now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
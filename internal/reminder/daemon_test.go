@@ -0,0 +1,195 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/notify"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+func setupDaemon(t *testing.T) (*Daemon, *service.TodoService, string) {
+	t.Helper()
+
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = repo.Close()
+	})
+
+	svc := service.NewTodoService(repo)
+	logPath := filepath.Join(t.TempDir(), "notifications.log")
+	d := NewDaemon(svc, notify.NewFileNotifier(logPath), []int{60}, time.Minute, nil)
+	return d, svc, logPath
+}
+
+func TestDaemon_Check(t *testing.T) {
+	d, svc, logPath := setupDaemon(t)
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	d.now = func() time.Time { return now }
+
+	due := now.Add(30 * time.Minute)
+	if _, err := svc.AddTodo(ctx, "Send report", "", model.PriorityHigh, &due); err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+
+	sent, err := d.Check(ctx)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("expected 1 reminder, got %d", sent)
+	}
+
+	// The same reminder is not sent twice
+	if sent, err := d.Check(ctx); err != nil || sent != 0 {
+		t.Errorf("expected no reminder on second check, got %d (%v)", sent, err)
+	}
+
+	// An overdue reminder follows once the due date has passed
+	now = due.Add(time.Minute)
+	if sent, err := d.Check(ctx); err != nil || sent != 1 {
+		t.Errorf("expected overdue reminder, got %d (%v)", sent, err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read notification log: %v", err)
+	}
+	log := string(data)
+	if !strings.Contains(log, "Todo due in 1h") || !strings.Contains(log, "Todo overdue") || !strings.Contains(log, "Send report") {
+		t.Errorf("unexpected notifications: %q", log)
+	}
+}
+
+func TestDaemon_CheckTodoReminders(t *testing.T) {
+	d, svc, logPath := setupDaemon(t)
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	d.now = func() time.Time { return now }
+
+	todo, err := svc.AddTodo(ctx, "Call the bank", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+	if _, err := svc.AddReminder(ctx, todo.ID, now.Add(30*time.Minute), now); err != nil {
+		t.Fatalf("failed to add reminder: %v", err)
+	}
+
+	if sent, err := d.Check(ctx); err != nil || sent != 0 {
+		t.Errorf("expected no reminder before its time, got %d (%v)", sent, err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if sent, err := d.Check(ctx); err != nil || sent != 1 {
+		t.Errorf("expected 1 reminder, got %d (%v)", sent, err)
+	}
+	if sent, err := d.Check(ctx); err != nil || sent != 0 {
+		t.Errorf("expected reminder to be sent once, got %d (%v)", sent, err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read notification log: %v", err)
+	}
+	if !strings.Contains(string(data), "Reminder\t#1 Call the bank") {
+		t.Errorf("unexpected notifications: %q", data)
+	}
+}
+
+// failingNotifier fails to notify while down is set and records the notifications sent otherwise
+type failingNotifier struct {
+	down bool
+	sent []string
+}
+
+func (n *failingNotifier) Notify(title, message string) error {
+	if n.down {
+		return errors.New("notification service unavailable")
+	}
+	n.sent = append(n.sent, title+": "+message)
+	return nil
+}
+
+func (n *failingNotifier) Beep() error {
+	return nil
+}
+
+func TestDaemon_CheckRetriesFailedReminders(t *testing.T) {
+	d, svc, _ := setupDaemon(t)
+	notifier := &failingNotifier{down: true}
+	d.notifier = notifier
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	d.now = func() time.Time { return now }
+
+	due := now.Add(30 * time.Minute)
+	if _, err := svc.AddTodo(ctx, "Send report", "", model.PriorityHigh, &due); err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+	todo, err := svc.AddTodo(ctx, "Call the bank", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+	if _, err := svc.AddReminder(ctx, todo.ID, now.Add(time.Minute), now.Add(-time.Minute)); err != nil {
+		t.Fatalf("failed to add reminder: %v", err)
+	}
+	now = now.Add(time.Minute)
+
+	// Failed notifications are not counted as sent
+	if sent, err := d.Check(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected no reminder to be sent, got %d (%v)", sent, err)
+	}
+
+	// Both are retried once notifications work again, and sent only once
+	notifier.down = false
+	if sent, err := d.Check(ctx); err != nil || sent != 2 {
+		t.Fatalf("expected both reminders to be retried, got %d (%v)", sent, err)
+	}
+	if sent, err := d.Check(ctx); err != nil || sent != 0 {
+		t.Errorf("expected the reminders to be sent once, got %d (%v)", sent, err)
+	}
+	if len(notifier.sent) != 2 {
+		t.Errorf("expected 2 notifications, got %q", notifier.sent)
+	}
+}
+
+func TestDaemon_RunStopsOnCancel(t *testing.T) {
+	d, _, _ := setupDaemon(t)
+	var out bytes.Buffer
+	d.log = &out
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Run(ctx)
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected clean exit, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop after cancellation")
+	}
+
+	if !strings.Contains(out.String(), "stopped") {
+		t.Errorf("expected stop message in log, got %q", out.String())
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/syeeel/koto-cli-go/internal/model"
 )
@@ -17,21 +18,166 @@ type TodoRepository interface {
 	// GetAll retrieves all todos
 	GetAll(ctx context.Context) ([]*model.Todo, error)
 
+	// GetPage retrieves up to limit todos that are not deferred at now, skipping the first offset (newest first)
+	GetPage(ctx context.Context, now time.Time, offset, limit int) ([]*model.Todo, error)
+
+	// SearchTodos retrieves up to limit todos that are not archived whose title contains every term,
+	// or whose ID starts with the only term (newest first); with fuzzy, the characters of a term
+	// only have to appear in order in the title
+	SearchTodos(ctx context.Context, terms []string, fuzzy bool, limit int) ([]*model.Todo, error)
+
+	// CountVisible counts the todos that are not deferred at now and the deferred ones
+	CountVisible(ctx context.Context, now time.Time) (visible, deferred int, err error)
+
 	// GetByStatus retrieves todos by status
 	GetByStatus(ctx context.Context, status model.TodoStatus) ([]*model.Todo, error)
 
 	// Update updates a todo
 	Update(ctx context.Context, todo *model.Todo) error
 
-	// Delete deletes a todo by ID
+	// UpdateMany updates several todos in a single transaction
+	UpdateMany(ctx context.Context, todos []*model.Todo) error
+
+	// Delete moves a todo to the trash (soft delete)
 	Delete(ctx context.Context, id int64) error
 
+	// DeleteMany moves several todos to the trash in a single transaction
+	DeleteMany(ctx context.Context, ids []int64) error
+
+	// GetDeleted retrieves the todos in the trash (most recently deleted first)
+	GetDeleted(ctx context.Context) ([]*model.Todo, error)
+
+	// Undelete restores a todo from the trash
+	Undelete(ctx context.Context, id int64) error
+
+	// Purge permanently deletes a todo in the trash
+	Purge(ctx context.Context, id int64) error
+
+	// GetArchived retrieves the archived todos matching query (most recently archived first)
+	GetArchived(ctx context.Context, query string) ([]*model.Todo, error)
+
+	// ArchiveCompletedBefore archives the todos completed before the given time and returns their IDs
+	ArchiveCompletedBefore(ctx context.Context, before time.Time) ([]int64, error)
+
+	// PurgeDeletedBefore permanently deletes the todos moved to the trash before the given time
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error)
+
 	// MarkAsCompleted marks a todo as completed
 	MarkAsCompleted(ctx context.Context, id int64) error
 
 	// AddWorkDuration adds work duration (in minutes) to a todo
 	AddWorkDuration(ctx context.Context, id int64, minutes int) error
 
+	// AddWorkLog records a work log entry and applies its minutes to the todo's total
+	AddWorkLog(ctx context.Context, log *model.WorkLog) error
+
+	// GetWorkLog retrieves a work log entry by ID
+	GetWorkLog(ctx context.Context, id int64) (*model.WorkLog, error)
+
+	// GetWorkLogs retrieves all work log entries of a todo (newest first)
+	GetWorkLogs(ctx context.Context, todoID int64) ([]*model.WorkLog, error)
+
+	// GetDailyWork aggregates work log minutes per todo and day for [from, to)
+	GetDailyWork(ctx context.Context, from, to time.Time) ([]model.DailyWork, error)
+
+	// CountCompletedByDay counts todos completed per day for [from, to)
+	CountCompletedByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error)
+
+	// CountPomodorosByDay counts finished Pomodoros per day for [from, to)
+	CountPomodorosByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error)
+
+	// SumWorkMinutes sums the work log minutes logged during [from, to)
+	SumWorkMinutes(ctx context.Context, from, to time.Time) (int, error)
+
+	// SumWorkMinutesByDay sums work log minutes per day for [from, to)
+	SumWorkMinutesByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error)
+
+	// GetAverageCompletionTime returns the average time from creation to completion
+	// and the number of completed todos it is based on
+	GetAverageCompletionTime(ctx context.Context) (time.Duration, int, error)
+
+	// CountOpenByPriority counts pending todos per priority
+	CountOpenByPriority(ctx context.Context) (map[model.Priority]int, error)
+
+	// GetCompletionDays retrieves every distinct day on which a todo was completed
+	GetCompletionDays(ctx context.Context) ([]time.Time, error)
+
+	// GetPendingDueBefore retrieves pending todos due before the given time (earliest first)
+	GetPendingDueBefore(ctx context.Context, before time.Time) ([]*model.Todo, error)
+
+	// MarkReminderSent records that a due date reminder was sent
+	// Returns false if the reminder had already been recorded
+	MarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) (bool, error)
+
+	// UnmarkReminderSent forgets that a due date reminder was sent, so that it is sent again
+	UnmarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) error
+
+	// AddReminder creates a reminder for a todo
+	AddReminder(ctx context.Context, reminder *model.Reminder) error
+
+	// GetReminder retrieves a reminder by ID
+	GetReminder(ctx context.Context, id int64) (*model.Reminder, error)
+
+	// GetReminders retrieves the active (not dismissed) reminders of a todo (earliest first)
+	GetReminders(ctx context.Context, todoID int64) ([]*model.Reminder, error)
+
+	// GetDueReminders retrieves active reminders of pending todos due at or before now
+	GetDueReminders(ctx context.Context, now time.Time) ([]*model.Reminder, error)
+
+	// ClaimReminder marks a reminder as sent; returns false if it was already sent
+	ClaimReminder(ctx context.Context, id int64) (bool, error)
+
+	// ReleaseReminder marks a claimed reminder as not sent, so that it is sent again
+	ReleaseReminder(ctx context.Context, id int64) error
+
+	// RescheduleReminder moves a reminder to a new time so that it is sent again
+	RescheduleReminder(ctx context.Context, id int64, remindAt time.Time) error
+
+	// DismissReminder dismisses a reminder
+	DismissReminder(ctx context.Context, id int64) error
+
+	// DeleteReminders deletes the active reminders of a todo and returns how many were deleted
+	DeleteReminders(ctx context.Context, todoID int64) (int, error)
+
+	// GetStopwatch retrieves the running stopwatch (nil if none is running)
+	GetStopwatch(ctx context.Context) (*model.Stopwatch, error)
+
+	// SetStopwatch stores the running stopwatch, replacing any previous one
+	SetStopwatch(ctx context.Context, stopwatch *model.Stopwatch) error
+
+	// ClearStopwatch removes the running stopwatch
+	ClearStopwatch(ctx context.Context) error
+
+	// RestoreTodo writes a todo back with its original ID, recreating it if it was deleted
+	RestoreTodo(ctx context.Context, todo *model.Todo) error
+
+	// AddUndoEntry appends a change to the undo history, discarding undone changes
+	AddUndoEntry(ctx context.Context, entry *model.UndoEntry) error
+
+	// AddUndoEntries appends changes that are undone together, discarding undone changes
+	AddUndoEntries(ctx context.Context, entries []*model.UndoEntry) error
+
+	// GetUndoGroup retrieves the changes of a bulk change (oldest first)
+	GetUndoGroup(ctx context.Context, groupID int64) ([]*model.UndoEntry, error)
+
+	// GetUndoEntry retrieves the latest change that is still in effect
+	GetUndoEntry(ctx context.Context) (*model.UndoEntry, error)
+
+	// GetRedoEntry retrieves the change that was undone most recently
+	GetRedoEntry(ctx context.Context) (*model.UndoEntry, error)
+
+	// SetUndoEntryUndone marks a change as undone, or as in effect again
+	SetUndoEntryUndone(ctx context.Context, id int64, undone bool) error
+
+	// ApplyUndo makes the writes of an undo or redo and marks its changes in a single transaction
+	ApplyUndo(ctx context.Context, change *model.UndoChange) error
+
+	// AddTodoEvents appends field-level changes to the audit history
+	AddTodoEvents(ctx context.Context, events []*model.TodoEvent) error
+
+	// GetTodoEvents retrieves the audit history of a todo (oldest first)
+	GetTodoEvents(ctx context.Context, todoID int64) ([]*model.TodoEvent, error)
+
 	// Close closes the repository connection
 	Close() error
 }
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
@@ -23,6 +25,11 @@ CREATE TABLE IF NOT EXISTS todos (
     priority INTEGER NOT NULL DEFAULT 0,
     due_date DATETIME,
     work_duration INTEGER NOT NULL DEFAULT 0,
+    completed_at DATETIME,
+    start_date DATETIME,
+    deleted_at DATETIME,
+    archived_at DATETIME,
+    tags TEXT NOT NULL DEFAULT '',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -30,11 +37,97 @@ CREATE TABLE IF NOT EXISTS todos (
 CREATE INDEX IF NOT EXISTS idx_todos_status ON todos(status);
 CREATE INDEX IF NOT EXISTS idx_todos_due_date ON todos(due_date);
 CREATE INDEX IF NOT EXISTS idx_todos_created_at ON todos(created_at);
+
+CREATE TABLE IF NOT EXISTS work_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    minutes INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT 'manual',
+    corrects_id INTEGER,
+    logged_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_logs_todo_id ON work_logs(todo_id);
+CREATE INDEX IF NOT EXISTS idx_work_logs_logged_at ON work_logs(logged_at);
+
+CREATE TABLE IF NOT EXISTS stopwatch (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    todo_id INTEGER NOT NULL,
+    started_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reminders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    remind_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    dismissed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_todo_id ON reminders(todo_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_remind_at ON reminders(remind_at);
+
+CREATE TABLE IF NOT EXISTS undo_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    todo_id INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    before_json TEXT,
+    after_json TEXT,
+    work_log_id INTEGER,
+    minutes_before INTEGER NOT NULL DEFAULT 0,
+    minutes_after INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    undone_at DATETIME,
+    group_id INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS todo_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_events_todo_id ON todo_events(todo_id);
+
+CREATE TABLE IF NOT EXISTS sent_reminders (
+    todo_id INTEGER NOT NULL,
+    due_unix INTEGER NOT NULL,
+    offset_minutes INTEGER NOT NULL,
+    sent_at DATETIME NOT NULL,
+    PRIMARY KEY (todo_id, due_unix, offset_minutes)
+);
 `
 
+// todoColumns lists the todos columns in the order expected by scanTodo
+const todoColumns = `id, title, description, status, priority, due_date, work_duration, completed_at, start_date, deleted_at, archived_at, tags, created_at, updated_at`
+
+// backfillNote is the note of work logs backfilled from pre-existing totals
+// They sum up several Pomodoros, so they are not counted as single Pomodoros
+const backfillNote = "Recorded before work logs were introduced"
+
+// undoLogLimit is the number of changes kept in the undo history
+const undoLogLimit = 200
+
+// undoColumns lists the undo_log columns in the order expected by scanUndoEntry
+const undoColumns = `id, action, todo_id, title, before_json, after_json, work_log_id, minutes_before, minutes_after, created_at, undone_at, group_id`
+
 var (
 	// ErrTodoNotFound is returned when a todo is not found
 	ErrTodoNotFound = errors.New("todo not found")
+	// ErrWorkLogNotFound is returned when a work log entry is not found
+	ErrWorkLogNotFound = errors.New("work log entry not found")
+	// ErrReminderNotFound is returned when a reminder is not found
+	ErrReminderNotFound = errors.New("reminder not found")
+	// ErrUndoEntryNotFound is returned when there is no change to undo or redo
+	ErrUndoEntryNotFound = errors.New("undo entry not found")
 )
 
 // SQLiteRepository implements TodoRepository using SQLite
@@ -54,6 +147,10 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 	if dbPath != ":memory:" {
 		// Ignore error if file doesn't exist yet (it will be created by SQLite)
 		_ = os.Chmod(dbPath, 0600)
+	} else {
+		// Every connection to ":memory:" opens a separate database,
+		// so transactions must share the single connection
+		db.SetMaxOpenConns(1)
 	}
 
 	// Initialize schema
@@ -83,24 +180,118 @@ func initSchema(db *sql.DB) error {
 // applyMigrations applies database migrations for existing databases
 func applyMigrations(db *sql.DB) error {
 	// Migration 002: Add work_duration column (for Pomodoro feature)
+	if err := addColumnIfMissing(db, "todos", "work_duration", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Migration 003: Backfill work_logs from existing work_duration totals
+	// so time tracked before work logs existed keeps an auditable entry
+	_, err := db.Exec(`
+		INSERT INTO work_logs (todo_id, minutes, note, source, logged_at, created_at)
+		SELECT id, work_duration, ?, ?, updated_at, updated_at
+		FROM todos
+		WHERE work_duration > 0 AND id NOT IN (SELECT todo_id FROM work_logs)
+	`, backfillNote, model.WorkLogSourcePomodoro)
+	if err != nil {
+		return fmt.Errorf("failed to backfill work logs: %w", err)
+	}
+
+	// Migration 005: Add completed_at column (for productivity statistics)
+	// Completed todos created before this migration use their last update time
+	if err := addColumnIfMissing(db, "todos", "completed_at", "DATETIME"); err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		UPDATE todos SET completed_at = updated_at
+		WHERE status = ? AND completed_at IS NULL
+	`, model.StatusCompleted)
+	if err != nil {
+		return fmt.Errorf("failed to backfill completed_at: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_completed_at ON todos(completed_at)`); err != nil {
+		return fmt.Errorf("failed to create completed_at index: %w", err)
+	}
+
+	// Migration 008: Add start_date column (for deferring todos)
+	if err := addColumnIfMissing(db, "todos", "start_date", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_start_date ON todos(start_date)`); err != nil {
+		return fmt.Errorf("failed to create start_date index: %w", err)
+	}
+
+	// Migration 010: Add deleted_at column (for the trash)
+	if err := addColumnIfMissing(db, "todos", "deleted_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos(deleted_at)`); err != nil {
+		return fmt.Errorf("failed to create deleted_at index: %w", err)
+	}
+
+	// Migration 011: Add archived_at column (for the archive)
+	// The partial index covers the default list, which skips archived and deleted todos
+	if err := addColumnIfMissing(db, "todos", "archived_at", "DATETIME"); err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_todos_archived_at ON todos(archived_at);
+		CREATE INDEX IF NOT EXISTS idx_todos_active ON todos(created_at)
+			WHERE archived_at IS NULL AND deleted_at IS NULL;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create archive indexes: %w", err)
+	}
+
+	// Migration 013: Add group_id column to undo_log (for bulk changes undone together)
+	if err := addColumnIfMissing(db, "undo_log", "group_id", "INTEGER"); err != nil {
+		return err
+	}
+
+	// Migration 014: Add tags column (space-separated tags)
+	if err := addColumnIfMissing(db, "todos", "tags", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// utcTime returns an SQL expression reading the time stored in column as a UTC
+// "YYYY-MM-DD HH:MM:SS.SSS" string, which compares correctly with utcParam values
+// The driver stores times as Go time strings ("2006-01-02 15:04:05.999999999 -0700 MST"),
+// whose local clock times do not compare across zone offsets and which SQLite
+// cannot parse, so the offset is moved next to the time ("...05.999999999-07:00")
+// first. Times in SQLite's own format, like CURRENT_TIMESTAMP defaults, are taken as is.
+func utcTime(column string) string {
+	space := fmt.Sprintf("instr(substr(%s, 20), ' ')", column)
+	return fmt.Sprintf(`strftime('%%Y-%%m-%%d %%H:%%M:%%f', CASE WHEN %[2]s > 0
+		THEN substr(%[1]s, 1, 18 + %[2]s) || substr(%[1]s, 20 + %[2]s, 3) || ':' || substr(%[1]s, 23 + %[2]s, 2)
+		ELSE %[1]s END)`, column, space)
+}
+
+// utcParam formats t for comparisons with utcTime expressions
+// SQLite rounds the stored times to milliseconds, so t is rounded the same way.
+func utcParam(t time.Time) string {
+	return t.UTC().Round(time.Millisecond).Format("2006-01-02 15:04:05.000")
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
 	// Check if column exists
 	var columnExists bool
 	err := db.QueryRow(`
 		SELECT COUNT(*) > 0
-		FROM pragma_table_info('todos')
-		WHERE name='work_duration'
-	`).Scan(&columnExists)
+		FROM pragma_table_info(?)
+		WHERE name = ?
+	`, table, column).Scan(&columnExists)
 	if err != nil {
-		return fmt.Errorf("failed to check work_duration column existence: %w", err)
+		return fmt.Errorf("failed to check %s column existence: %w", column, err)
 	}
 
 	// Add column if it doesn't exist
 	if !columnExists {
-		_, err = db.Exec(`
-			ALTER TABLE todos ADD COLUMN work_duration INTEGER NOT NULL DEFAULT 0
-		`)
+		_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
 		if err != nil {
-			return fmt.Errorf("failed to add work_duration column: %w", err)
+			return fmt.Errorf("failed to add %s column: %w", column, err)
 		}
 	}
 
@@ -110,8 +301,8 @@ func applyMigrations(db *sql.DB) error {
 // Create creates a new todo item
 func (r *SQLiteRepository) Create(ctx context.Context, todo *model.Todo) error {
 	query := `
-		INSERT INTO todos (title, description, status, priority, due_date, work_duration, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO todos (title, description, status, priority, due_date, work_duration, completed_at, start_date, archived_at, tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -121,6 +312,10 @@ func (r *SQLiteRepository) Create(ctx context.Context, todo *model.Todo) error {
 		todo.Priority,
 		todo.DueDate,
 		todo.WorkDuration,
+		todo.CompletedAt,
+		todo.StartDate,
+		todo.ArchivedAt,
+		joinTags(todo.Tags),
 		todo.CreatedAt,
 		todo.UpdatedAt,
 	)
@@ -140,26 +335,12 @@ func (r *SQLiteRepository) Create(ctx context.Context, todo *model.Todo) error {
 // GetByID retrieves a todo by ID
 func (r *SQLiteRepository) GetByID(ctx context.Context, id int64) (*model.Todo, error) {
 	query := `
-		SELECT id, title, description, status, priority, due_date, work_duration, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	todo := &model.Todo{}
-	var dueDate sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&todo.ID,
-		&todo.Title,
-		&todo.Description,
-		&todo.Status,
-		&todo.Priority,
-		&dueDate,
-		&todo.WorkDuration,
-		&todo.CreatedAt,
-		&todo.UpdatedAt,
-	)
-
+	todo, err := scanTodo(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, ErrTodoNotFound
 	}
@@ -167,19 +348,16 @@ func (r *SQLiteRepository) GetByID(ctx context.Context, id int64) (*model.Todo,
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
 
-	if dueDate.Valid {
-		todo.DueDate = &dueDate.Time
-	}
-
 	return todo, nil
 }
 
 // GetAll retrieves all todos
 func (r *SQLiteRepository) GetAll(ctx context.Context) ([]*model.Todo, error) {
 	query := `
-		SELECT id, title, description, status, priority, due_date, work_duration, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos
-		ORDER BY created_at DESC
+		WHERE archived_at IS NULL AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -193,12 +371,98 @@ func (r *SQLiteRepository) GetAll(ctx context.Context) ([]*model.Todo, error) {
 	return r.scanTodos(rows)
 }
 
+// GetPage retrieves up to limit todos that are not deferred at now, skipping the first offset
+// Todos are ordered like GetAll, so the pages add up to the list without the deferred todos.
+func (r *SQLiteRepository) GetPage(ctx context.Context, now time.Time, offset, limit int) ([]*model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE archived_at IS NULL AND deleted_at IS NULL AND (start_date IS NULL OR ` + utcTime("start_date") + ` <= ?)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, utcParam(now), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query todo page: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanTodos(rows)
+}
+
+// SearchTodos retrieves up to limit todos that are not archived matching the search terms
+// Titles match case-insensitively (ASCII only, like LIKE). Without terms the newest todos are returned.
+func (r *SQLiteRepository) SearchTodos(ctx context.Context, terms []string, fuzzy bool, limit int) ([]*model.Todo, error) {
+	var conditions []string
+	var args []any
+	for _, term := range terms {
+		pattern := escapeLike(term)
+		if fuzzy {
+			chars := make([]string, 0, len(term))
+			for _, c := range term {
+				chars = append(chars, escapeLike(string(c)))
+			}
+			pattern = strings.Join(chars, "%")
+		}
+		conditions = append(conditions, `title LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+pattern+"%")
+	}
+
+	match := "1 = 1"
+	if len(conditions) > 0 {
+		match = strings.Join(conditions, " AND ")
+	}
+	if len(terms) == 1 && !fuzzy {
+		match = "(" + match + ` OR CAST(id AS TEXT) LIKE ? ESCAPE '\')`
+		args = append(args, escapeLike(terms[0])+"%")
+	}
+
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE archived_at IS NULL AND deleted_at IS NULL AND ` + match + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search todos: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanTodos(rows)
+}
+
+// CountVisible counts the todos that are not deferred at now and the deferred ones
+func (r *SQLiteRepository) CountVisible(ctx context.Context, now time.Time) (int, int, error) {
+	query := `
+		SELECT
+			COUNT(CASE WHEN start_date IS NULL OR ` + utcTime("start_date") + ` <= ? THEN 1 END),
+			COUNT(CASE WHEN ` + utcTime("start_date") + ` > ? THEN 1 END)
+		FROM todos
+		WHERE archived_at IS NULL AND deleted_at IS NULL
+	`
+
+	var visible, deferred int
+	if err := r.db.QueryRowContext(ctx, query, utcParam(now), utcParam(now)).Scan(&visible, &deferred); err != nil {
+		return 0, 0, fmt.Errorf("failed to count todos: %w", err)
+	}
+	return visible, deferred, nil
+}
+
 // GetByStatus retrieves todos by status
 func (r *SQLiteRepository) GetByStatus(ctx context.Context, status model.TodoStatus) ([]*model.Todo, error) {
 	query := `
-		SELECT id, title, description, status, priority, due_date, work_duration, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE status = ?
+		WHERE status = ? AND archived_at IS NULL AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -215,21 +479,54 @@ func (r *SQLiteRepository) GetByStatus(ctx context.Context, status model.TodoSta
 
 // Update updates a todo
 func (r *SQLiteRepository) Update(ctx context.Context, todo *model.Todo) error {
+	return updateTodo(ctx, r.db, todo)
+}
+
+// UpdateMany updates several todos in a single transaction
+// Nothing is updated if any of the todos does not exist.
+func (r *SQLiteRepository) UpdateMany(ctx context.Context, todos []*model.Todo) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	for _, todo := range todos {
+		if err := updateTodo(ctx, tx, todo); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit update: %w", err)
+	}
+
+	return nil
+}
+
+// updateTodo updates a todo with db, which may be a transaction
+func updateTodo(ctx context.Context, db execer, todo *model.Todo) error {
 	query := `
 		UPDATE todos
-		SET title = ?, description = ?, status = ?, priority = ?, due_date = ?, work_duration = ?, updated_at = ?
+		SET title = ?, description = ?, status = ?, priority = ?, due_date = ?, work_duration = ?, completed_at = ?, start_date = ?, archived_at = ?, tags = ?, updated_at = ?
 		WHERE id = ?
 	`
 
 	todo.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := db.ExecContext(ctx, query,
 		todo.Title,
 		todo.Description,
 		todo.Status,
 		todo.Priority,
 		todo.DueDate,
 		todo.WorkDuration,
+		todo.CompletedAt,
+		todo.StartDate,
+		todo.ArchivedAt,
+		joinTags(todo.Tags),
 		todo.UpdatedAt,
 		todo.ID,
 	)
@@ -249,11 +546,41 @@ func (r *SQLiteRepository) Update(ctx context.Context, todo *model.Todo) error {
 	return nil
 }
 
-// Delete deletes a todo by ID
+// Delete moves a todo to the trash by setting deleted_at
 func (r *SQLiteRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM todos WHERE id = ?`
+	return deleteTodo(ctx, r.db, id, time.Now())
+}
+
+// DeleteMany moves several todos to the trash in a single transaction
+// Nothing is deleted if any of the todos does not exist or is already in the trash.
+func (r *SQLiteRepository) DeleteMany(ctx context.Context, ids []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := deleteTodo(ctx, tx, id, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delete: %w", err)
+	}
+
+	return nil
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// deleteTodo moves a todo to the trash with db, which may be a transaction
+func deleteTodo(ctx context.Context, db execer, id int64, deletedAt time.Time) error {
+	query := `UPDATE todos SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+
+	result, err := db.ExecContext(ctx, query, deletedAt, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
@@ -270,17 +597,33 @@ func (r *SQLiteRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// MarkAsCompleted marks a todo as completed
-func (r *SQLiteRepository) MarkAsCompleted(ctx context.Context, id int64) error {
+// GetDeleted retrieves the todos in the trash (most recently deleted first)
+func (r *SQLiteRepository) GetDeleted(ctx context.Context) ([]*model.Todo, error) {
 	query := `
-		UPDATE todos
-		SET status = ?, updated_at = ?
-		WHERE id = ?
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
 	`
 
-	result, err := r.db.ExecContext(ctx, query, model.StatusCompleted, time.Now(), id)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to mark todo as completed: %w", err)
+		return nil, fmt.Errorf("failed to query deleted todos: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanTodos(rows)
+}
+
+// Undelete restores a todo from the trash
+func (r *SQLiteRepository) Undelete(ctx context.Context, id int64) error {
+	query := `UPDATE todos SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to restore todo: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -295,60 +638,128 @@ func (r *SQLiteRepository) MarkAsCompleted(ctx context.Context, id int64) error
 	return nil
 }
 
-// Close closes the repository connection
-func (r *SQLiteRepository) Close() error {
-	return r.db.Close()
+// Purge permanently deletes a todo in the trash with its work log, reminders and undo history
+func (r *SQLiteRepository) Purge(ctx context.Context, id int64) error {
+	count, err := r.purgeWhere(ctx, `id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrTodoNotFound
+	}
+	return nil
 }
 
-// scanTodos is a helper function to scan multiple todo rows
-func (r *SQLiteRepository) scanTodos(rows *sql.Rows) ([]*model.Todo, error) {
-	var todos []*model.Todo
+// PurgeDeletedBefore permanently deletes the todos moved to the trash before the given time
+// Returns the number of purged todos
+func (r *SQLiteRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	return r.purgeWhere(ctx, `deleted_at IS NOT NULL AND `+utcTime("deleted_at")+` < ?`, utcParam(before))
+}
 
-	for rows.Next() {
-		todo := &model.Todo{}
-		var dueDate sql.NullTime
+// purgeWhere permanently deletes the todos matching condition and everything that refers to them
+func (r *SQLiteRepository) purgeWhere(ctx context.Context, condition string, args ...any) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
 
-		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Status,
-			&todo.Priority,
-			&dueDate,
-			&todo.WorkDuration,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan todo: %w", err)
+	selected := `SELECT id FROM todos WHERE ` + condition
+	for _, table := range []string{"work_logs", "reminders", "sent_reminders", "undo_log", "todo_events"} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE todo_id IN (%s)`, table, selected)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, fmt.Errorf("failed to purge %s: %w", table, err)
 		}
+	}
 
-		if dueDate.Valid {
-			todo.DueDate = &dueDate.Time
-		}
+	result, err := tx.ExecContext(ctx, `DELETE FROM todos WHERE `+condition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge todos: %w", err)
+	}
 
-		todos = append(todos, todo)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit purge: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetArchived retrieves the archived todos whose title or description contains query
+// (most recently archived first); an empty query returns every archived todo
+func (r *SQLiteRepository) GetArchived(ctx context.Context, query string) ([]*model.Todo, error) {
+	pattern := "%" + escapeLike(query) + "%"
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+todoColumns+`
+		FROM todos
+		WHERE archived_at IS NOT NULL AND deleted_at IS NULL
+		  AND (title LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')
+		ORDER BY archived_at DESC
+	`, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived todos: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanTodos(rows)
+}
+
+// ArchiveCompletedBefore archives the todos completed before the given time
+// Returns the IDs of the archived todos
+func (r *SQLiteRepository) ArchiveCompletedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE todos SET archived_at = ?
+		WHERE status = ? AND `+utcTime("completed_at")+` < ? AND archived_at IS NULL AND deleted_at IS NULL
+		RETURNING id
+	`, time.Now(), model.StatusCompleted, utcParam(before))
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive completed todos: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan archived todo: %w", err)
+		}
+		ids = append(ids, id)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating todos: %w", err)
+		return nil, fmt.Errorf("error iterating archived todos: %w", err)
 	}
 
-	return todos, nil
+	return ids, nil
 }
 
-// AddWorkDuration adds work duration (in minutes) to a todo
-func (r *SQLiteRepository) AddWorkDuration(ctx context.Context, id int64, minutes int) error {
+// escapeLike escapes the LIKE wildcards in s (for use with ESCAPE '\')
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// MarkAsCompleted marks a todo as completed
+func (r *SQLiteRepository) MarkAsCompleted(ctx context.Context, id int64) error {
 	query := `
 		UPDATE todos
-		SET work_duration = work_duration + ?,
-		    updated_at = ?
+		SET status = ?, completed_at = COALESCE(completed_at, ?), updated_at = ?
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, minutes, time.Now(), id)
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, model.StatusCompleted, now, now, id)
 	if err != nil {
-		return fmt.Errorf("failed to add work duration: %w", err)
+		return fmt.Errorf("failed to mark todo as completed: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -362,3 +773,1224 @@ func (r *SQLiteRepository) AddWorkDuration(ctx context.Context, id int64, minute
 
 	return nil
 }
+
+// Close closes the repository connection
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// scanTodo scans a single todo selected with todoColumns
+func scanTodo(row rowScanner) (*model.Todo, error) {
+	todo := &model.Todo{}
+	var dueDate, completedAt, startDate, deletedAt, archivedAt sql.NullTime
+	var tags string
+
+	err := row.Scan(
+		&todo.ID,
+		&todo.Title,
+		&todo.Description,
+		&todo.Status,
+		&todo.Priority,
+		&dueDate,
+		&todo.WorkDuration,
+		&completedAt,
+		&startDate,
+		&deletedAt,
+		&archivedAt,
+		&tags,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dueDate.Valid {
+		todo.DueDate = &dueDate.Time
+	}
+	if completedAt.Valid {
+		todo.CompletedAt = &completedAt.Time
+	}
+	if startDate.Valid {
+		todo.StartDate = &startDate.Time
+	}
+	if deletedAt.Valid {
+		todo.DeletedAt = &deletedAt.Time
+	}
+	if archivedAt.Valid {
+		todo.ArchivedAt = &archivedAt.Time
+	}
+	todo.Tags = splitTags(tags)
+
+	return todo, nil
+}
+
+// joinTags encodes tags for the tags column (tags never contain spaces)
+func joinTags(tags []string) string {
+	return strings.Join(tags, " ")
+}
+
+// splitTags decodes the tags column (nil if there are none)
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Fields(tags)
+}
+
+// scanTodos is a helper function to scan multiple todo rows
+func (r *SQLiteRepository) scanTodos(rows *sql.Rows) ([]*model.Todo, error) {
+	var todos []*model.Todo
+
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan todo: %w", err)
+		}
+
+		todos = append(todos, todo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+// AddWorkDuration adds work duration (in minutes) to a todo
+// The time is recorded as a Pomodoro work log entry
+func (r *SQLiteRepository) AddWorkDuration(ctx context.Context, id int64, minutes int) error {
+	return r.AddWorkLog(ctx, &model.WorkLog{
+		TodoID:   id,
+		Minutes:  minutes,
+		Source:   model.WorkLogSourcePomodoro,
+		LoggedAt: time.Now(),
+	})
+}
+
+// AddWorkLog records a work log entry and applies its minutes to the todo's total
+// Both changes are made in a single transaction so the total always matches the log
+func (r *SQLiteRepository) AddWorkLog(ctx context.Context, log *model.WorkLog) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	if err := addWorkLog(ctx, tx, log); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit work log: %w", err)
+	}
+
+	return nil
+}
+
+// addWorkLog records a work log entry and applies its minutes to the todo's total
+// with db, which should be a transaction
+func addWorkLog(ctx context.Context, db execer, log *model.WorkLog) error {
+	now := time.Now()
+	result, err := db.ExecContext(ctx, `
+		UPDATE todos
+		SET work_duration = work_duration + ?,
+		    updated_at = ?
+		WHERE id = ?
+	`, log.Minutes, now, log.TodoID)
+	if err != nil {
+		return fmt.Errorf("failed to add work duration: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrTodoNotFound
+	}
+
+	if log.LoggedAt.IsZero() {
+		log.LoggedAt = now
+	}
+	log.CreatedAt = now
+
+	result, err = db.ExecContext(ctx, `
+		INSERT INTO work_logs (todo_id, minutes, note, source, corrects_id, logged_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, log.TodoID, log.Minutes, log.Note, log.Source, log.CorrectsID, log.LoggedAt, log.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create work log: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	log.ID = id
+	return nil
+}
+
+// GetWorkLog retrieves a work log entry by ID
+func (r *SQLiteRepository) GetWorkLog(ctx context.Context, id int64) (*model.WorkLog, error) {
+	query := `
+		SELECT id, todo_id, minutes, note, source, corrects_id, logged_at, created_at
+		FROM work_logs
+		WHERE id = ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work log: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	logs, err := r.scanWorkLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logs) == 0 {
+		return nil, ErrWorkLogNotFound
+	}
+
+	return logs[0], nil
+}
+
+// GetWorkLogs retrieves all work log entries of a todo (newest first)
+func (r *SQLiteRepository) GetWorkLogs(ctx context.Context, todoID int64) ([]*model.WorkLog, error) {
+	query := `
+		SELECT id, todo_id, minutes, note, source, corrects_id, logged_at, created_at
+		FROM work_logs
+		WHERE todo_id = ?
+		ORDER BY logged_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query work logs: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanWorkLogs(rows)
+}
+
+// scanWorkLogs is a helper function to scan multiple work log rows
+func (r *SQLiteRepository) scanWorkLogs(rows *sql.Rows) ([]*model.WorkLog, error) {
+	var logs []*model.WorkLog
+
+	for rows.Next() {
+		log := &model.WorkLog{}
+		var correctsID sql.NullInt64
+
+		err := rows.Scan(
+			&log.ID,
+			&log.TodoID,
+			&log.Minutes,
+			&log.Note,
+			&log.Source,
+			&correctsID,
+			&log.LoggedAt,
+			&log.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan work log: %w", err)
+		}
+
+		if correctsID.Valid {
+			log.CorrectsID = &correctsID.Int64
+		}
+
+		logs = append(logs, log)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating work logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// localDate returns an SQL expression for the local date ("YYYY-MM-DD") of the time stored in column
+func localDate(column string) string {
+	return "date(" + utcTime(column) + ", 'localtime')"
+}
+
+// GetDailyWork aggregates work log minutes per todo and day for [from, to)
+// Days are taken from the local date the work was logged at
+func (r *SQLiteRepository) GetDailyWork(ctx context.Context, from, to time.Time) ([]model.DailyWork, error) {
+	query := `
+		SELECT w.todo_id, COALESCE(t.title, ''), ` + localDate("w.logged_at") + ` AS day, SUM(w.minutes)
+		FROM work_logs w
+		LEFT JOIN todos t ON t.id = w.todo_id
+		WHERE ` + utcTime("w.logged_at") + ` >= ? AND ` + utcTime("w.logged_at") + ` < ?
+		GROUP BY w.todo_id, day
+		HAVING SUM(w.minutes) != 0
+		ORDER BY day, w.todo_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, utcParam(from), utcParam(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily work: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	var entries []model.DailyWork
+	for rows.Next() {
+		var entry model.DailyWork
+		var day string
+		if err := rows.Scan(&entry.TodoID, &entry.Title, &day, &entry.Minutes); err != nil {
+			return nil, fmt.Errorf("failed to scan daily work: %w", err)
+		}
+
+		entry.Day, err = time.ParseInLocation("2006-01-02", day, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse work log day %q: %w", day, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily work: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountCompletedByDay counts todos completed per day for [from, to)
+func (r *SQLiteRepository) CountCompletedByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	query := `
+		SELECT ` + localDate("completed_at") + ` AS day, COUNT(*)
+		FROM todos
+		WHERE status = ? AND deleted_at IS NULL AND ` + utcTime("completed_at") + ` >= ? AND ` + utcTime("completed_at") + ` < ?
+		GROUP BY day
+		ORDER BY day
+	`
+
+	return r.queryDayCounts(ctx, query, model.StatusCompleted, utcParam(from), utcParam(to))
+}
+
+// CountPomodorosByDay counts finished Pomodoros per day for [from, to)
+// Every positive Pomodoro work log entry is one finished Pomodoro
+func (r *SQLiteRepository) CountPomodorosByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	query := `
+		SELECT ` + localDate("logged_at") + ` AS day, COUNT(*)
+		FROM work_logs
+		WHERE source = ? AND minutes > 0 AND note != ? AND ` + utcTime("logged_at") + ` >= ? AND ` + utcTime("logged_at") + ` < ?
+		GROUP BY day
+		ORDER BY day
+	`
+
+	return r.queryDayCounts(ctx, query, model.WorkLogSourcePomodoro, backfillNote, utcParam(from), utcParam(to))
+}
+
+// queryDayCounts runs a query returning (day, count) rows
+func (r *SQLiteRepository) queryDayCounts(ctx context.Context, query string, args ...any) ([]model.DayCount, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query day counts: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	var counts []model.DayCount
+	for rows.Next() {
+		var count model.DayCount
+		var day string
+		if err := rows.Scan(&day, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan day count: %w", err)
+		}
+
+		count.Day, err = time.ParseInLocation("2006-01-02", day, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse day %q: %w", day, err)
+		}
+
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating day counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SumWorkMinutes sums the work log minutes logged during [from, to)
+func (r *SQLiteRepository) SumWorkMinutes(ctx context.Context, from, to time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(minutes), 0)
+		FROM work_logs
+		WHERE ` + utcTime("logged_at") + ` >= ? AND ` + utcTime("logged_at") + ` < ?
+	`
+
+	var minutes int
+	if err := r.db.QueryRowContext(ctx, query, utcParam(from), utcParam(to)).Scan(&minutes); err != nil {
+		return 0, fmt.Errorf("failed to sum work minutes: %w", err)
+	}
+
+	return minutes, nil
+}
+
+// SumWorkMinutesByDay sums work log minutes per day for [from, to)
+// The minutes of each day are returned as its count
+func (r *SQLiteRepository) SumWorkMinutesByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	query := `
+		SELECT ` + localDate("logged_at") + ` AS day, SUM(minutes)
+		FROM work_logs
+		WHERE ` + utcTime("logged_at") + ` >= ? AND ` + utcTime("logged_at") + ` < ?
+		GROUP BY day
+		HAVING SUM(minutes) != 0
+		ORDER BY day
+	`
+
+	return r.queryDayCounts(ctx, query, utcParam(from), utcParam(to))
+}
+
+// GetAverageCompletionTime returns the average time from creation to completion
+// and the number of completed todos it is based on
+func (r *SQLiteRepository) GetAverageCompletionTime(ctx context.Context) (time.Duration, int, error) {
+	query := `
+		SELECT COALESCE(AVG(julianday(` + utcTime("completed_at") + `) - julianday(` + utcTime("created_at") + `)), 0),
+		       COUNT(*)
+		FROM todos
+		WHERE status = ? AND completed_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	var days float64
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, model.StatusCompleted).Scan(&days, &count); err != nil {
+		return 0, 0, fmt.Errorf("failed to get average completion time: %w", err)
+	}
+
+	// Stored times have millisecond precision, the rest is floating point noise
+	return time.Duration(days * float64(24*time.Hour)).Round(time.Millisecond), count, nil
+}
+
+// CountOpenByPriority counts pending todos per priority
+func (r *SQLiteRepository) CountOpenByPriority(ctx context.Context) (map[model.Priority]int, error) {
+	query := `
+		SELECT priority, COUNT(*)
+		FROM todos
+		WHERE status = ? AND deleted_at IS NULL
+		GROUP BY priority
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open todos: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	counts := make(map[model.Priority]int)
+	for rows.Next() {
+		var priority model.Priority
+		var count int
+		if err := rows.Scan(&priority, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan priority count: %w", err)
+		}
+		counts[priority] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating priority counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetCompletionDays retrieves every distinct day on which a todo was completed
+// Days are returned newest first
+func (r *SQLiteRepository) GetCompletionDays(ctx context.Context) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT ` + localDate("completed_at") + ` AS day
+		FROM todos
+		WHERE status = ? AND completed_at IS NOT NULL AND deleted_at IS NULL
+		ORDER BY day DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.StatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query completion days: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	var days []time.Time
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan completion day: %w", err)
+		}
+
+		parsed, err := time.ParseInLocation("2006-01-02", day, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse completion day %q: %w", day, err)
+		}
+
+		days = append(days, parsed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating completion days: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetPendingDueBefore retrieves pending todos due before the given time (earliest first)
+func (r *SQLiteRepository) GetPendingDueBefore(ctx context.Context, before time.Time) ([]*model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE status = ? AND deleted_at IS NULL AND due_date IS NOT NULL AND ` + utcTime("due_date") + ` < ?
+		ORDER BY ` + utcTime("due_date") + ` ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.StatusPending, utcParam(before))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos due before %v: %w", before, err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	return r.scanTodos(rows)
+}
+
+// MarkReminderSent records that a due date reminder was sent
+// Reminders are keyed by todo, due date and offset, so changing the due date
+// re-arms them. Returns false if the reminder had already been recorded.
+func (r *SQLiteRepository) MarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO sent_reminders (todo_id, due_unix, offset_minutes, sent_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, todoID, dueDate.Unix(), offsetMinutes, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to record sent reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UnmarkReminderSent forgets that a due date reminder was sent, so that it is sent again
+func (r *SQLiteRepository) UnmarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) error {
+	query := `
+		DELETE FROM sent_reminders
+		WHERE todo_id = ? AND due_unix = ? AND offset_minutes = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, todoID, dueDate.Unix(), offsetMinutes); err != nil {
+		return fmt.Errorf("failed to forget sent reminder: %w", err)
+	}
+
+	return nil
+}
+
+// reminderColumns lists the reminder columns (joined with todos) in the order expected by scanReminders
+const reminderColumns = `r.id, r.todo_id, t.title, r.remind_at, r.sent_at, r.dismissed_at, r.created_at`
+
+// AddReminder creates a reminder for a todo
+func (r *SQLiteRepository) AddReminder(ctx context.Context, reminder *model.Reminder) error {
+	if _, err := r.GetByID(ctx, reminder.TodoID); err != nil {
+		return err
+	}
+
+	reminder.CreatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO reminders (todo_id, remind_at, created_at)
+		VALUES (?, ?, ?)
+	`, reminder.TodoID, reminder.RemindAt, reminder.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	reminder.ID = id
+	return nil
+}
+
+// GetReminder retrieves a reminder by ID
+func (r *SQLiteRepository) GetReminder(ctx context.Context, id int64) (*model.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		JOIN todos t ON t.id = r.todo_id
+		WHERE r.id = ?
+	`
+
+	reminders, err := r.queryReminders(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(reminders) == 0 {
+		return nil, ErrReminderNotFound
+	}
+
+	return reminders[0], nil
+}
+
+// GetReminders retrieves the active (not dismissed) reminders of a todo (earliest first)
+func (r *SQLiteRepository) GetReminders(ctx context.Context, todoID int64) ([]*model.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		JOIN todos t ON t.id = r.todo_id
+		WHERE r.todo_id = ? AND r.dismissed_at IS NULL
+		ORDER BY r.remind_at ASC
+	`
+
+	return r.queryReminders(ctx, query, todoID)
+}
+
+// GetDueReminders retrieves active reminders of pending todos due at or before the given time
+func (r *SQLiteRepository) GetDueReminders(ctx context.Context, now time.Time) ([]*model.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		JOIN todos t ON t.id = r.todo_id
+		WHERE r.dismissed_at IS NULL AND ` + utcTime("r.remind_at") + ` <= ? AND t.status = ? AND t.deleted_at IS NULL
+		ORDER BY ` + utcTime("r.remind_at") + ` ASC
+	`
+
+	return r.queryReminders(ctx, query, utcParam(now), model.StatusPending)
+}
+
+// queryReminders runs a query selecting reminderColumns
+func (r *SQLiteRepository) queryReminders(ctx context.Context, query string, args ...any) ([]*model.Reminder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reminders: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	var reminders []*model.Reminder
+	for rows.Next() {
+		reminder := &model.Reminder{}
+		var sentAt, dismissedAt sql.NullTime
+
+		err := rows.Scan(
+			&reminder.ID,
+			&reminder.TodoID,
+			&reminder.TodoTitle,
+			&reminder.RemindAt,
+			&sentAt,
+			&dismissedAt,
+			&reminder.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+
+		if sentAt.Valid {
+			reminder.SentAt = &sentAt.Time
+		}
+		if dismissedAt.Valid {
+			reminder.DismissedAt = &dismissedAt.Time
+		}
+
+		reminders = append(reminders, reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// ClaimReminder marks a reminder as sent unless another process already did
+// Returns false if the reminder had already been sent.
+func (r *SQLiteRepository) ClaimReminder(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reminders SET sent_at = ?
+		WHERE id = ? AND sent_at IS NULL
+	`, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ReleaseReminder marks a claimed reminder as not sent, so that it is sent again
+func (r *SQLiteRepository) ReleaseReminder(ctx context.Context, id int64) error {
+	return r.execReminderUpdate(ctx, `
+		UPDATE reminders SET sent_at = NULL
+		WHERE id = ? AND dismissed_at IS NULL
+	`, id)
+}
+
+// RescheduleReminder moves a reminder to a new time so that it is sent again
+func (r *SQLiteRepository) RescheduleReminder(ctx context.Context, id int64, remindAt time.Time) error {
+	return r.execReminderUpdate(ctx, `
+		UPDATE reminders SET remind_at = ?, sent_at = NULL
+		WHERE id = ? AND dismissed_at IS NULL
+	`, remindAt, id)
+}
+
+// DismissReminder dismisses a reminder
+func (r *SQLiteRepository) DismissReminder(ctx context.Context, id int64) error {
+	return r.execReminderUpdate(ctx, `
+		UPDATE reminders SET dismissed_at = ?
+		WHERE id = ? AND dismissed_at IS NULL
+	`, time.Now(), id)
+}
+
+// execReminderUpdate runs an update of a single active reminder
+func (r *SQLiteRepository) execReminderUpdate(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrReminderNotFound
+	}
+
+	return nil
+}
+
+// DeleteReminders deletes the active reminders of a todo and returns how many were deleted
+func (r *SQLiteRepository) DeleteReminders(ctx context.Context, todoID int64) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM reminders WHERE todo_id = ? AND dismissed_at IS NULL
+	`, todoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reminders: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetStopwatch retrieves the running stopwatch (nil if none is running)
+func (r *SQLiteRepository) GetStopwatch(ctx context.Context) (*model.Stopwatch, error) {
+	query := `SELECT todo_id, started_at FROM stopwatch WHERE id = 1`
+
+	stopwatch := &model.Stopwatch{}
+	err := r.db.QueryRowContext(ctx, query).Scan(&stopwatch.TodoID, &stopwatch.StartedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stopwatch: %w", err)
+	}
+
+	return stopwatch, nil
+}
+
+// SetStopwatch stores the running stopwatch, replacing any previous one
+func (r *SQLiteRepository) SetStopwatch(ctx context.Context, stopwatch *model.Stopwatch) error {
+	query := `
+		INSERT INTO stopwatch (id, todo_id, started_at)
+		VALUES (1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET todo_id = excluded.todo_id, started_at = excluded.started_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, stopwatch.TodoID, stopwatch.StartedAt); err != nil {
+		return fmt.Errorf("failed to set stopwatch: %w", err)
+	}
+
+	return nil
+}
+
+// ClearStopwatch removes the running stopwatch
+func (r *SQLiteRepository) ClearStopwatch(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM stopwatch`); err != nil {
+		return fmt.Errorf("failed to clear stopwatch: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreTodo writes a todo back with its original ID, recreating it if it was deleted
+// The work duration of an existing todo is kept, since it always follows its work log.
+func (r *SQLiteRepository) RestoreTodo(ctx context.Context, todo *model.Todo) error {
+	return restoreTodo(ctx, r.db, todo)
+}
+
+// restoreTodo writes a todo back with db, which may be a transaction
+func restoreTodo(ctx context.Context, db execer, todo *model.Todo) error {
+	query := `
+		INSERT INTO todos (id, title, description, status, priority, due_date, work_duration, completed_at, start_date, deleted_at, archived_at, tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title,
+			description = excluded.description,
+			status = excluded.status,
+			priority = excluded.priority,
+			due_date = excluded.due_date,
+			completed_at = excluded.completed_at,
+			start_date = excluded.start_date,
+			deleted_at = excluded.deleted_at,
+			archived_at = excluded.archived_at,
+			tags = excluded.tags,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		todo.ID,
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		todo.Priority,
+		todo.DueDate,
+		todo.WorkDuration,
+		todo.CompletedAt,
+		todo.StartDate,
+		todo.DeletedAt,
+		todo.ArchivedAt,
+		joinTags(todo.Tags),
+		todo.CreatedAt,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore todo: %w", err)
+	}
+
+	return nil
+}
+
+// AddUndoEntry appends a change to the undo history
+func (r *SQLiteRepository) AddUndoEntry(ctx context.Context, entry *model.UndoEntry) error {
+	return r.AddUndoEntries(ctx, []*model.UndoEntry{entry})
+}
+
+// AddUndoEntries appends changes to the undo history
+// Several changes are stored as a group (sharing the ID of the first entry as
+// group_id) and are undone together. Changes that were undone can no longer be
+// redone afterwards, and only the latest undoLogLimit changes (a group counting
+// as one) are kept.
+func (r *SQLiteRepository) AddUndoEntries(ctx context.Context, entries []*model.UndoEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM undo_log WHERE undone_at IS NOT NULL`); err != nil {
+		return fmt.Errorf("failed to clear redo history: %w", err)
+	}
+
+	now := time.Now()
+	var groupID *int64
+	ids := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		before, err := marshalSnapshot(entry.Before)
+		if err != nil {
+			return err
+		}
+		after, err := marshalSnapshot(entry.After)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO undo_log (action, todo_id, title, before_json, after_json, work_log_id, minutes_before, minutes_after, created_at, group_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, entry.Action, entry.TodoID, entry.Title, before, after, entry.WorkLogID, entry.MinutesBefore, entry.MinutesAfter, now, groupID)
+		if err != nil {
+			return fmt.Errorf("failed to create undo entry: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		ids = append(ids, id)
+
+		if len(entries) > 1 && groupID == nil {
+			groupID = &id
+			if _, err := tx.ExecContext(ctx, `UPDATE undo_log SET group_id = ? WHERE id = ?`, id, id); err != nil {
+				return fmt.Errorf("failed to group undo entries: %w", err)
+			}
+		}
+	}
+
+	// A group counts as one change and is kept or pruned as a whole, so that
+	// undoing it never restores only part of a bulk change
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM undo_log
+		WHERE COALESCE(group_id, id) NOT IN (
+			SELECT DISTINCT COALESCE(group_id, id) AS change
+			FROM undo_log
+			ORDER BY change DESC
+			LIMIT ?
+		)
+	`, undoLogLimit); err != nil {
+		return fmt.Errorf("failed to prune undo history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit undo entry: %w", err)
+	}
+
+	for i, entry := range entries {
+		entry.ID = ids[i]
+		entry.CreatedAt = now
+		entry.GroupID = groupID
+	}
+	return nil
+}
+
+// GetUndoEntry retrieves the latest change that is still in effect
+func (r *SQLiteRepository) GetUndoEntry(ctx context.Context) (*model.UndoEntry, error) {
+	return r.queryUndoEntry(ctx, `
+		SELECT `+undoColumns+`
+		FROM undo_log
+		WHERE undone_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`)
+}
+
+// GetRedoEntry retrieves the change that was undone most recently
+func (r *SQLiteRepository) GetRedoEntry(ctx context.Context) (*model.UndoEntry, error) {
+	// Changes are undone newest first, so the oldest undone change was undone last
+	return r.queryUndoEntry(ctx, `
+		SELECT `+undoColumns+`
+		FROM undo_log
+		WHERE undone_at IS NOT NULL
+		ORDER BY id ASC
+		LIMIT 1
+	`)
+}
+
+// GetUndoGroup retrieves the changes of a bulk change (oldest first)
+func (r *SQLiteRepository) GetUndoGroup(ctx context.Context, groupID int64) ([]*model.UndoEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+undoColumns+`
+		FROM undo_log
+		WHERE group_id = ?
+		ORDER BY id ASC
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query undo group: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	entries := make([]*model.UndoEntry, 0)
+	for rows.Next() {
+		entry, err := scanUndoEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating undo group: %w", err)
+	}
+
+	return entries, nil
+}
+
+// queryUndoEntry runs a query selecting a single undo entry with undoColumns
+func (r *SQLiteRepository) queryUndoEntry(ctx context.Context, query string) (*model.UndoEntry, error) {
+	entry, err := scanUndoEntry(r.db.QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return nil, ErrUndoEntryNotFound
+	}
+	return entry, err
+}
+
+// scanUndoEntry scans a single undo entry selected with undoColumns
+func scanUndoEntry(row rowScanner) (*model.UndoEntry, error) {
+	entry := &model.UndoEntry{}
+	var before, after sql.NullString
+	var workLogID, groupID sql.NullInt64
+	var undoneAt sql.NullTime
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.Action,
+		&entry.TodoID,
+		&entry.Title,
+		&before,
+		&after,
+		&workLogID,
+		&entry.MinutesBefore,
+		&entry.MinutesAfter,
+		&entry.CreatedAt,
+		&undoneAt,
+		&groupID,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan undo entry: %w", err)
+	}
+
+	if entry.Before, err = unmarshalSnapshot(before); err != nil {
+		return nil, err
+	}
+	if entry.After, err = unmarshalSnapshot(after); err != nil {
+		return nil, err
+	}
+	if workLogID.Valid {
+		entry.WorkLogID = &workLogID.Int64
+	}
+	if undoneAt.Valid {
+		entry.UndoneAt = &undoneAt.Time
+	}
+	if groupID.Valid {
+		entry.GroupID = &groupID.Int64
+	}
+
+	return entry, nil
+}
+
+// SetUndoEntryUndone marks a change as undone (or as in effect again after a redo)
+func (r *SQLiteRepository) SetUndoEntryUndone(ctx context.Context, id int64, undone bool) error {
+	var undoneAt *time.Time
+	if undone {
+		now := time.Now()
+		undoneAt = &now
+	}
+	return setUndoneAt(ctx, r.db, id, undoneAt)
+}
+
+// setUndoneAt sets when a change was undone (nil when it is in effect) with db, which may be a transaction
+func setUndoneAt(ctx context.Context, db execer, id int64, undoneAt *time.Time) error {
+	result, err := db.ExecContext(ctx, `UPDATE undo_log SET undone_at = ? WHERE id = ?`, undoneAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update undo entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUndoEntryNotFound
+	}
+
+	return nil
+}
+
+// ApplyUndo makes the writes of an undo or redo and marks its changes as undone
+// (or in effect again) in a single transaction, so a bulk change is never left half undone
+func (r *SQLiteRepository) ApplyUndo(ctx context.Context, change *model.UndoChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	for _, todo := range change.Restore {
+		if err := restoreTodo(ctx, tx, todo); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, id := range change.Delete {
+		if err := deleteTodo(ctx, tx, id, now); err != nil {
+			return err
+		}
+	}
+
+	for _, log := range change.WorkLogs {
+		if err := addWorkLog(ctx, tx, log); err != nil {
+			return err
+		}
+	}
+
+	if err := addTodoEvents(ctx, tx, change.Events); err != nil {
+		return err
+	}
+
+	var undoneAt *time.Time
+	if change.Undone {
+		undoneAt = &now
+	}
+	for _, id := range change.EntryIDs {
+		if err := setUndoneAt(ctx, tx, id, undoneAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit undo: %w", err)
+	}
+
+	return nil
+}
+
+// marshalSnapshot encodes a todo snapshot as JSON (NULL for nil)
+func marshalSnapshot(todo *model.Todo) (sql.NullString, error) {
+	if todo == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode todo snapshot: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalSnapshot decodes a todo snapshot encoded by marshalSnapshot
+func unmarshalSnapshot(data sql.NullString) (*model.Todo, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+	todo := &model.Todo{}
+	if err := json.Unmarshal([]byte(data.String), todo); err != nil {
+		return nil, fmt.Errorf("failed to decode todo snapshot: %w", err)
+	}
+	return todo, nil
+}
+
+// AddTodoEvents appends field-level changes to the audit history
+func (r *SQLiteRepository) AddTodoEvents(ctx context.Context, events []*model.TodoEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op after a successful commit
+	}()
+
+	if err := addTodoEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit todo events: %w", err)
+	}
+
+	return nil
+}
+
+// addTodoEvents appends field-level changes with db, which should be a transaction
+func addTodoEvents(ctx context.Context, db execer, events []*model.TodoEvent) error {
+	for _, event := range events {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		result, err := db.ExecContext(ctx, `
+			INSERT INTO todo_events (todo_id, field, old_value, new_value, source, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, event.TodoID, event.Field, event.OldValue, event.NewValue, event.Source, event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create todo event: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		event.ID = id
+	}
+
+	return nil
+}
+
+// GetTodoEvents retrieves the audit history of a todo (oldest first)
+func (r *SQLiteRepository) GetTodoEvents(ctx context.Context, todoID int64) ([]*model.TodoEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, todo_id, field, old_value, new_value, source, created_at
+		FROM todo_events
+		WHERE todo_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query todo events: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error, query results are more important
+	}()
+
+	events := make([]*model.TodoEvent, 0)
+	for rows.Next() {
+		event := &model.TodoEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.TodoID,
+			&event.Field,
+			&event.OldValue,
+			&event.NewValue,
+			&event.Source,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan todo event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating todo events: %w", err)
+	}
+
+	return events, nil
+}
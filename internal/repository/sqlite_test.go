@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -20,6 +22,25 @@ func setupTestDB(t *testing.T) *SQLiteRepository {
 	return repo
 }
 
+// createTestTodo creates a pending todo with the given title for testing
+func createTestTodo(t *testing.T, repo *SQLiteRepository, title string) *model.Todo {
+	t.Helper()
+
+	now := time.Now()
+	todo := &model.Todo{
+		Title:     title,
+		Status:    model.StatusPending,
+		Priority:  model.PriorityMedium,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Create(context.Background(), todo); err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+
+	return todo
+}
+
 func TestNewSQLiteRepository(t *testing.T) {
 	repo := setupTestDB(t)
 	defer func() {
@@ -167,6 +188,100 @@ func TestSQLiteRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestSQLiteRepository_GetPage(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 1; i <= 5; i++ {
+		createTestTodo(t, repo, fmt.Sprintf("Todo %d", i))
+	}
+	deferred := createTestTodo(t, repo, "Later")
+	start := now.Add(24 * time.Hour)
+	deferred.StartDate = &start
+	if err := repo.Update(ctx, deferred); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
+	}
+
+	// Pages leave out the deferred todo and add up to the whole list, newest first
+	var titles []string
+	for offset := 0; offset < 6; offset += 2 {
+		page, err := repo.GetPage(ctx, now, offset, 2)
+		if err != nil {
+			t.Fatalf("failed to get page at %d: %v", offset, err)
+		}
+		for _, todo := range page {
+			titles = append(titles, todo.Title)
+		}
+	}
+	if want := []string{"Todo 5", "Todo 4", "Todo 3", "Todo 2", "Todo 1"}; !slices.Equal(titles, want) {
+		t.Errorf("expected %v, got %v", want, titles)
+	}
+
+	visible, deferredCount, err := repo.CountVisible(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to count todos: %v", err)
+	}
+	if visible != 5 || deferredCount != 1 {
+		t.Errorf("expected 5 visible and 1 deferred todo, got %d and %d", visible, deferredCount)
+	}
+}
+
+func TestSQLiteRepository_SearchTodos(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	createTestTodo(t, repo, "Write release notes")
+	createTestTodo(t, repo, "Review 100% of the PRs")
+	later := createTestTodo(t, repo, "Plan the retro")
+	start := time.Now().Add(24 * time.Hour)
+	later.StartDate = &start
+	if err := repo.Update(ctx, later); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		terms []string
+		fuzzy bool
+		want  []string
+	}{
+		{"title substring, case-insensitive", []string{"RE"}, false, []string{"Plan the retro", "Review 100% of the PRs", "Write release notes"}},
+		{"every term", []string{"re", "notes"}, false, []string{"Write release notes"}},
+		{"wildcards are literal", []string{"0%"}, false, []string{"Review 100% of the PRs"}},
+		{"ID prefix, deferred included", []string{fmt.Sprint(later.ID)}, false, []string{"Plan the retro"}},
+		{"fuzzy", []string{"wrn"}, true, []string{"Write release notes"}},
+		{"not fuzzy", []string{"wrn"}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, err := repo.SearchTodos(ctx, tt.terms, tt.fuzzy, 10)
+			if err != nil {
+				t.Fatalf("failed to search todos: %v", err)
+			}
+			var titles []string
+			for _, todo := range todos {
+				titles = append(titles, todo.Title)
+			}
+			if !slices.Equal(titles, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, titles)
+			}
+		})
+	}
+}
+
 func TestSQLiteRepository_GetByStatus(t *testing.T) {
 	repo := setupTestDB(t)
 	defer func() {
@@ -240,6 +355,8 @@ func TestSQLiteRepository_Update(t *testing.T) {
 	todo.Title = "Updated Title"
 	todo.Description = "Updated Description"
 	todo.Priority = model.PriorityHigh
+	startDate := now.AddDate(0, 1, 0)
+	todo.StartDate = &startDate
 
 	if err := repo.Update(ctx, todo); err != nil {
 		t.Fatalf("failed to update todo: %v", err)
@@ -260,6 +377,9 @@ func TestSQLiteRepository_Update(t *testing.T) {
 	if updated.Priority != model.PriorityHigh {
 		t.Errorf("expected priority %d, got %d", model.PriorityHigh, updated.Priority)
 	}
+	if updated.StartDate == nil || !updated.StartDate.Equal(startDate) {
+		t.Errorf("expected start date %v, got %v", startDate, updated.StartDate)
+	}
 }
 
 func TestSQLiteRepository_Delete(t *testing.T) {
@@ -408,3 +528,1429 @@ func TestSQLiteRepository_AddWorkDuration_NotFound(t *testing.T) {
 		t.Errorf("expected ErrTodoNotFound, got %v", err)
 	}
 }
+
+func TestSQLiteRepository_AddWorkLog(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Test Todo")
+	loggedAt := time.Now().Add(-24 * time.Hour)
+
+	// Log a meeting
+	entry := &model.WorkLog{
+		TodoID:   todo.ID,
+		Minutes:  60,
+		Note:     "Planning meeting",
+		Source:   model.WorkLogSourceManual,
+		LoggedAt: loggedAt,
+	}
+	if err := repo.AddWorkLog(ctx, entry); err != nil {
+		t.Fatalf("failed to add work log: %v", err)
+	}
+	if entry.ID == 0 {
+		t.Error("expected work log ID to be set after creation")
+	}
+
+	// Correct it by 15 minutes
+	correction := &model.WorkLog{
+		TodoID:     todo.ID,
+		Minutes:    -15,
+		Source:     model.WorkLogSourceCorrection,
+		CorrectsID: &entry.ID,
+	}
+	if err := repo.AddWorkLog(ctx, correction); err != nil {
+		t.Fatalf("failed to add correction: %v", err)
+	}
+
+	// Verify the total reflects both entries
+	updated, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get updated todo: %v", err)
+	}
+	if updated.WorkDuration != 45 {
+		t.Errorf("expected work duration %d, got %d", 45, updated.WorkDuration)
+	}
+
+	// Verify the entry was stored as logged
+	stored, err := repo.GetWorkLog(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("failed to get work log: %v", err)
+	}
+	if stored.Note != "Planning meeting" || stored.Source != model.WorkLogSourceManual {
+		t.Errorf("unexpected work log: %+v", stored)
+	}
+	if !stored.LoggedAt.Equal(loggedAt) {
+		t.Errorf("expected logged at %v, got %v", loggedAt, stored.LoggedAt)
+	}
+
+	// Verify both entries are listed, newest first
+	logs, err := repo.GetWorkLogs(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get work logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 work logs, got %d", len(logs))
+	}
+	if logs[0].ID != correction.ID {
+		t.Errorf("expected newest entry first, got #%d", logs[0].ID)
+	}
+	if logs[0].CorrectsID == nil || *logs[0].CorrectsID != entry.ID {
+		t.Errorf("expected correction to reference entry #%d", entry.ID)
+	}
+}
+
+func TestSQLiteRepository_AddWorkLog_NotFound(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+
+	err := repo.AddWorkLog(ctx, &model.WorkLog{TodoID: 9999, Minutes: 25})
+	if err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+
+	// The failed entry must not be recorded
+	logs, err := repo.GetWorkLogs(ctx, 9999)
+	if err != nil {
+		t.Fatalf("failed to get work logs: %v", err)
+	}
+	if len(logs) != 0 {
+		t.Errorf("expected no work logs, got %d", len(logs))
+	}
+}
+
+func TestSQLiteRepository_GetWorkLog_NotFound(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	_, err := repo.GetWorkLog(context.Background(), 9999)
+	if err != ErrWorkLogNotFound {
+		t.Errorf("expected ErrWorkLogNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteRepository_BackfillWorkLogs(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+
+	// Simulate a todo whose time was tracked before work logs existed
+	todo := createTestTodo(t, repo, "Legacy Todo")
+	if _, err := repo.db.Exec(`UPDATE todos SET work_duration = 50 WHERE id = ?`, todo.ID); err != nil {
+		t.Fatalf("failed to set legacy work duration: %v", err)
+	}
+
+	// Running migrations twice must backfill exactly once
+	for i := 0; i < 2; i++ {
+		if err := applyMigrations(repo.db); err != nil {
+			t.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	logs, err := repo.GetWorkLogs(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get work logs: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 backfilled work log, got %d", len(logs))
+	}
+	if logs[0].Minutes != 50 {
+		t.Errorf("expected backfilled minutes %d, got %d", 50, logs[0].Minutes)
+	}
+}
+
+func TestSQLiteRepository_Stopwatch(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+
+	// No stopwatch initially
+	running, err := repo.GetStopwatch(ctx)
+	if err != nil {
+		t.Fatalf("failed to get stopwatch: %v", err)
+	}
+	if running != nil {
+		t.Fatalf("expected no stopwatch, got %+v", running)
+	}
+
+	// Setting twice replaces the previous stopwatch
+	startedAt := time.Now().Add(-10 * time.Minute)
+	if err := repo.SetStopwatch(ctx, &model.Stopwatch{TodoID: 1, StartedAt: startedAt}); err != nil {
+		t.Fatalf("failed to set stopwatch: %v", err)
+	}
+	if err := repo.SetStopwatch(ctx, &model.Stopwatch{TodoID: 2, StartedAt: startedAt}); err != nil {
+		t.Fatalf("failed to replace stopwatch: %v", err)
+	}
+
+	running, err = repo.GetStopwatch(ctx)
+	if err != nil {
+		t.Fatalf("failed to get stopwatch: %v", err)
+	}
+	if running == nil || running.TodoID != 2 {
+		t.Fatalf("expected stopwatch for todo 2, got %+v", running)
+	}
+	if !running.StartedAt.Equal(startedAt) {
+		t.Errorf("expected started at %v, got %v", startedAt, running.StartedAt)
+	}
+
+	if err := repo.ClearStopwatch(ctx); err != nil {
+		t.Fatalf("failed to clear stopwatch: %v", err)
+	}
+	running, _ = repo.GetStopwatch(ctx)
+	if running != nil {
+		t.Errorf("expected stopwatch to be cleared, got %+v", running)
+	}
+}
+
+func TestSQLiteRepository_GetDailyWork(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	first := createTestTodo(t, repo, "First")
+	second := createTestTodo(t, repo, "Second")
+
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	logs := []*model.WorkLog{
+		{TodoID: first.ID, Minutes: 25, LoggedAt: monday.Add(9 * time.Hour)},
+		{TodoID: first.ID, Minutes: 25, LoggedAt: monday.Add(14 * time.Hour)},
+		{TodoID: second.ID, Minutes: 60, LoggedAt: monday.Add(33 * time.Hour)},
+		{TodoID: second.ID, Minutes: -10, LoggedAt: monday.Add(34 * time.Hour)},
+		{TodoID: first.ID, Minutes: 90, LoggedAt: monday.Add(-time.Hour)}, // Previous week
+	}
+	for _, log := range logs {
+		if err := repo.AddWorkLog(ctx, log); err != nil {
+			t.Fatalf("failed to add work log: %v", err)
+		}
+	}
+
+	entries, err := repo.GetDailyWork(ctx, monday, monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("failed to get daily work: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].TodoID != first.ID || entries[0].Minutes != 50 || !entries[0].Day.Equal(monday) {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Title != "Second" || entries[1].Minutes != 50 || !entries[1].Day.Equal(monday.AddDate(0, 0, 1)) {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestSQLiteRepository_MarkAsCompleted_SetsCompletedAt(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Finish me")
+
+	if err := repo.MarkAsCompleted(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to mark as completed: %v", err)
+	}
+
+	retrieved, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get todo: %v", err)
+	}
+	if retrieved.CompletedAt == nil {
+		t.Fatal("expected CompletedAt to be set")
+	}
+	completedAt := *retrieved.CompletedAt
+
+	// Completing again keeps the original completion time
+	if err := repo.MarkAsCompleted(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to mark as completed again: %v", err)
+	}
+	retrieved, err = repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get todo: %v", err)
+	}
+	if !retrieved.CompletedAt.Equal(completedAt) {
+		t.Errorf("CompletedAt changed from %v to %v", completedAt, *retrieved.CompletedAt)
+	}
+}
+
+func TestSQLiteRepository_StatsQueries(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+
+	completed := func(title string, created, done time.Time) {
+		todo := &model.Todo{
+			Title:       title,
+			Status:      model.StatusCompleted,
+			Priority:    model.PriorityMedium,
+			CompletedAt: &done,
+			CreatedAt:   created,
+			UpdatedAt:   done,
+		}
+		if err := repo.Create(ctx, todo); err != nil {
+			t.Fatalf("failed to create todo: %v", err)
+		}
+	}
+	completed("A", monday, monday.Add(10*time.Hour))
+	completed("B", monday, monday.Add(14*time.Hour))
+	completed("C", monday.Add(-48*time.Hour), monday.Add(24*time.Hour+12*time.Hour))
+
+	open := []model.Priority{model.PriorityHigh, model.PriorityHigh, model.PriorityLow}
+	for _, priority := range open {
+		todo := &model.Todo{Title: "Open", Priority: priority, CreatedAt: monday, UpdatedAt: monday}
+		if err := repo.Create(ctx, todo); err != nil {
+			t.Fatalf("failed to create todo: %v", err)
+		}
+	}
+
+	work := createTestTodo(t, repo, "Work")
+	logs := []*model.WorkLog{
+		{TodoID: work.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: monday.Add(9 * time.Hour)},
+		{TodoID: work.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: monday.Add(10 * time.Hour)},
+		{TodoID: work.ID, Minutes: 45, Source: model.WorkLogSourceManual, LoggedAt: monday.Add(11 * time.Hour)},
+		{TodoID: work.ID, Minutes: 30, Source: model.WorkLogSourcePomodoro, LoggedAt: monday.Add(-time.Hour)}, // Previous week
+	}
+	for _, log := range logs {
+		if err := repo.AddWorkLog(ctx, log); err != nil {
+			t.Fatalf("failed to add work log: %v", err)
+		}
+	}
+
+	week := monday.AddDate(0, 0, 7)
+
+	completedByDay, err := repo.CountCompletedByDay(ctx, monday, week)
+	if err != nil {
+		t.Fatalf("failed to count completed todos: %v", err)
+	}
+	if len(completedByDay) != 2 || completedByDay[0].Count != 2 || completedByDay[1].Count != 1 {
+		t.Errorf("unexpected completed counts: %+v", completedByDay)
+	}
+
+	pomodoros, err := repo.CountPomodorosByDay(ctx, monday, week)
+	if err != nil {
+		t.Fatalf("failed to count pomodoros: %v", err)
+	}
+	if len(pomodoros) != 1 || pomodoros[0].Count != 2 || !pomodoros[0].Day.Equal(monday) {
+		t.Errorf("unexpected pomodoro counts: %+v", pomodoros)
+	}
+
+	minutes, err := repo.SumWorkMinutes(ctx, monday, week)
+	if err != nil {
+		t.Fatalf("failed to sum work minutes: %v", err)
+	}
+	if minutes != 95 {
+		t.Errorf("expected 95 minutes, got %d", minutes)
+	}
+
+	minutesByDay, err := repo.SumWorkMinutesByDay(ctx, monday.AddDate(0, 0, -7), week)
+	if err != nil {
+		t.Fatalf("failed to sum work minutes by day: %v", err)
+	}
+	if len(minutesByDay) != 2 || minutesByDay[0].Count != 30 || minutesByDay[1].Count != 95 {
+		t.Errorf("unexpected minutes by day: %+v", minutesByDay)
+	}
+
+	avg, count, err := repo.GetAverageCompletionTime(ctx)
+	if err != nil {
+		t.Fatalf("failed to get average completion time: %v", err)
+	}
+	// (10h + 14h + 84h) / 3 = 36h
+	if count != 3 || avg.Round(time.Minute) != 36*time.Hour {
+		t.Errorf("expected 36h over 3 todos, got %v over %d", avg, count)
+	}
+
+	byPriority, err := repo.CountOpenByPriority(ctx)
+	if err != nil {
+		t.Fatalf("failed to count open todos: %v", err)
+	}
+	if byPriority[model.PriorityHigh] != 2 || byPriority[model.PriorityLow] != 1 || byPriority[model.PriorityMedium] != 1 {
+		t.Errorf("unexpected priority counts: %v", byPriority)
+	}
+
+	days, err := repo.GetCompletionDays(ctx)
+	if err != nil {
+		t.Fatalf("failed to get completion days: %v", err)
+	}
+	if len(days) != 2 || !days[0].Equal(monday.AddDate(0, 0, 1)) || !days[1].Equal(monday) {
+		t.Errorf("unexpected completion days: %v", days)
+	}
+}
+
+func TestSQLiteRepository_StatsQueries_Trash(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+
+	var ids []int64
+	for _, hours := range []time.Duration{10, 20} {
+		done := monday.Add(hours * time.Hour)
+		todo := &model.Todo{
+			Title:       "Done",
+			Status:      model.StatusCompleted,
+			Priority:    model.PriorityMedium,
+			CompletedAt: &done,
+			CreatedAt:   monday,
+			UpdatedAt:   done,
+		}
+		if err := repo.Create(ctx, todo); err != nil {
+			t.Fatalf("failed to create todo: %v", err)
+		}
+		ids = append(ids, todo.ID)
+	}
+	sunday := monday.Add(-12 * time.Hour)
+	trashed := &model.Todo{
+		Title:       "Trashed",
+		Status:      model.StatusCompleted,
+		Priority:    model.PriorityMedium,
+		CompletedAt: &sunday,
+		CreatedAt:   sunday.Add(-time.Hour),
+		UpdatedAt:   sunday,
+	}
+	if err := repo.Create(ctx, trashed); err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	if err := repo.Delete(ctx, ids[1]); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if err := repo.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	completedByDay, err := repo.CountCompletedByDay(ctx, monday.AddDate(0, 0, -7), monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("failed to count completed todos: %v", err)
+	}
+	if len(completedByDay) != 1 || completedByDay[0].Count != 1 || !completedByDay[0].Day.Equal(monday) {
+		t.Errorf("expected only the todo outside the trash to count, got %+v", completedByDay)
+	}
+
+	avg, count, err := repo.GetAverageCompletionTime(ctx)
+	if err != nil {
+		t.Fatalf("failed to get average completion time: %v", err)
+	}
+	if count != 1 || avg.Round(time.Minute) != 10*time.Hour {
+		t.Errorf("expected 10h over 1 todo, got %v over %d", avg, count)
+	}
+
+	days, err := repo.GetCompletionDays(ctx)
+	if err != nil {
+		t.Fatalf("failed to get completion days: %v", err)
+	}
+	if len(days) != 1 || !days[0].Equal(monday) {
+		t.Errorf("expected only %v, got %v", monday, days)
+	}
+}
+
+// distantZones returns zones up to ten hours behind and ahead of the local zone at t
+// Real zone offsets range from -12:00 to +14:00, so tests are skipped where
+// there is no zone at least two hours away on both sides.
+func distantZones(t *testing.T, at time.Time) (behind, ahead *time.Location) {
+	t.Helper()
+	_, offset := at.Zone()
+	behindOffset := max(offset-10*3600, -12*3600)
+	aheadOffset := min(offset+10*3600, 14*3600)
+	if offset-behindOffset < 2*3600 || aheadOffset-offset < 2*3600 {
+		t.Skipf("no zones far enough from the local offset %+d", offset)
+	}
+	return time.FixedZone("BHD", behindOffset), time.FixedZone("AHD", aheadOffset)
+}
+
+func TestSQLiteRepository_StatsQueries_TimeZones(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	day := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	// Times stored in zones hours behind and ahead have their date on the other side of midnight
+	behind, ahead := distantZones(t, day)
+	earlyToday := day.Add(30 * time.Minute).In(behind)
+	lateYesterday := day.Add(-30 * time.Minute).In(ahead)
+
+	work := createTestTodo(t, repo, "Work")
+	for _, loggedAt := range []time.Time{earlyToday, lateYesterday} {
+		log := &model.WorkLog{TodoID: work.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: loggedAt}
+		if err := repo.AddWorkLog(ctx, log); err != nil {
+			t.Fatalf("failed to add work log: %v", err)
+		}
+	}
+	for _, completedAt := range []time.Time{earlyToday, lateYesterday.UTC()} {
+		todo := &model.Todo{
+			Title:       "Done",
+			Status:      model.StatusCompleted,
+			Priority:    model.PriorityMedium,
+			CompletedAt: &completedAt,
+			CreatedAt:   completedAt.Add(-time.Hour),
+			UpdatedAt:   completedAt,
+		}
+		if err := repo.Create(ctx, todo); err != nil {
+			t.Fatalf("failed to create todo: %v", err)
+		}
+	}
+
+	next := day.AddDate(0, 0, 1)
+	expectOne := func(name string, counts []model.DayCount, want int) {
+		t.Helper()
+		if len(counts) != 1 || !counts[0].Day.Equal(day) || counts[0].Count != want {
+			t.Errorf("%s: expected %d on %v, got %+v", name, want, day, counts)
+		}
+	}
+
+	completed, err := repo.CountCompletedByDay(ctx, day, next)
+	if err != nil {
+		t.Fatalf("failed to count completed todos: %v", err)
+	}
+	expectOne("completed", completed, 1)
+
+	pomodoros, err := repo.CountPomodorosByDay(ctx, day, next)
+	if err != nil {
+		t.Fatalf("failed to count pomodoros: %v", err)
+	}
+	expectOne("pomodoros", pomodoros, 1)
+
+	minutes, err := repo.SumWorkMinutesByDay(ctx, day, next)
+	if err != nil {
+		t.Fatalf("failed to sum work minutes by day: %v", err)
+	}
+	expectOne("minutes", minutes, 25)
+
+	daily, err := repo.GetDailyWork(ctx, day.AddDate(0, 0, -1), next)
+	if err != nil {
+		t.Fatalf("failed to get daily work: %v", err)
+	}
+	if len(daily) != 2 || !daily[0].Day.Equal(day.AddDate(0, 0, -1)) || !daily[1].Day.Equal(day) {
+		t.Errorf("expected one entry on each local day, got %+v", daily)
+	}
+
+	days, err := repo.GetCompletionDays(ctx)
+	if err != nil {
+		t.Fatalf("failed to get completion days: %v", err)
+	}
+	if len(days) != 2 || !days[0].Equal(day) || !days[1].Equal(day.AddDate(0, 0, -1)) {
+		t.Errorf("expected the local days newest first, got %v", days)
+	}
+
+	avg, count, err := repo.GetAverageCompletionTime(ctx)
+	if err != nil {
+		t.Fatalf("failed to get average completion time: %v", err)
+	}
+	if count != 2 || avg != time.Hour {
+		t.Errorf("expected 1h over 2 todos, got %v over %d", avg, count)
+	}
+}
+
+func TestSQLiteRepository_TimeComparisons_TimeZones(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	now := time.Now()
+	// Later times stored hours behind read as earlier clock times and
+	// earlier times stored hours ahead as later ones
+	behind, ahead := distantZones(t, now)
+	soon := now.Add(time.Hour).In(behind)
+	recently := now.Add(-time.Hour).In(ahead)
+
+	deferred := createTestTodo(t, repo, "Deferred")
+	deferred.StartDate = &soon
+	if err := repo.Update(ctx, deferred); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
+	}
+
+	visible, hidden, err := repo.CountVisible(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to count todos: %v", err)
+	}
+	if visible != 0 || hidden != 1 {
+		t.Errorf("expected the todo to be deferred, got %d visible and %d deferred", visible, hidden)
+	}
+	page, err := repo.GetPage(ctx, now, 0, 10)
+	if err != nil {
+		t.Fatalf("failed to get page: %v", err)
+	}
+	if len(page) != 0 {
+		t.Errorf("expected the deferred todo to be left out of the page, got %d todos", len(page))
+	}
+
+	reminded := createTestTodo(t, repo, "Reminded")
+	if err := repo.AddReminder(ctx, &model.Reminder{TodoID: reminded.ID, RemindAt: recently}); err != nil {
+		t.Fatalf("failed to add reminder: %v", err)
+	}
+	due, err := repo.GetDueReminders(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to get due reminders: %v", err)
+	}
+	if len(due) != 1 {
+		t.Errorf("expected the reminder to be due, got %d", len(due))
+	}
+
+	overdue := createTestTodo(t, repo, "Overdue")
+	overdue.DueDate = &recently
+	if err := repo.Update(ctx, overdue); err != nil {
+		t.Fatalf("failed to set due date: %v", err)
+	}
+	pending, err := repo.GetPendingDueBefore(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to get pending todos due: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != overdue.ID {
+		t.Errorf("expected the overdue todo, got %d todos", len(pending))
+	}
+
+	done := createTestTodo(t, repo, "Done")
+	done.Status = model.StatusCompleted
+	done.CompletedAt = &recently
+	if err := repo.Update(ctx, done); err != nil {
+		t.Fatalf("failed to complete todo: %v", err)
+	}
+	archived, err := repo.ArchiveCompletedBefore(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to archive completed todos: %v", err)
+	}
+	if len(archived) != 1 {
+		t.Errorf("expected the completed todo to be archived, got %v", archived)
+	}
+
+	trashed := createTestTodo(t, repo, "Trashed")
+	if _, err := repo.db.ExecContext(ctx, `UPDATE todos SET deleted_at = ? WHERE id = ?`, recently, trashed.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	purged, err := repo.PurgeDeletedBefore(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to purge trash: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected the deleted todo to be purged, got %d", purged)
+	}
+}
+
+func TestSQLiteRepository_CountPomodorosByDay_StoppedEarly(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	day := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	work := createTestTodo(t, repo, "Work")
+	logs := []*model.WorkLog{
+		{TodoID: work.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: day.Add(9 * time.Hour)},
+		{TodoID: work.ID, Minutes: 3, Source: model.WorkLogSourcePartialPomodoro, LoggedAt: day.Add(10 * time.Hour)},
+	}
+	for _, log := range logs {
+		if err := repo.AddWorkLog(ctx, log); err != nil {
+			t.Fatalf("failed to add work log: %v", err)
+		}
+	}
+
+	pomodoros, err := repo.CountPomodorosByDay(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("failed to count pomodoros: %v", err)
+	}
+	if len(pomodoros) != 1 || pomodoros[0].Count != 1 {
+		t.Errorf("expected only the finished Pomodoro to count, got %+v", pomodoros)
+	}
+
+	// The time of the stopped timer still counts as focus time
+	minutes, err := repo.SumWorkMinutes(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("failed to sum work minutes: %v", err)
+	}
+	if minutes != 28 {
+		t.Errorf("expected 28 minutes, got %d", minutes)
+	}
+}
+
+func TestSQLiteRepository_BackfillCompletedAt(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Done long ago")
+
+	// Simulate a todo completed before completed_at existed
+	if _, err := repo.db.ExecContext(ctx, `UPDATE todos SET status = ?, completed_at = NULL WHERE id = ?`, model.StatusCompleted, todo.ID); err != nil {
+		t.Fatalf("failed to reset completed_at: %v", err)
+	}
+
+	if err := applyMigrations(repo.db); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	retrieved, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get todo: %v", err)
+	}
+	if retrieved.CompletedAt == nil || !retrieved.CompletedAt.Equal(retrieved.UpdatedAt) {
+		t.Errorf("expected CompletedAt to be backfilled with UpdatedAt, got %v", retrieved.CompletedAt)
+	}
+}
+
+func TestSQLiteRepository_DueReminders(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+
+	create := func(title string, due *time.Time, status model.TodoStatus) *model.Todo {
+		todo := &model.Todo{Title: title, Status: status, DueDate: due, CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, todo); err != nil {
+			t.Fatalf("failed to create todo: %v", err)
+		}
+		return todo
+	}
+	soon := now.Add(30 * time.Minute)
+	past := now.Add(-time.Hour)
+	later := now.Add(3 * time.Hour)
+
+	dueSoon := create("Due soon", &soon, model.StatusPending)
+	overdue := create("Overdue", &past, model.StatusPending)
+	create("Due later", &later, model.StatusPending)
+	create("Completed", &soon, model.StatusCompleted)
+	create("No due date", nil, model.StatusPending)
+
+	todos, err := repo.GetPendingDueBefore(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get due todos: %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != overdue.ID || todos[1].ID != dueSoon.ID {
+		t.Fatalf("expected overdue and due soon todos, got %+v", todos)
+	}
+
+	sent, err := repo.MarkReminderSent(ctx, dueSoon.ID, soon, 60)
+	if err != nil || !sent {
+		t.Fatalf("expected first reminder to be recorded, got %v, %v", sent, err)
+	}
+	sent, err = repo.MarkReminderSent(ctx, dueSoon.ID, soon, 60)
+	if err != nil || sent {
+		t.Errorf("expected duplicate reminder to be ignored, got %v, %v", sent, err)
+	}
+
+	// A new due date re-arms the reminder
+	sent, err = repo.MarkReminderSent(ctx, dueSoon.ID, later, 60)
+	if err != nil || !sent {
+		t.Errorf("expected reminder for new due date to be recorded, got %v, %v", sent, err)
+	}
+
+	// A reminder that is no longer recorded as sent can be recorded again
+	if err := repo.UnmarkReminderSent(ctx, dueSoon.ID, soon, 60); err != nil {
+		t.Fatalf("failed to forget sent reminder: %v", err)
+	}
+	sent, err = repo.MarkReminderSent(ctx, dueSoon.ID, soon, 60)
+	if err != nil || !sent {
+		t.Errorf("expected forgotten reminder to be recorded again, got %v, %v", sent, err)
+	}
+}
+
+func TestSQLiteRepository_Reminders(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	todo := createTestTodo(t, repo, "Call the bank")
+
+	if err := repo.AddReminder(ctx, &model.Reminder{TodoID: 999, RemindAt: now}); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+
+	due := &model.Reminder{TodoID: todo.ID, RemindAt: now.Add(-time.Minute)}
+	upcoming := &model.Reminder{TodoID: todo.ID, RemindAt: now.Add(time.Hour)}
+	for _, r := range []*model.Reminder{upcoming, due} {
+		if err := repo.AddReminder(ctx, r); err != nil {
+			t.Fatalf("failed to add reminder: %v", err)
+		}
+	}
+
+	reminders, err := repo.GetReminders(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get reminders: %v", err)
+	}
+	if len(reminders) != 2 || reminders[0].ID != due.ID || reminders[0].TodoTitle != "Call the bank" {
+		t.Fatalf("unexpected reminders: %+v", reminders)
+	}
+
+	dueReminders, err := repo.GetDueReminders(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to get due reminders: %v", err)
+	}
+	if len(dueReminders) != 1 || dueReminders[0].ID != due.ID {
+		t.Fatalf("expected only the due reminder, got %+v", dueReminders)
+	}
+
+	claimed, err := repo.ClaimReminder(ctx, due.ID)
+	if err != nil || !claimed {
+		t.Fatalf("expected reminder to be claimed, got %v (%v)", claimed, err)
+	}
+	if claimed, _ := repo.ClaimReminder(ctx, due.ID); claimed {
+		t.Error("expected second claim to fail")
+	}
+
+	// A released reminder can be claimed again
+	if err := repo.ReleaseReminder(ctx, due.ID); err != nil {
+		t.Fatalf("failed to release reminder: %v", err)
+	}
+	if claimed, _ := repo.ClaimReminder(ctx, due.ID); !claimed {
+		t.Error("expected the released reminder to be claimed again")
+	}
+
+	// Rescheduling clears the sent time
+	if err := repo.RescheduleReminder(ctx, due.ID, now.Add(10*time.Minute)); err != nil {
+		t.Fatalf("failed to reschedule: %v", err)
+	}
+	retrieved, err := repo.GetReminder(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("failed to get reminder: %v", err)
+	}
+	if retrieved.SentAt != nil || !retrieved.RemindAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("unexpected rescheduled reminder: %+v", retrieved)
+	}
+
+	if err := repo.DismissReminder(ctx, due.ID); err != nil {
+		t.Fatalf("failed to dismiss: %v", err)
+	}
+	if err := repo.DismissReminder(ctx, due.ID); err != ErrReminderNotFound {
+		t.Errorf("expected ErrReminderNotFound, got %v", err)
+	}
+
+	// Reminders of completed todos are not due
+	if err := repo.MarkAsCompleted(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to complete todo: %v", err)
+	}
+	if dueReminders, _ := repo.GetDueReminders(ctx, now.Add(2*time.Hour)); len(dueReminders) != 0 {
+		t.Errorf("expected no due reminders for completed todo, got %d", len(dueReminders))
+	}
+
+	deleted, err := repo.DeleteReminders(ctx, todo.ID)
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 deleted reminder, got %d (%v)", deleted, err)
+	}
+}
+
+func TestSQLiteRepository_UndoLog(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Write report")
+
+	if _, err := repo.GetUndoEntry(ctx); err != ErrUndoEntryNotFound {
+		t.Errorf("expected ErrUndoEntryNotFound, got %v", err)
+	}
+
+	before := *todo
+	after := *todo
+	after.Title = "Write the report"
+	edit := &model.UndoEntry{Action: model.UndoActionEdit, TodoID: todo.ID, Title: after.Title, Before: &before, After: &after}
+	if err := repo.AddUndoEntry(ctx, edit); err != nil {
+		t.Fatalf("failed to add undo entry: %v", err)
+	}
+	logID := int64(7)
+	work := &model.UndoEntry{Action: model.UndoActionWork, TodoID: todo.ID, WorkLogID: &logID, MinutesAfter: 25}
+	if err := repo.AddUndoEntry(ctx, work); err != nil {
+		t.Fatalf("failed to add undo entry: %v", err)
+	}
+
+	// The latest change is undone first
+	entry, err := repo.GetUndoEntry(ctx)
+	if err != nil {
+		t.Fatalf("failed to get undo entry: %v", err)
+	}
+	if entry.ID != work.ID || entry.WorkLogID == nil || *entry.WorkLogID != logID || entry.MinutesAfter != 25 {
+		t.Errorf("unexpected undo entry: %+v", entry)
+	}
+	if entry.Before != nil || entry.After != nil {
+		t.Error("expected work change without todo snapshots")
+	}
+
+	for _, e := range []*model.UndoEntry{work, edit} {
+		if err := repo.SetUndoEntryUndone(ctx, e.ID, true); err != nil {
+			t.Fatalf("failed to mark entry as undone: %v", err)
+		}
+	}
+
+	// The change undone last is redone first, with its snapshots intact
+	entry, err = repo.GetRedoEntry(ctx)
+	if err != nil {
+		t.Fatalf("failed to get redo entry: %v", err)
+	}
+	if entry.ID != edit.ID || entry.UndoneAt == nil {
+		t.Errorf("expected the edit to be redone first, got %+v", entry)
+	}
+	if entry.Before == nil || entry.Before.Title != "Write report" || entry.After == nil || entry.After.Title != "Write the report" {
+		t.Errorf("unexpected snapshots: %+v / %+v", entry.Before, entry.After)
+	}
+
+	// A new change discards the undone changes
+	if err := repo.AddUndoEntry(ctx, &model.UndoEntry{Action: model.UndoActionCreate, TodoID: todo.ID, After: &after}); err != nil {
+		t.Fatalf("failed to add undo entry: %v", err)
+	}
+	if _, err := repo.GetRedoEntry(ctx); err != ErrUndoEntryNotFound {
+		t.Errorf("expected ErrUndoEntryNotFound, got %v", err)
+	}
+	if err := repo.SetUndoEntryUndone(ctx, edit.ID, false); err != ErrUndoEntryNotFound {
+		t.Errorf("expected ErrUndoEntryNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteRepository_ApplyUndo(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	first := createTestTodo(t, repo, "First")
+	second := createTestTodo(t, repo, "Second")
+	entries := []*model.UndoEntry{
+		{Action: model.UndoActionEdit, TodoID: first.ID, Before: first, After: first},
+		{Action: model.UndoActionEdit, TodoID: second.ID, Before: second, After: second},
+	}
+	if err := repo.AddUndoEntries(ctx, entries); err != nil {
+		t.Fatalf("failed to add undo entries: %v", err)
+	}
+
+	renamed := *first
+	renamed.Title = "Renamed"
+	change := &model.UndoChange{
+		EntryIDs: []int64{entries[1].ID, entries[0].ID},
+		Undone:   true,
+		Restore:  []*model.Todo{&renamed},
+		Delete:   []int64{second.ID},
+		WorkLogs: []*model.WorkLog{{TodoID: first.ID, Minutes: 25, Source: model.WorkLogSourceCorrection}},
+		Events:   []*model.TodoEvent{{TodoID: first.ID, Field: "title", OldValue: "First", NewValue: "Renamed", Source: "tui"}},
+	}
+
+	// A failing write leaves everything as it was
+	failing := *change
+	failing.Delete = []int64{second.ID, 999}
+	if err := repo.ApplyUndo(ctx, &failing); err != ErrTodoNotFound {
+		t.Fatalf("expected ErrTodoNotFound, got %v", err)
+	}
+	if todo, _ := repo.GetByID(ctx, first.ID); todo.Title != "First" || todo.WorkDuration != 0 {
+		t.Errorf("expected the restore and work log to be rolled back, got %+v", todo)
+	}
+	if _, err := repo.GetByID(ctx, second.ID); err != nil {
+		t.Errorf("expected the delete to be rolled back, got %v", err)
+	}
+	if events, _ := repo.GetTodoEvents(ctx, first.ID); len(events) != 0 {
+		t.Errorf("expected no events, got %d", len(events))
+	}
+	if _, err := repo.GetRedoEntry(ctx); err != ErrUndoEntryNotFound {
+		t.Errorf("expected the changes to stay in effect, got %v", err)
+	}
+
+	if err := repo.ApplyUndo(ctx, change); err != nil {
+		t.Fatalf("failed to apply undo: %v", err)
+	}
+	if todo, _ := repo.GetByID(ctx, first.ID); todo.Title != "Renamed" || todo.WorkDuration != 25 {
+		t.Errorf("expected the todo restored with the correction, got %+v", todo)
+	}
+	if _, err := repo.GetByID(ctx, second.ID); err != ErrTodoNotFound {
+		t.Errorf("expected the second todo in the trash, got %v", err)
+	}
+	if events, _ := repo.GetTodoEvents(ctx, first.ID); len(events) != 1 {
+		t.Errorf("expected 1 event, got %d", len(events))
+	}
+	if _, err := repo.GetUndoEntry(ctx); err != ErrUndoEntryNotFound {
+		t.Errorf("expected both changes undone, got %v", err)
+	}
+}
+
+func TestSQLiteRepository_UndoLog_PruneGroups(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Write report")
+	change := func() *model.UndoEntry {
+		return &model.UndoEntry{Action: model.UndoActionEdit, TodoID: todo.ID, Before: todo, After: todo}
+	}
+	groupSize := func(groupID int64) int {
+		t.Helper()
+		entries, err := repo.GetUndoGroup(ctx, groupID)
+		if err != nil {
+			t.Fatalf("failed to get undo group: %v", err)
+		}
+		return len(entries)
+	}
+
+	bulk := []*model.UndoEntry{change(), change(), change()}
+	if err := repo.AddUndoEntries(ctx, bulk); err != nil {
+		t.Fatalf("failed to add undo entries: %v", err)
+	}
+	groupID := bulk[0].ID
+
+	// The group counts as a single change, and is kept whole while it is among the latest
+	for i := 0; i < undoLogLimit-1; i++ {
+		if err := repo.AddUndoEntry(ctx, change()); err != nil {
+			t.Fatalf("failed to add undo entry: %v", err)
+		}
+	}
+	if n := groupSize(groupID); n != len(bulk) {
+		t.Fatalf("expected the whole group to be kept, got %d of %d entries", n, len(bulk))
+	}
+
+	// One more change prunes the whole group
+	if err := repo.AddUndoEntry(ctx, change()); err != nil {
+		t.Fatalf("failed to add undo entry: %v", err)
+	}
+	if n := groupSize(groupID); n != 0 {
+		t.Errorf("expected the whole group to be pruned, got %d entries left", n)
+	}
+
+	// A bulk change larger than the limit is kept whole
+	large := make([]*model.UndoEntry, undoLogLimit+1)
+	for i := range large {
+		large[i] = change()
+	}
+	if err := repo.AddUndoEntries(ctx, large); err != nil {
+		t.Fatalf("failed to add undo entries: %v", err)
+	}
+	if n := groupSize(large[0].ID); n != len(large) {
+		t.Errorf("expected the large group to be kept whole, got %d of %d entries", n, len(large))
+	}
+}
+
+func TestSQLiteRepository_RestoreTodo(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Original")
+	snapshot := *todo
+
+	// Restoring an existing todo keeps its work duration
+	if err := repo.AddWorkDuration(ctx, todo.ID, 25); err != nil {
+		t.Fatalf("failed to add work duration: %v", err)
+	}
+	snapshot.Title = "Restored"
+	if err := repo.RestoreTodo(ctx, &snapshot); err != nil {
+		t.Fatalf("failed to restore todo: %v", err)
+	}
+	restored, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get todo: %v", err)
+	}
+	if restored.Title != "Restored" || restored.WorkDuration != 25 {
+		t.Errorf("expected restored title with 25m, got %q and %dm", restored.Title, restored.WorkDuration)
+	}
+
+	// Restoring a deleted todo recreates it with its ID
+	if err := repo.Delete(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	restored.Title = "Recreated"
+	if err := repo.RestoreTodo(ctx, restored); err != nil {
+		t.Fatalf("failed to restore todo: %v", err)
+	}
+	recreated, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("deleted todo not recreated: %v", err)
+	}
+	if recreated.Title != "Recreated" || recreated.WorkDuration != 25 {
+		t.Errorf("expected recreated title with 25m, got %q and %dm", recreated.Title, recreated.WorkDuration)
+	}
+}
+
+func TestSQLiteRepository_Trash(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	kept := createTestTodo(t, repo, "Keep me")
+	trashed := createTestTodo(t, repo, "Trash me")
+	if err := repo.AddWorkDuration(ctx, trashed.ID, 25); err != nil {
+		t.Fatalf("failed to add work duration: %v", err)
+	}
+
+	if err := repo.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if err := repo.Delete(ctx, trashed.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound when deleting twice, got %v", err)
+	}
+
+	// Deleted todos are hidden everywhere but in the trash
+	all, _ := repo.GetAll(ctx)
+	if len(all) != 1 || all[0].ID != kept.ID {
+		t.Errorf("expected only the kept todo, got %d todos", len(all))
+	}
+	pending, _ := repo.GetByStatus(ctx, model.StatusPending)
+	if len(pending) != 1 {
+		t.Errorf("expected 1 pending todo, got %d", len(pending))
+	}
+	if _, err := repo.GetByID(ctx, trashed.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound for a deleted todo, got %v", err)
+	}
+	deleted, err := repo.GetDeleted(ctx)
+	if err != nil {
+		t.Fatalf("failed to get deleted todos: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != trashed.ID || deleted[0].DeletedAt == nil {
+		t.Fatalf("expected the trashed todo with deleted_at, got %v", deleted)
+	}
+
+	if err := repo.Undelete(ctx, trashed.ID); err != nil {
+		t.Fatalf("failed to restore todo: %v", err)
+	}
+	if restored, err := repo.GetByID(ctx, trashed.ID); err != nil || restored.DeletedAt != nil {
+		t.Errorf("expected restored todo, got %v (%v)", restored, err)
+	}
+	if err := repo.Undelete(ctx, trashed.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound for a todo not in the trash, got %v", err)
+	}
+
+	// Only todos in the trash can be purged, together with their work log
+	if err := repo.Purge(ctx, trashed.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound when purging a todo not in the trash, got %v", err)
+	}
+	if err := repo.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if count, _ := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour)); count != 0 {
+		t.Errorf("expected recently deleted todo to be kept, purged %d", count)
+	}
+	count, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to purge deleted todos: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 purged todo, got %d", count)
+	}
+	if logs, _ := repo.GetWorkLogs(ctx, trashed.ID); len(logs) != 0 {
+		t.Errorf("expected work log to be purged, got %d entries", len(logs))
+	}
+	if deleted, _ := repo.GetDeleted(ctx); len(deleted) != 0 {
+		t.Errorf("expected empty trash, got %d todos", len(deleted))
+	}
+}
+
+func TestSQLiteRepository_Archive(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	createTestTodo(t, repo, "Still open")
+	recent := createTestTodo(t, repo, "Finished 100% of the report")
+	old := createTestTodo(t, repo, "Old_invoice")
+	for _, todo := range []*model.Todo{recent, old} {
+		if err := repo.MarkAsCompleted(ctx, todo.ID); err != nil {
+			t.Fatalf("failed to complete todo: %v", err)
+		}
+	}
+	old, _ = repo.GetByID(ctx, old.ID)
+	longAgo := time.Now().AddDate(0, 0, -40)
+	old.CompletedAt = &longAgo
+	if err := repo.Update(ctx, old); err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+
+	// Only todos completed before the threshold are archived
+	ids, err := repo.ArchiveCompletedBefore(ctx, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("failed to archive todos: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != old.ID {
+		t.Errorf("expected only the old todo to be archived, got %v", ids)
+	}
+
+	// Archived todos are hidden from the default lists but can still be opened
+	all, _ := repo.GetAll(ctx)
+	if len(all) != 2 {
+		t.Errorf("expected 2 todos, got %d", len(all))
+	}
+	completed, _ := repo.GetByStatus(ctx, model.StatusCompleted)
+	if len(completed) != 1 || completed[0].ID != recent.ID {
+		t.Errorf("expected only the recent completed todo, got %d todos", len(completed))
+	}
+	if archived, err := repo.GetByID(ctx, old.ID); err != nil || archived.ArchivedAt == nil {
+		t.Errorf("expected archived todo with archived_at, got %v (%v)", archived, err)
+	}
+
+	recent.ArchivedAt = &longAgo
+	recent.Status = model.StatusCompleted
+	if err := repo.Update(ctx, recent); err != nil {
+		t.Fatalf("failed to update todo: %v", err)
+	}
+
+	archived, err := repo.GetArchived(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to get archived todos: %v", err)
+	}
+	if len(archived) != 2 || archived[0].ID != old.ID {
+		t.Errorf("expected 2 archived todos, most recently archived first, got %v", archived)
+	}
+
+	// LIKE wildcards in the query match literally
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"invoice", 1},
+		{"100%", 1},
+		{"_", 1},
+		{"%", 1},
+		{"open", 0},
+	}
+	for _, tt := range tests {
+		found, err := repo.GetArchived(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("failed to search archive for %q: %v", tt.query, err)
+		}
+		if len(found) != tt.want {
+			t.Errorf("query %q: expected %d todos, got %d", tt.query, tt.want, len(found))
+		}
+	}
+}
+
+func TestSQLiteRepository_TodoEvents(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	todo := createTestTodo(t, repo, "Audited")
+	other := createTestTodo(t, repo, "Other")
+
+	events := []*model.TodoEvent{
+		{TodoID: todo.ID, Field: model.EventFieldCreated, NewValue: "Audited", Source: model.EventSourceTUI},
+		{TodoID: todo.ID, Field: model.EventFieldPriority, OldValue: "low", NewValue: "high", Source: model.EventSourceCLI},
+		{TodoID: other.ID, Field: model.EventFieldCreated, NewValue: "Other", Source: model.EventSourceImport},
+	}
+	if err := repo.AddTodoEvents(ctx, events); err != nil {
+		t.Fatalf("failed to add events: %v", err)
+	}
+	if events[0].ID == 0 {
+		t.Error("expected event ID to be set")
+	}
+
+	history, err := repo.GetTodoEvents(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get events: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(history))
+	}
+	if got := history[1]; got.Field != model.EventFieldPriority || got.OldValue != "low" || got.NewValue != "high" || got.Source != model.EventSourceCLI {
+		t.Errorf("unexpected event %+v", got)
+	}
+
+	// Purging a todo removes its history
+	if err := repo.Delete(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if err := repo.Purge(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to purge todo: %v", err)
+	}
+	if history, _ := repo.GetTodoEvents(ctx, todo.ID); len(history) != 0 {
+		t.Errorf("expected history to be purged, got %d events", len(history))
+	}
+	if history, _ := repo.GetTodoEvents(ctx, other.ID); len(history) != 1 {
+		t.Errorf("expected other history to be kept, got %d events", len(history))
+	}
+}
+
+func TestSQLiteRepository_Bulk(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	first := createTestTodo(t, repo, "First")
+	second := createTestTodo(t, repo, "Second")
+
+	// A missing todo rolls back the whole update
+	first.Priority = model.PriorityHigh
+	missing := &model.Todo{ID: 999, Title: "Missing"}
+	if err := repo.UpdateMany(ctx, []*model.Todo{first, missing}); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, first.ID); stored.Priority == model.PriorityHigh {
+		t.Error("expected the update to be rolled back")
+	}
+
+	second.Priority = model.PriorityHigh
+	if err := repo.UpdateMany(ctx, []*model.Todo{first, second}); err != nil {
+		t.Fatalf("failed to update todos: %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, second.ID); stored.Priority != model.PriorityHigh {
+		t.Errorf("expected high priority, got %v", stored.Priority)
+	}
+
+	if err := repo.DeleteMany(ctx, []int64{first.ID, 999}); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+	if all, _ := repo.GetAll(ctx); len(all) != 2 {
+		t.Errorf("expected the delete to be rolled back, got %d todos", len(all))
+	}
+	if err := repo.DeleteMany(ctx, []int64{first.ID, second.ID}); err != nil {
+		t.Fatalf("failed to delete todos: %v", err)
+	}
+	if deleted, _ := repo.GetDeleted(ctx); len(deleted) != 2 {
+		t.Errorf("expected 2 todos in the trash, got %d", len(deleted))
+	}
+
+	// The entries of a bulk change share a group
+	entries := []*model.UndoEntry{
+		{Action: model.UndoActionDelete, TodoID: first.ID, Title: first.Title, Before: first},
+		{Action: model.UndoActionDelete, TodoID: second.ID, Title: second.Title, Before: second},
+	}
+	if err := repo.AddUndoEntries(ctx, entries); err != nil {
+		t.Fatalf("failed to add undo entries: %v", err)
+	}
+	if entries[0].GroupID == nil || *entries[0].GroupID != entries[0].ID {
+		t.Fatalf("expected the group to be named after the first entry, got %v", entries[0].GroupID)
+	}
+
+	latest, err := repo.GetUndoEntry(ctx)
+	if err != nil {
+		t.Fatalf("failed to get undo entry: %v", err)
+	}
+	if latest.ID != entries[1].ID || latest.GroupID == nil {
+		t.Fatalf("expected the grouped latest entry, got %+v", latest)
+	}
+	group, err := repo.GetUndoGroup(ctx, *latest.GroupID)
+	if err != nil {
+		t.Fatalf("failed to get undo group: %v", err)
+	}
+	if len(group) != 2 || group[0].ID != entries[0].ID {
+		t.Errorf("expected both entries oldest first, got %d entries", len(group))
+	}
+
+	single := &model.UndoEntry{Action: model.UndoActionEdit, TodoID: first.ID, Title: first.Title, Before: first, After: first}
+	if err := repo.AddUndoEntry(ctx, single); err != nil {
+		t.Fatalf("failed to add undo entry: %v", err)
+	}
+	if single.GroupID != nil {
+		t.Errorf("expected a single change to have no group, got %v", *single.GroupID)
+	}
+}
+
+func TestSQLiteRepository_Tags(t *testing.T) {
+	repo := setupTestDB(t)
+	defer func() { _ = repo.Close() }()
+	ctx := context.Background()
+
+	tagged := &model.Todo{Title: "Fix login bug", Tags: []string{"auth", "urgent"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := repo.Create(ctx, tagged); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	untagged := createTestTodo(t, repo, "No tags")
+
+	got, err := repo.GetByID(ctx, tagged.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !slices.Equal(got.Tags, []string{"auth", "urgent"}) {
+		t.Errorf("expected tags [auth urgent], got %q", got.Tags)
+	}
+
+	got.Tags = []string{"ui"}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, _ = repo.GetByID(ctx, tagged.ID)
+	if !slices.Equal(got.Tags, []string{"ui"}) {
+		t.Errorf("expected tags [ui] after update, got %q", got.Tags)
+	}
+
+	got, _ = repo.GetByID(ctx, untagged.ID)
+	if got.Tags != nil {
+		t.Errorf("expected no tags, got %q", got.Tags)
+	}
+}
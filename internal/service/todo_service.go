@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syeeel/koto-cli-go/internal/model"
 	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
 )
 
 var (
@@ -21,6 +27,32 @@ var (
 	ErrInvalidPriority = errors.New("invalid priority")
 	// ErrInvalidWorkDuration is returned when work duration is invalid (negative or zero)
 	ErrInvalidWorkDuration = errors.New("work duration must be positive")
+	// ErrEmptyWorkLog is returned when a work log entry has zero minutes
+	ErrEmptyWorkLog = errors.New("work duration cannot be zero")
+	// ErrNegativeWorkTotal is returned when a correction would make the total work time negative
+	ErrNegativeWorkTotal = errors.New("total work time cannot become negative")
+	// ErrWorkLogNotFound is returned when a work log entry is not found
+	ErrWorkLogNotFound = errors.New("work log entry not found")
+	// ErrInvalidDateRange is returned when a date range ends before it starts
+	ErrInvalidDateRange = errors.New("end date must be after start date")
+	// ErrNoStopwatch is returned when stopping while no stopwatch is running
+	ErrNoStopwatch = errors.New("no stopwatch is running")
+	// ErrInvalidCorrection is returned when trying to adjust a correction entry
+	ErrInvalidCorrection = errors.New("corrections cannot be adjusted, adjust the original entry instead")
+	// ErrReminderNotFound is returned when a reminder is not found
+	ErrReminderNotFound = errors.New("reminder not found")
+	// ErrReminderInPast is returned when a reminder is set for a time that has already passed
+	ErrReminderInPast = errors.New("reminder time must be in the future")
+	// ErrNotCompleted is returned when archiving a todo that is not completed
+	ErrNotCompleted = errors.New("only completed todos can be archived")
+	// ErrNotArchived is returned when unarchiving a todo that is not archived
+	ErrNotArchived = errors.New("todo is not archived")
+	// ErrNoSelection is returned when a bulk operation is given no todos
+	ErrNoSelection = errors.New("no todos selected")
+	// ErrNothingToUndo is returned when there is no change to undo
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned when there is no undone change to redo
+	ErrNothingToRedo = errors.New("nothing to redo")
 	// ErrFileNotFound is returned when the specified file is not found
 	ErrFileNotFound = errors.New("file not found")
 	// ErrInvalidJSON is returned when the JSON format is invalid
@@ -41,8 +73,25 @@ func NewTodoService(repo repository.TodoRepository) *TodoService {
 	return &TodoService{repo: repo}
 }
 
+// sourceKey is the context key of the event source
+type sourceKey struct{}
+
+// WithSource returns a context whose changes are recorded in the audit history as made from source
+func WithSource(ctx context.Context, source model.EventSource) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// sourceFromContext returns the event source of ctx (the CLI if none was set)
+func sourceFromContext(ctx context.Context) model.EventSource {
+	if source, ok := ctx.Value(sourceKey{}).(model.EventSource); ok {
+		return source
+	}
+	return model.EventSourceCLI
+}
+
 // AddTodo adds a new todo item
-func (s *TodoService) AddTodo(ctx context.Context, title, description string, priority model.Priority, dueDate *time.Time) (*model.Todo, error) {
+// Tags are normalized, see model.NormalizeTags.
+func (s *TodoService) AddTodo(ctx context.Context, title, description string, priority model.Priority, dueDate *time.Time, tags ...string) (*model.Todo, error) {
 	if err := s.validateTitle(title); err != nil {
 		return nil, err
 	}
@@ -58,6 +107,7 @@ func (s *TodoService) AddTodo(ctx context.Context, title, description string, pr
 		Status:      model.StatusPending,
 		Priority:    priority,
 		DueDate:     dueDate,
+		Tags:        model.NormalizeTags(tags),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -66,9 +116,32 @@ func (s *TodoService) AddTodo(ctx context.Context, title, description string, pr
 		return nil, err
 	}
 
+	if err := s.recordTodoChange(ctx, model.UndoActionCreate, nil, copyTodo(todo)); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// GetTodo returns a todo by ID, including deferred and archived todos
+func (s *TodoService) GetTodo(ctx context.Context, id int64) (*model.Todo, error) {
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
 	return todo, nil
 }
 
+// SearchTodos returns up to limit todos whose title contains every term, or whose ID
+// starts with the only term (newest first)
+// With fuzzy, the characters of a term only have to appear in order in the title.
+func (s *TodoService) SearchTodos(ctx context.Context, terms []string, fuzzy bool, limit int) ([]*model.Todo, error) {
+	return s.repo.SearchTodos(ctx, terms, fuzzy, limit)
+}
+
 // EditTodo edits an existing todo item
 func (s *TodoService) EditTodo(ctx context.Context, id int64, title, description string, priority model.Priority, dueDate *time.Time) error {
 	todo, err := s.repo.GetByID(ctx, id)
@@ -91,122 +164,316 @@ func (s *TodoService) EditTodo(ctx context.Context, id int64, title, description
 		return err
 	}
 
+	before := copyTodo(todo)
 	todo.Title = strings.TrimSpace(title)
 	todo.Description = strings.TrimSpace(description)
 	todo.Priority = priority
 	todo.DueDate = dueDate
 	todo.UpdatedAt = time.Now()
 
-	return s.repo.Update(ctx, todo)
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionEdit, before, copyTodo(todo))
 }
 
-// DeleteTodo deletes a todo by ID
+// DeleteTodo moves a todo to the trash
 func (s *TodoService) DeleteTodo(ctx context.Context, id int64) error {
-	err := s.repo.Delete(ctx, id)
-	if err == repository.ErrTodoNotFound {
+	before, err := s.snapshotTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionDelete, before, nil)
+}
+
+// ListTrash returns the todos in the trash (most recently deleted first)
+func (s *TodoService) ListTrash(ctx context.Context) ([]*model.Todo, error) {
+	return s.repo.GetDeleted(ctx)
+}
+
+// RestoreFromTrash moves a deleted todo back out of the trash
+func (s *TodoService) RestoreFromTrash(ctx context.Context, id int64) error {
+	trash, err := s.repo.GetDeleted(ctx)
+	if err != nil {
+		return err
+	}
+
+	var before *model.Todo
+	for _, todo := range trash {
+		if todo.ID == id {
+			before = copyTodo(todo)
+			break
+		}
+	}
+	if before == nil {
 		return ErrTodoNotFound
 	}
-	return err
+
+	if err := s.repo.Undelete(ctx, id); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	after, err := s.snapshotTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionRestore, before, after)
 }
 
-// CompleteTodo marks a todo as completed
-func (s *TodoService) CompleteTodo(ctx context.Context, id int64) error {
-	err := s.repo.MarkAsCompleted(ctx, id)
+// PurgeTodo permanently deletes a todo in the trash
+func (s *TodoService) PurgeTodo(ctx context.Context, id int64) error {
+	err := s.repo.Purge(ctx, id)
 	if err == repository.ErrTodoNotFound {
 		return ErrTodoNotFound
 	}
 	return err
 }
 
-// ListTodos returns all todos
-func (s *TodoService) ListTodos(ctx context.Context) ([]*model.Todo, error) {
-	return s.repo.GetAll(ctx)
+// PurgeExpiredTrash permanently deletes the todos that have been in the trash
+// for longer than retention and returns how many were purged
+// A retention of zero or less keeps deleted todos forever.
+func (s *TodoService) PurgeExpiredTrash(ctx context.Context, now time.Time, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	return s.repo.PurgeDeletedBefore(ctx, now.Add(-retention))
 }
 
-// ListPendingTodos returns all pending todos
-func (s *TodoService) ListPendingTodos(ctx context.Context) ([]*model.Todo, error) {
-	return s.repo.GetByStatus(ctx, model.StatusPending)
+// ArchiveTodo archives a completed todo, hiding it from the default list
+func (s *TodoService) ArchiveTodo(ctx context.Context, id int64) error {
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	if !todo.IsCompleted() {
+		return ErrNotCompleted
+	}
+	if todo.IsArchived() {
+		return nil
+	}
+
+	before := copyTodo(todo)
+	now := time.Now()
+	todo.ArchivedAt = &now
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionArchive, before, copyTodo(todo))
 }
 
-// ListCompletedTodos returns all completed todos
-func (s *TodoService) ListCompletedTodos(ctx context.Context) ([]*model.Todo, error) {
-	return s.repo.GetByStatus(ctx, model.StatusCompleted)
+// BulkArchive archives several completed todos in a single transaction
+// Todos that are already archived are left alone.
+func (s *TodoService) BulkArchive(ctx context.Context, ids []int64) error {
+	todos, err := s.getTodos(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var unarchived []int64
+	for _, todo := range todos {
+		if !todo.IsCompleted() {
+			return fmt.Errorf("todo #%d: %w", todo.ID, ErrNotCompleted)
+		}
+		if !todo.IsArchived() {
+			unarchived = append(unarchived, todo.ID)
+		}
+	}
+	if len(unarchived) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	return s.bulkUpdate(ctx, unarchived, model.UndoActionArchive, func(todo *model.Todo) {
+		todo.ArchivedAt = &now
+	})
 }
 
-// ExportToJSON exports all todos to a JSON file
-func (s *TodoService) ExportToJSON(ctx context.Context, filepath string) error {
-	todos, err := s.repo.GetAll(ctx)
+// UnarchiveTodo brings an archived todo back to the default list
+func (s *TodoService) UnarchiveTodo(ctx context.Context, id int64) error {
+	todo, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return ErrExportFailed
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
 	}
 
-	data, err := json.MarshalIndent(todos, "", "  ")
+	if !todo.IsArchived() {
+		return ErrNotArchived
+	}
+
+	before := copyTodo(todo)
+	todo.ArchivedAt = nil
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionUnarchive, before, copyTodo(todo))
+}
+
+// SearchArchive returns the archived todos whose title or description contains query
+// (most recently archived first); an empty query returns the whole archive
+func (s *TodoService) SearchArchive(ctx context.Context, query string) ([]*model.Todo, error) {
+	return s.repo.GetArchived(ctx, strings.TrimSpace(query))
+}
+
+// AutoArchive archives the todos completed more than after ago and returns how many were archived
+// A duration of zero or less disables automatic archiving.
+func (s *TodoService) AutoArchive(ctx context.Context, now time.Time, after time.Duration) (int, error) {
+	if after <= 0 {
+		return 0, nil
+	}
+
+	ids, err := s.repo.ArchiveCompletedBefore(ctx, now.Add(-after))
 	if err != nil {
-		return ErrExportFailed
+		return 0, err
 	}
 
-	if err := os.WriteFile(filepath, data, 0600); err != nil {
-		return ErrExportFailed
+	events := make([]*model.TodoEvent, 0, len(ids))
+	for _, id := range ids {
+		event := model.ArchivedEvent(id, now)
+		event.Source = sourceFromContext(ctx)
+		events = append(events, event)
+	}
+	if err := s.repo.AddTodoEvents(ctx, events); err != nil {
+		return 0, fmt.Errorf("failed to record todo history: %w", err)
 	}
 
-	return nil
+	return len(ids), nil
 }
 
-// ImportFromJSON imports todos from a JSON file
-func (s *TodoService) ImportFromJSON(ctx context.Context, filepath string) error {
-	data, err := os.ReadFile(filepath)
+// ListTodoEvents returns the audit history of a todo (oldest first)
+func (s *TodoService) ListTodoEvents(ctx context.Context, id int64) ([]*model.TodoEvent, error) {
+	return s.repo.GetTodoEvents(ctx, id)
+}
+
+// CompleteTodo marks a todo as completed
+func (s *TodoService) CompleteTodo(ctx context.Context, id int64) error {
+	before, err := s.snapshotTodo(ctx, id)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return ErrFileNotFound
+		return err
+	}
+
+	if err := s.repo.MarkAsCompleted(ctx, id); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
 		}
-		return ErrImportFailed
+		return err
 	}
 
-	var todos []*model.Todo
-	if err := json.Unmarshal(data, &todos); err != nil {
-		return ErrInvalidJSON
+	after, err := s.snapshotTodo(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	// Import each todo (note: this creates new todos, doesn't preserve IDs)
-	for _, todo := range todos {
-		// Reset ID to create as new todo
-		todo.ID = 0
-		todo.CreatedAt = time.Now()
-		todo.UpdatedAt = time.Now()
+	return s.recordTodoChange(ctx, model.UndoActionComplete, before, after)
+}
 
-		if err := s.repo.Create(ctx, todo); err != nil {
-			return ErrImportFailed
+// BulkComplete marks several todos as completed in a single transaction
+func (s *TodoService) BulkComplete(ctx context.Context, ids []int64) error {
+	now := time.Now()
+	return s.bulkUpdate(ctx, ids, model.UndoActionComplete, func(todo *model.Todo) {
+		todo.Status = model.StatusCompleted
+		if todo.CompletedAt == nil {
+			todo.CompletedAt = &now
 		}
+	})
+}
+
+// BulkSetPriority sets the priority of several todos in a single transaction
+func (s *TodoService) BulkSetPriority(ctx context.Context, ids []int64, priority model.Priority) error {
+	if err := s.validatePriority(priority); err != nil {
+		return err
 	}
+	return s.bulkUpdate(ctx, ids, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.Priority = priority
+	})
+}
 
-	return nil
+// BulkSetDueDate sets (or clears, for nil) the due date of several todos in a single transaction
+func (s *TodoService) BulkSetDueDate(ctx context.Context, ids []int64, dueDate *time.Time) error {
+	return s.bulkUpdate(ctx, ids, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.DueDate = dueDate
+	})
 }
 
-// validateTitle validates the todo title
-func (s *TodoService) validateTitle(title string) error {
-	if strings.TrimSpace(title) == "" {
-		return ErrInvalidTitle
+// BulkDelete moves several todos to the trash in a single transaction
+func (s *TodoService) BulkDelete(ctx context.Context, ids []int64) error {
+	todos, err := s.getTodos(ctx, ids)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	before := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		before = append(before, copyTodo(todo))
+	}
+
+	if err := s.repo.DeleteMany(ctx, ids); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	return s.recordBulkChange(ctx, model.UndoActionDelete, before, nil)
 }
 
-// validatePriority validates the priority value
-func (s *TodoService) validatePriority(priority model.Priority) error {
-	if priority < model.PriorityLow || priority > model.PriorityHigh {
-		return ErrInvalidPriority
+// SetPriority sets the priority of a todo
+func (s *TodoService) SetPriority(ctx context.Context, id int64, priority model.Priority) error {
+	if err := s.validatePriority(priority); err != nil {
+		return err
 	}
-	return nil
+	return s.updateTodo(ctx, id, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.Priority = priority
+	})
 }
 
-// AddWorkDuration adds work duration (in minutes) to a todo
-// Returns an error if the todo doesn't exist or if the duration is invalid
-func (s *TodoService) AddWorkDuration(ctx context.Context, id int64, minutes int) error {
-	// Validate work duration (must be positive)
-	if minutes <= 0 {
-		return ErrInvalidWorkDuration
+// SetDueDate sets (or clears, for nil) the due date of a todo
+func (s *TodoService) SetDueDate(ctx context.Context, id int64, dueDate *time.Time) error {
+	return s.updateTodo(ctx, id, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.DueDate = dueDate
+	})
+}
+
+// RenameTodo changes the title of a todo
+func (s *TodoService) RenameTodo(ctx context.Context, id int64, title string) error {
+	if err := s.validateTitle(title); err != nil {
+		return err
 	}
+	return s.updateTodo(ctx, id, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.Title = strings.TrimSpace(title)
+	})
+}
 
-	// Check if todo exists
+// ReopenTodo marks a completed todo as pending again
+func (s *TodoService) ReopenTodo(ctx context.Context, id int64) error {
+	return s.updateTodo(ctx, id, model.UndoActionEdit, func(todo *model.Todo) {
+		todo.Status = model.StatusPending
+		todo.CompletedAt = nil
+	})
+}
+
+// updateTodo applies a change to a todo, stores it and records it in the undo history
+func (s *TodoService) updateTodo(ctx context.Context, id int64, action model.UndoAction, apply func(todo *model.Todo)) error {
 	todo, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrTodoNotFound {
@@ -215,10 +482,1119 @@ func (s *TodoService) AddWorkDuration(ctx context.Context, id int64, minutes int
 		return err
 	}
 
-	if todo == nil {
-		return ErrTodoNotFound
+	before := copyTodo(todo)
+	apply(todo)
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	return s.recordTodoChange(ctx, action, before, copyTodo(todo))
+}
+
+// bulkUpdate applies a change to several todos and stores them in a single transaction
+// The changes are recorded as one group, so a single undo reverts all of them.
+func (s *TodoService) bulkUpdate(ctx context.Context, ids []int64, action model.UndoAction, apply func(todo *model.Todo)) error {
+	todos, err := s.getTodos(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	before := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		before = append(before, copyTodo(todo))
+		apply(todo)
+	}
+
+	if err := s.repo.UpdateMany(ctx, todos); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	after := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		after = append(after, copyTodo(todo))
+	}
+
+	return s.recordBulkChange(ctx, action, before, after)
+}
+
+// getTodos returns the todos with the given IDs, in the same order
+func (s *TodoService) getTodos(ctx context.Context, ids []int64) ([]*model.Todo, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoSelection
+	}
+
+	todos := make([]*model.Todo, 0, len(ids))
+	for _, id := range ids {
+		todo, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			if err == repository.ErrTodoNotFound {
+				return nil, fmt.Errorf("todo #%d: %w", id, ErrTodoNotFound)
+			}
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	return todos, nil
+}
+
+// ListTodos returns all todos
+func (s *TodoService) ListTodos(ctx context.Context) ([]*model.Todo, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// ListVisibleTodos returns the todos that are not deferred at now,
+// along with the number of deferred todos that were hidden
+func (s *TodoService) ListVisibleTodos(ctx context.Context, now time.Time) ([]*model.Todo, int, error) {
+	todos, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	visible := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if !todo.IsDeferred(now) {
+			visible = append(visible, todo)
+		}
+	}
+	return visible, len(todos) - len(visible), nil
+}
+
+// TodoPage is a page of the todos that are not deferred
+type TodoPage struct {
+	Todos    []*model.Todo
+	Offset   int // Position of the first todo of the page in the list
+	Total    int // Number of todos in the list
+	Deferred int // Number of deferred todos left out of the list
+}
+
+// ListVisibleTodosPage returns up to limit todos that are not deferred at now,
+// starting at offset, along with the size of the list
+func (s *TodoService) ListVisibleTodosPage(ctx context.Context, now time.Time, offset, limit int) (*TodoPage, error) {
+	todos, err := s.repo.GetPage(ctx, now, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, deferred, err := s.repo.CountVisible(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	return &TodoPage{Todos: todos, Offset: offset, Total: total, Deferred: deferred}, nil
+}
+
+// ListDeferredTodos returns the todos that are deferred at now (earliest start first)
+func (s *TodoService) ListDeferredTodos(ctx context.Context, now time.Time) ([]*model.Todo, error) {
+	todos, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add work duration
-	return s.repo.AddWorkDuration(ctx, id, minutes)
+	var deferred []*model.Todo
+	for _, todo := range todos {
+		if todo.IsDeferred(now) {
+			deferred = append(deferred, todo)
+		}
+	}
+	sort.SliceStable(deferred, func(i, j int) bool {
+		return deferred[i].StartDate.Before(*deferred[j].StartDate)
+	})
+	return deferred, nil
+}
+
+// DeferTodo hides a todo from the default list until startDate
+// A nil startDate makes the todo visible again.
+func (s *TodoService) DeferTodo(ctx context.Context, id int64, startDate *time.Time) error {
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	before := copyTodo(todo)
+	todo.StartDate = startDate
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return err
+	}
+
+	return s.recordTodoChange(ctx, model.UndoActionEdit, before, copyTodo(todo))
+}
+
+// ListPendingTodos returns all pending todos
+func (s *TodoService) ListPendingTodos(ctx context.Context) ([]*model.Todo, error) {
+	return s.repo.GetByStatus(ctx, model.StatusPending)
+}
+
+// ListCompletedTodos returns all completed todos
+func (s *TodoService) ListCompletedTodos(ctx context.Context) ([]*model.Todo, error) {
+	return s.repo.GetByStatus(ctx, model.StatusCompleted)
+}
+
+// ExportToJSON exports all todos, archived ones included, to a JSON file
+func (s *TodoService) ExportToJSON(ctx context.Context, filepath string) error {
+	todos, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return ErrExportFailed
+	}
+	archived, err := s.repo.GetArchived(ctx, "")
+	if err != nil {
+		return ErrExportFailed
+	}
+	todos = append(todos, archived...)
+
+	return writeTodosJSON(todos, filepath)
+}
+
+// ExportSelectedToJSON exports the given todos to a JSON file
+func (s *TodoService) ExportSelectedToJSON(ctx context.Context, ids []int64, filepath string) error {
+	todos, err := s.getTodos(ctx, ids)
+	if err != nil {
+		return err
+	}
+	return writeTodosJSON(todos, filepath)
+}
+
+// writeTodosJSON writes todos to a JSON file in the export format
+func writeTodosJSON(todos []*model.Todo, filepath string) error {
+	data, err := json.MarshalIndent(todos, "", "  ")
+	if err != nil {
+		return ErrExportFailed
+	}
+
+	if err := os.WriteFile(filepath, data, 0600); err != nil {
+		return ErrExportFailed
+	}
+
+	return nil
+}
+
+// ImportFromJSON imports todos from a JSON file
+func (s *TodoService) ImportFromJSON(ctx context.Context, filepath string) error {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrFileNotFound
+		}
+		return ErrImportFailed
+	}
+
+	var todos []*model.Todo
+	if err := json.Unmarshal(data, &todos); err != nil {
+		return ErrInvalidJSON
+	}
+
+	// Import each todo (note: this creates new todos, doesn't preserve IDs)
+	ctx = WithSource(ctx, model.EventSourceImport)
+	for _, todo := range todos {
+		// Reset ID to create as new todo
+		todo.ID = 0
+		todo.CreatedAt = time.Now()
+		todo.UpdatedAt = time.Now()
+
+		if err := s.repo.Create(ctx, todo); err != nil {
+			return ErrImportFailed
+		}
+		if err := s.recordEvents(ctx, nil, todo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTitle validates the todo title
+func (s *TodoService) validateTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return ErrInvalidTitle
+	}
+	return nil
+}
+
+// validatePriority validates the priority value
+func (s *TodoService) validatePriority(priority model.Priority) error {
+	if priority < model.PriorityLow || priority > model.PriorityHigh {
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// AddWorkDuration adds work duration (in minutes) to a todo
+// Returns an error if the todo doesn't exist or if the duration is invalid
+func (s *TodoService) AddWorkDuration(ctx context.Context, id int64, minutes int) error {
+	return s.addPomodoroWork(ctx, id, minutes, model.WorkLogSourcePomodoro)
+}
+
+// AddPartialWorkDuration adds the work duration (in minutes) of a Pomodoro stopped early to a todo
+// The time counts towards the todo's total but not as a finished Pomodoro.
+func (s *TodoService) AddPartialWorkDuration(ctx context.Context, id int64, minutes int) error {
+	return s.addPomodoroWork(ctx, id, minutes, model.WorkLogSourcePartialPomodoro)
+}
+
+// addPomodoroWork records Pomodoro timer work for a todo with the given source
+func (s *TodoService) addPomodoroWork(ctx context.Context, id int64, minutes int, source model.WorkLogSource) error {
+	// Validate work duration (must be positive)
+	if minutes <= 0 {
+		return ErrInvalidWorkDuration
+	}
+
+	// Check if todo exists
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	if todo == nil {
+		return ErrTodoNotFound
+	}
+
+	// Add work duration as a Pomodoro entry
+	log := &model.WorkLog{
+		TodoID:   id,
+		Minutes:  minutes,
+		Source:   source,
+		LoggedAt: time.Now(),
+	}
+	if err := s.repo.AddWorkLog(ctx, log); err != nil {
+		return err
+	}
+
+	return s.recordWorkChange(ctx, log, 0, minutes)
+}
+
+// LogWork records work time for a todo, e.g. meetings or other off-keyboard work
+// Negative minutes remove time, but the todo's total may not drop below zero
+func (s *TodoService) LogWork(ctx context.Context, id int64, minutes int, note string, loggedAt time.Time) (*model.WorkLog, error) {
+	if minutes == 0 {
+		return nil, ErrEmptyWorkLog
+	}
+
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	if todo.WorkDuration+minutes < 0 {
+		return nil, ErrNegativeWorkTotal
+	}
+
+	log := &model.WorkLog{
+		TodoID:   id,
+		Minutes:  minutes,
+		Note:     strings.TrimSpace(note),
+		Source:   model.WorkLogSourceManual,
+		LoggedAt: loggedAt,
+	}
+	if err := s.repo.AddWorkLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordWorkChange(ctx, log, 0, minutes); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// ListWorkLogs returns the work log entries of a todo (newest first)
+func (s *TodoService) ListWorkLogs(ctx context.Context, todoID int64) ([]*model.WorkLog, error) {
+	return s.repo.GetWorkLogs(ctx, todoID)
+}
+
+// AdjustWorkLog changes the effective minutes of a work log entry
+// The original entry is kept and a correction entry is appended for the difference
+func (s *TodoService) AdjustWorkLog(ctx context.Context, logID int64, minutes int) error {
+	if minutes < 0 {
+		return ErrInvalidWorkDuration
+	}
+	return s.changeWorkLog(ctx, logID, minutes, "Adjusted entry #%d from %dm to %dm")
+}
+
+// DeleteWorkLog voids a work log entry by appending a correction that cancels it
+func (s *TodoService) DeleteWorkLog(ctx context.Context, logID int64) error {
+	return s.changeWorkLog(ctx, logID, 0, "Deleted entry #%d (was %dm, now %dm)")
+}
+
+// changeWorkLog corrects a work log entry and records the change in the undo history
+func (s *TodoService) changeWorkLog(ctx context.Context, logID int64, minutes int, noteFormat string) error {
+	entry, previous, err := s.correctWorkLog(ctx, logID, minutes, noteFormat)
+	if err != nil || previous == minutes {
+		return err
+	}
+	return s.recordWorkChange(ctx, entry, previous, minutes)
+}
+
+// correctWorkLog appends a correction so that the entry's effective minutes become minutes
+// noteFormat receives the entry ID, the current minutes and the new minutes.
+// Returns the corrected entry and its effective minutes before the correction.
+func (s *TodoService) correctWorkLog(ctx context.Context, logID int64, minutes int, noteFormat string) (*model.WorkLog, int, error) {
+	entry, current, correction, err := s.workLogCorrection(ctx, logID, minutes, noteFormat)
+	if err != nil || correction == nil {
+		return entry, current, err
+	}
+	return entry, current, s.repo.AddWorkLog(ctx, correction)
+}
+
+// workLogCorrection returns the entry, its effective minutes and the correction that
+// makes them minutes, without storing it (nil if the minutes already match)
+func (s *TodoService) workLogCorrection(ctx context.Context, logID int64, minutes int, noteFormat string) (*model.WorkLog, int, *model.WorkLog, error) {
+	entry, err := s.repo.GetWorkLog(ctx, logID)
+	if err != nil {
+		if err == repository.ErrWorkLogNotFound {
+			return nil, 0, nil, ErrWorkLogNotFound
+		}
+		return nil, 0, nil, err
+	}
+
+	if entry.IsCorrection() {
+		return nil, 0, nil, ErrInvalidCorrection
+	}
+
+	logs, err := s.repo.GetWorkLogs(ctx, entry.TodoID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	current := model.EffectiveWorkMinutes(logs)[entry.ID]
+	delta := minutes - current
+	if delta == 0 {
+		return entry, current, nil, nil
+	}
+
+	todo, err := s.repo.GetByID(ctx, entry.TodoID)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, 0, nil, ErrTodoNotFound
+		}
+		return nil, 0, nil, err
+	}
+
+	if todo.WorkDuration+delta < 0 {
+		return nil, 0, nil, ErrNegativeWorkTotal
+	}
+
+	return entry, current, &model.WorkLog{
+		TodoID:     entry.TodoID,
+		Minutes:    delta,
+		Note:       fmt.Sprintf(noteFormat, entry.ID, current, minutes),
+		Source:     model.WorkLogSourceCorrection,
+		CorrectsID: &entry.ID,
+		LoggedAt:   entry.LoggedAt,
+	}, nil
+}
+
+// StopwatchResult describes the time recorded when a stopwatch was stopped
+type StopwatchResult struct {
+	TodoID  int64 // Todo the time was recorded for
+	Minutes int   // Whole minutes recorded (0 if less than a minute elapsed)
+}
+
+// GetStopwatch returns the running stopwatch (nil if none is running)
+func (s *TodoService) GetStopwatch(ctx context.Context) (*model.Stopwatch, error) {
+	return s.repo.GetStopwatch(ctx)
+}
+
+// StartStopwatch starts tracking open-ended work on a todo
+// A stopwatch already running for another todo is stopped and its time recorded first;
+// the returned result describes that previous stopwatch (nil if there was none).
+func (s *TodoService) StartStopwatch(ctx context.Context, id int64) (*StopwatchResult, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	running, err := s.repo.GetStopwatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Starting the same todo again keeps the current session
+	if running != nil && running.TodoID == id {
+		return nil, nil
+	}
+
+	var previous *StopwatchResult
+	if running != nil {
+		previous, err = s.StopStopwatch(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stopwatch := &model.Stopwatch{TodoID: id, StartedAt: time.Now()}
+	if err := s.repo.SetStopwatch(ctx, stopwatch); err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// StopStopwatch stops the running stopwatch and records the elapsed whole minutes
+// through the same work log accounting as the Pomodoro timer
+func (s *TodoService) StopStopwatch(ctx context.Context) (*StopwatchResult, error) {
+	running, err := s.repo.GetStopwatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if running == nil {
+		return nil, ErrNoStopwatch
+	}
+
+	if err := s.repo.ClearStopwatch(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &StopwatchResult{
+		TodoID:  running.TodoID,
+		Minutes: running.ElapsedMinutes(time.Now()),
+	}
+
+	if result.Minutes > 0 {
+		log := &model.WorkLog{
+			TodoID:   running.TodoID,
+			Minutes:  result.Minutes,
+			Source:   model.WorkLogSourceStopwatch,
+			LoggedAt: running.StartedAt,
+		}
+		err := s.repo.AddWorkLog(ctx, log)
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.recordWorkChange(ctx, log, 0, result.Minutes); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetTimesheet aggregates tracked work per day and per todo for [from, to)
+// from and to are truncated to midnight so that whole days are reported
+func (s *TodoService) GetTimesheet(ctx context.Context, from, to time.Time) (*model.Timesheet, error) {
+	from = timeutil.StartOfDay(from)
+	to = timeutil.StartOfDay(to)
+	if !to.After(from) {
+		return nil, ErrInvalidDateRange
+	}
+
+	entries, err := s.repo.GetDailyWork(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewTimesheet(from, to, entries), nil
+}
+
+// StatsDays is the number of days covered by the per-day statistics
+const StatsDays = 14
+
+// GetStats computes productivity statistics as of now
+// Per-day figures cover the last StatsDays days including today, and
+// focus time compares this week (from Monday) with the previous week.
+func (s *TodoService) GetStats(ctx context.Context, now time.Time) (*model.Stats, error) {
+	today := timeutil.StartOfDay(now)
+	from := today.AddDate(0, 0, -(StatsDays - 1))
+	to := today.AddDate(0, 0, 1)
+
+	stats := &model.Stats{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		stats.Days = append(stats.Days, day)
+	}
+
+	completed, err := s.repo.CountCompletedByDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats.CompletedPerDay = model.DayCounts(stats.Days, completed)
+
+	pomodoros, err := s.repo.CountPomodorosByDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats.PomodorosPerDay = model.DayCounts(stats.Days, pomodoros)
+
+	thisWeek := timeutil.StartOfWeek(now)
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+	if stats.FocusThisWeek, err = s.repo.SumWorkMinutes(ctx, thisWeek, to); err != nil {
+		return nil, err
+	}
+	if stats.FocusLastWeek, err = s.repo.SumWorkMinutes(ctx, lastWeek, thisWeek); err != nil {
+		return nil, err
+	}
+
+	if stats.AvgCompletion, stats.CompletedTotal, err = s.repo.GetAverageCompletionTime(ctx); err != nil {
+		return nil, err
+	}
+
+	if stats.OpenByPriority, err = s.repo.CountOpenByPriority(ctx); err != nil {
+		return nil, err
+	}
+
+	completionDays, err := s.repo.GetCompletionDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.CurrentStreak, stats.StreakIncludesToday = model.CurrentStreak(completionDays, now)
+
+	return stats, nil
+}
+
+// goalHistoryDays is how far back the daily goal streak is counted
+const goalHistoryDays = 366
+
+// GetGoalProgress computes today's progress towards the daily goal and the
+// streak of consecutive days on which the goal was met
+func (s *TodoService) GetGoalProgress(ctx context.Context, goal model.DailyGoal, now time.Time) (*model.GoalProgress, error) {
+	progress := &model.GoalProgress{Goal: goal}
+	if !goal.IsSet() {
+		return progress, nil
+	}
+
+	today := timeutil.StartOfDay(now)
+	from := today.AddDate(0, 0, -goalHistoryDays)
+	to := today.AddDate(0, 0, 1)
+
+	var days []time.Time
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	pomodoroCounts, err := s.repo.CountPomodorosByDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	minuteCounts, err := s.repo.SumWorkMinutesByDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	pomodoros := model.DayCounts(days, pomodoroCounts)
+	minutes := model.DayCounts(days, minuteCounts)
+
+	var metDays []time.Time
+	for i, day := range days {
+		if goal.Met(pomodoros[i], minutes[i]) {
+			metDays = append(metDays, day)
+		}
+	}
+
+	last := len(days) - 1
+	progress.Pomodoros = pomodoros[last]
+	progress.Minutes = minutes[last]
+	progress.Streak, progress.StreakIncludesToday = model.CurrentStreak(metDays, now)
+
+	return progress, nil
+}
+
+// overdueReminderWindow limits overdue reminders to todos that became overdue
+// recently, so that starting the reminder daemon does not report old backlog
+const overdueReminderWindow = 24 * time.Hour
+
+// ClaimDueReminders returns the due date reminders to deliver at now and
+// records them as sent, so each reminder is delivered only once
+// offsets are the minutes before the due date at which to remind; once a todo
+// is overdue a final reminder with offset 0 is sent. When several offsets have
+// passed since the last check only the closest one to the due date is sent.
+func (s *TodoService) ClaimDueReminders(ctx context.Context, now time.Time, offsets []int) ([]*model.DueReminder, error) {
+	maxOffset := 0
+	for _, offset := range offsets {
+		maxOffset = max(maxOffset, offset)
+	}
+
+	todos, err := s.repo.GetPendingDueBefore(ctx, now.Add(time.Duration(maxOffset)*time.Minute+time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []*model.DueReminder
+	for _, todo := range todos {
+		due := *todo.DueDate
+
+		stage := -1
+		if !now.Before(due) {
+			if now.Sub(due) > overdueReminderWindow {
+				continue
+			}
+			stage = 0
+		} else {
+			for _, offset := range offsets {
+				reached := !now.Before(due.Add(-time.Duration(offset) * time.Minute))
+				if offset > 0 && reached && (stage < 0 || offset < stage) {
+					stage = offset
+				}
+			}
+		}
+		if stage < 0 {
+			continue
+		}
+
+		claimed, err := s.repo.MarkReminderSent(ctx, todo.ID, due, stage)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			reminders = append(reminders, &model.DueReminder{Todo: todo, OffsetMinutes: stage})
+		}
+	}
+
+	return reminders, nil
+}
+
+// ReleaseDueReminder forgets that a claimed due date reminder was sent, e.g.
+// because its notification failed, so that it is claimed again on the next check
+func (s *TodoService) ReleaseDueReminder(ctx context.Context, reminder *model.DueReminder) error {
+	return s.repo.UnmarkReminderSent(ctx, reminder.Todo.ID, *reminder.Todo.DueDate, reminder.OffsetMinutes)
+}
+
+// AddReminder sets a reminder on a todo for the given time
+func (s *TodoService) AddReminder(ctx context.Context, todoID int64, remindAt, now time.Time) (*model.Reminder, error) {
+	if !remindAt.After(now) {
+		return nil, ErrReminderInPast
+	}
+
+	reminder := &model.Reminder{TodoID: todoID, RemindAt: remindAt}
+	if err := s.repo.AddReminder(ctx, reminder); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// ListReminders returns the active reminders of a todo (earliest first)
+func (s *TodoService) ListReminders(ctx context.Context, todoID int64) ([]*model.Reminder, error) {
+	return s.repo.GetReminders(ctx, todoID)
+}
+
+// ClearReminders removes the active reminders of a todo and returns how many were removed
+func (s *TodoService) ClearReminders(ctx context.Context, todoID int64) (int, error) {
+	if _, err := s.repo.GetByID(ctx, todoID); err != nil {
+		if err == repository.ErrTodoNotFound {
+			return 0, ErrTodoNotFound
+		}
+		return 0, err
+	}
+	return s.repo.DeleteReminders(ctx, todoID)
+}
+
+// ActiveReminders returns the reminders that are due at now and not dismissed yet
+func (s *TodoService) ActiveReminders(ctx context.Context, now time.Time) ([]*model.Reminder, error) {
+	return s.repo.GetDueReminders(ctx, now)
+}
+
+// ClaimReminders returns the due reminders that have not been notified yet and
+// marks them as sent, so each is notified only once across processes
+func (s *TodoService) ClaimReminders(ctx context.Context, now time.Time) ([]*model.Reminder, error) {
+	due, err := s.repo.GetDueReminders(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []*model.Reminder
+	for _, reminder := range due {
+		if reminder.SentAt != nil {
+			continue
+		}
+		ok, err := s.repo.ClaimReminder(ctx, reminder.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, reminder)
+		}
+	}
+
+	return claimed, nil
+}
+
+// ReleaseReminder marks a claimed reminder as not sent, e.g. because its
+// notification failed, so that it is claimed again on the next check
+func (s *TodoService) ReleaseReminder(ctx context.Context, id int64) error {
+	err := s.repo.ReleaseReminder(ctx, id)
+	if err == repository.ErrReminderNotFound {
+		return ErrReminderNotFound
+	}
+	return err
+}
+
+// SnoozeReminder moves a reminder to a later time
+func (s *TodoService) SnoozeReminder(ctx context.Context, id int64, until time.Time) error {
+	err := s.repo.RescheduleReminder(ctx, id, until)
+	if err == repository.ErrReminderNotFound {
+		return ErrReminderNotFound
+	}
+	return err
+}
+
+// DismissReminder dismisses a reminder
+func (s *TodoService) DismissReminder(ctx context.Context, id int64) error {
+	err := s.repo.DismissReminder(ctx, id)
+	if err == repository.ErrReminderNotFound {
+		return ErrReminderNotFound
+	}
+	return err
+}
+
+// Undo reverts the latest change that is still in effect and returns it
+// The changes of a bulk operation are reverted together.
+func (s *TodoService) Undo(ctx context.Context) (*model.UndoEntry, error) {
+	entry, err := s.repo.GetUndoEntry(ctx)
+	if err == repository.ErrUndoEntryNotFound {
+		return nil, ErrNothingToUndo
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.undoGroup(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revert newest first
+	change := &model.UndoChange{Undone: true}
+	for i := len(group) - 1; i >= 0; i-- {
+		undone := group[i]
+		if err := s.planUndoEntry(ctx, change, undone, undone.After, undone.Before, undone.MinutesBefore, "Undid change to entry #%d (%dm → %dm)"); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.ApplyUndo(ctx, change); err != nil {
+		return nil, err
+	}
+
+	entry.GroupSize = len(group)
+	return entry, nil
+}
+
+// Redo applies the most recently undone change again and returns it
+// The changes of a bulk operation are applied together.
+func (s *TodoService) Redo(ctx context.Context) (*model.UndoEntry, error) {
+	entry, err := s.repo.GetRedoEntry(ctx)
+	if err == repository.ErrUndoEntryNotFound {
+		return nil, ErrNothingToRedo
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.undoGroup(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &model.UndoChange{}
+	for _, redone := range group {
+		if err := s.planUndoEntry(ctx, change, redone, redone.Before, redone.After, redone.MinutesAfter, "Redid change to entry #%d (%dm → %dm)"); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.ApplyUndo(ctx, change); err != nil {
+		return nil, err
+	}
+
+	entry.GroupSize = len(group)
+	return entry, nil
+}
+
+// undoGroup returns the changes undone or redone together with entry (oldest first)
+func (s *TodoService) undoGroup(ctx context.Context, entry *model.UndoEntry) ([]*model.UndoEntry, error) {
+	if entry.GroupID == nil {
+		return []*model.UndoEntry{entry}, nil
+	}
+	return s.repo.GetUndoGroup(ctx, *entry.GroupID)
+}
+
+// planUndoEntry adds the writes to change that bring the todo of a change from one
+// snapshot to the other (deleting it for nil), or the work log entry of a work change
+// to the given minutes
+func (s *TodoService) planUndoEntry(ctx context.Context, change *model.UndoChange, entry *model.UndoEntry, from, todo *model.Todo, minutes int, noteFormat string) error {
+	change.EntryIDs = append(change.EntryIDs, entry.ID)
+
+	if entry.Action == model.UndoActionWork {
+		if entry.WorkLogID == nil {
+			return fmt.Errorf("undo entry #%d has no work log entry", entry.ID)
+		}
+		_, _, correction, err := s.workLogCorrection(ctx, *entry.WorkLogID, minutes, noteFormat)
+		if err != nil {
+			return err
+		}
+		if correction != nil {
+			change.WorkLogs = append(change.WorkLogs, correction)
+		}
+		return nil
+	}
+
+	current, err := s.currentTodo(ctx, entry.TodoID)
+	if err != nil {
+		return err
+	}
+
+	if todo == nil {
+		if current == nil || current.IsDeleted() {
+			return nil // Already gone
+		}
+		change.Delete = append(change.Delete, entry.TodoID)
+		change.Events = append(change.Events, todoEvents(ctx, from, nil)...)
+		return nil
+	}
+
+	restored := todo
+	if current != nil && from != nil {
+		// Leave alone what changed since, e.g. archiving by auto-archive
+		restored = mergeTodoChange(current, from, todo)
+	}
+	change.Restore = append(change.Restore, restored)
+
+	switch {
+	case current != nil:
+		from = current
+	case from == nil:
+		// The todo has been in the trash since the change was made (or undone)
+		deletedAt := entry.CreatedAt
+		if entry.UndoneAt != nil {
+			deletedAt = *entry.UndoneAt
+		}
+		from = copyTodo(todo)
+		from.DeletedAt = &deletedAt
+	}
+	change.Events = append(change.Events, todoEvents(ctx, from, restored)...)
+	return nil
+}
+
+// currentTodo returns a todo as it is now, also in the trash (nil if it no longer exists)
+func (s *TodoService) currentTodo(ctx context.Context, id int64) (*model.Todo, error) {
+	todo, err := s.repo.GetByID(ctx, id)
+	if err == nil {
+		return copyTodo(todo), nil
+	}
+	if err != repository.ErrTodoNotFound {
+		return nil, err
+	}
+
+	deleted, err := s.repo.GetDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range deleted {
+		if todo.ID == id {
+			return copyTodo(todo), nil
+		}
+	}
+	return nil, nil
+}
+
+// mergeTodoChange applies the change from one snapshot to the other to the current todo
+// Only the fields that differ between the snapshots are set; the other fields
+// keep their current value.
+func mergeTodoChange(current, from, to *model.Todo) *model.Todo {
+	merged := copyTodo(current)
+	if from.Title != to.Title {
+		merged.Title = to.Title
+	}
+	if from.Description != to.Description {
+		merged.Description = to.Description
+	}
+	if from.Status != to.Status {
+		merged.Status = to.Status
+	}
+	if from.Priority != to.Priority {
+		merged.Priority = to.Priority
+	}
+	if !sameTime(from.DueDate, to.DueDate) {
+		merged.DueDate = to.DueDate
+	}
+	if !sameTime(from.CompletedAt, to.CompletedAt) {
+		merged.CompletedAt = to.CompletedAt
+	}
+	if !sameTime(from.StartDate, to.StartDate) {
+		merged.StartDate = to.StartDate
+	}
+	if !sameTime(from.DeletedAt, to.DeletedAt) {
+		merged.DeletedAt = to.DeletedAt
+	}
+	if !sameTime(from.ArchivedAt, to.ArchivedAt) {
+		merged.ArchivedAt = to.ArchivedAt
+	}
+	if !slices.Equal(from.Tags, to.Tags) {
+		merged.Tags = slices.Clone(to.Tags)
+	}
+	return merged
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// snapshotTodo returns a copy of a todo for the undo history
+func (s *TodoService) snapshotTodo(ctx context.Context, id int64) (*model.Todo, error) {
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrTodoNotFound {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+	return copyTodo(todo), nil
+}
+
+// copyTodo returns a shallow copy of a todo, so later changes do not alter a snapshot
+func copyTodo(todo *model.Todo) *model.Todo {
+	snapshot := *todo
+	snapshot.Tags = slices.Clone(todo.Tags)
+	return &snapshot
+}
+
+// recordTodoChange records a change of a todo in the undo history
+// before is nil for a created todo and after is nil for a deleted one.
+func (s *TodoService) recordTodoChange(ctx context.Context, action model.UndoAction, before, after *model.Todo) error {
+	entry := &model.UndoEntry{Action: action, Before: before, After: after}
+	if after != nil {
+		entry.TodoID, entry.Title = after.ID, after.Title
+	} else {
+		entry.TodoID, entry.Title = before.ID, before.Title
+	}
+
+	if err := s.repo.AddUndoEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record undo history: %w", err)
+	}
+	return s.recordEvents(ctx, before, after)
+}
+
+// recordEvents records the field-level changes between two snapshots of a todo in the audit history
+// before is nil for a created todo and after is nil for a todo moved to the trash.
+func (s *TodoService) recordEvents(ctx context.Context, before, after *model.Todo) error {
+	if err := s.repo.AddTodoEvents(ctx, todoEvents(ctx, before, after)); err != nil {
+		return fmt.Errorf("failed to record todo history: %w", err)
+	}
+	return nil
+}
+
+// todoEvents returns the field-level changes between two snapshots of a todo, like recordEvents
+func todoEvents(ctx context.Context, before, after *model.Todo) []*model.TodoEvent {
+	if after == nil {
+		now := time.Now()
+		after = copyTodo(before)
+		after.DeletedAt = &now
+	}
+
+	events := model.TodoChanges(before, after)
+	source := sourceFromContext(ctx)
+	for _, event := range events {
+		event.Source = source
+	}
+	return events
+}
+
+// recordBulkChange records the changes of a bulk operation as one group in the undo history
+// after is nil for a bulk delete.
+func (s *TodoService) recordBulkChange(ctx context.Context, action model.UndoAction, before, after []*model.Todo) error {
+	entries := make([]*model.UndoEntry, 0, len(before))
+	for i, todo := range before {
+		entry := &model.UndoEntry{Action: action, TodoID: todo.ID, Title: todo.Title, Before: todo}
+		if after != nil {
+			entry.After = after[i]
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := s.repo.AddUndoEntries(ctx, entries); err != nil {
+		return fmt.Errorf("failed to record undo history: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err := s.recordEvents(ctx, entry.Before, entry.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordWorkChange records a change of a work log entry's effective minutes in the undo history
+func (s *TodoService) recordWorkChange(ctx context.Context, log *model.WorkLog, before, after int) error {
+	entry := &model.UndoEntry{
+		Action:        model.UndoActionWork,
+		TodoID:        log.TodoID,
+		WorkLogID:     &log.ID,
+		MinutesBefore: before,
+		MinutesAfter:  after,
+	}
+	if todo, err := s.repo.GetByID(ctx, log.TodoID); err == nil {
+		entry.Title = todo.Title
+	}
+
+	if err := s.repo.AddUndoEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record undo history: %w", err)
+	}
+	return nil
+}
+
+// ExportTimesheetToCSV writes a timesheet to a CSV file
+// Columns are the todo ID, title, minutes per day and total minutes,
+// followed by a totals row.
+func (s *TodoService) ExportTimesheetToCSV(ts *model.Timesheet, filepath string) error {
+	file, err := os.OpenFile(filepath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return ErrExportFailed
+	}
+
+	w := csv.NewWriter(file)
+
+	header := []string{"Todo ID", "Title"}
+	for _, day := range ts.Days {
+		header = append(header, day.Format("2006-01-02"))
+	}
+	header = append(header, "Total (minutes)")
+	_ = w.Write(header)
+
+	for _, row := range ts.Rows {
+		record := []string{strconv.FormatInt(row.TodoID, 10), row.Title}
+		for _, minutes := range row.Minutes {
+			record = append(record, strconv.Itoa(minutes))
+		}
+		record = append(record, strconv.Itoa(row.Total))
+		_ = w.Write(record)
+	}
+
+	totals := []string{"", "Total"}
+	for _, minutes := range ts.DayTotals {
+		totals = append(totals, strconv.Itoa(minutes))
+	}
+	totals = append(totals, strconv.Itoa(ts.Total))
+	_ = w.Write(totals)
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		_ = file.Close()
+		return ErrExportFailed
+	}
+
+	if err := file.Close(); err != nil {
+		return ErrExportFailed
+	}
+
+	return nil
 }
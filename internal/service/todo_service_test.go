@@ -2,19 +2,32 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/syeeel/koto-cli-go/internal/model"
 	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
 )
 
 // mockRepository is a simple in-memory implementation for testing
 type mockRepository struct {
-	todos  map[int64]*model.Todo
-	nextID int64
+	todos     map[int64]*model.Todo
+	nextID    int64
+	logs      []*model.WorkLog
+	stopwatch *model.Stopwatch
+	sent      map[string]bool
+	reminders []*model.Reminder
+	undo      []*model.UndoEntry
+	events    []*model.TodoEvent
 }
 
 func newMockRepository() *mockRepository {
@@ -33,7 +46,7 @@ func (m *mockRepository) Create(ctx context.Context, todo *model.Todo) error {
 
 func (m *mockRepository) GetByID(ctx context.Context, id int64) (*model.Todo, error) {
 	todo, exists := m.todos[id]
-	if !exists {
+	if !exists || todo.IsDeleted() {
 		return nil, repository.ErrTodoNotFound
 	}
 	return todo, nil
@@ -42,15 +55,68 @@ func (m *mockRepository) GetByID(ctx context.Context, id int64) (*model.Todo, er
 func (m *mockRepository) GetAll(ctx context.Context) ([]*model.Todo, error) {
 	todos := make([]*model.Todo, 0, len(m.todos))
 	for _, todo := range m.todos {
-		todos = append(todos, todo)
+		if !todo.IsDeleted() && !todo.IsArchived() {
+			todos = append(todos, todo)
+		}
 	}
 	return todos, nil
 }
 
+func (m *mockRepository) GetPage(ctx context.Context, now time.Time, offset, limit int) ([]*model.Todo, error) {
+	todos, _ := m.GetAll(ctx)
+	visible := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if !todo.IsDeferred(now) {
+			visible = append(visible, todo)
+		}
+	}
+	// Newest first, like the SQLite repository
+	sort.Slice(visible, func(i, j int) bool { return visible[i].ID > visible[j].ID })
+	if offset >= len(visible) {
+		return nil, nil
+	}
+	return visible[offset:min(offset+limit, len(visible))], nil
+}
+
+func (m *mockRepository) SearchTodos(ctx context.Context, terms []string, fuzzy bool, limit int) ([]*model.Todo, error) {
+	todos, _ := m.GetAll(ctx)
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID > todos[j].ID })
+	var matches []*model.Todo
+	for _, todo := range todos {
+		title := strings.ToLower(todo.Title)
+		matched := true
+		for _, term := range terms {
+			matched = matched && strings.Contains(title, strings.ToLower(term))
+		}
+		if !matched && len(terms) == 1 && !fuzzy {
+			matched = strings.HasPrefix(fmt.Sprint(todo.ID), terms[0])
+		}
+		if matched && len(matches) < limit {
+			matches = append(matches, todo)
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockRepository) CountVisible(ctx context.Context, now time.Time) (int, int, error) {
+	var visible, deferred int
+	for _, todo := range m.todos {
+		if todo.IsDeleted() || todo.IsArchived() {
+			continue
+		}
+		if todo.IsDeferred(now) {
+			deferred++
+		} else {
+			visible++
+		}
+	}
+	return visible, deferred, nil
+}
+
 func (m *mockRepository) GetByStatus(ctx context.Context, status model.TodoStatus) ([]*model.Todo, error) {
 	todos := make([]*model.Todo, 0)
 	for _, todo := range m.todos {
-		if todo.Status == status {
+		if todo.Status == status && !todo.IsDeleted() && !todo.IsArchived() {
 			todos = append(todos, todo)
 		}
 	}
@@ -65,21 +131,113 @@ func (m *mockRepository) Update(ctx context.Context, todo *model.Todo) error {
 	return nil
 }
 
+func (m *mockRepository) UpdateMany(ctx context.Context, todos []*model.Todo) error {
+	for _, todo := range todos {
+		if _, exists := m.todos[todo.ID]; !exists {
+			return repository.ErrTodoNotFound
+		}
+	}
+	for _, todo := range todos {
+		m.todos[todo.ID] = todo
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteMany(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		if todo, exists := m.todos[id]; !exists || todo.IsDeleted() {
+			return repository.ErrTodoNotFound
+		}
+	}
+	for _, id := range ids {
+		_ = m.Delete(ctx, id)
+	}
+	return nil
+}
+
 func (m *mockRepository) Delete(ctx context.Context, id int64) error {
-	if _, exists := m.todos[id]; !exists {
+	todo, exists := m.todos[id]
+	if !exists || todo.IsDeleted() {
+		return repository.ErrTodoNotFound
+	}
+	now := time.Now()
+	todo.DeletedAt = &now
+	return nil
+}
+
+func (m *mockRepository) GetDeleted(ctx context.Context) ([]*model.Todo, error) {
+	todos := make([]*model.Todo, 0)
+	for _, todo := range m.todos {
+		if todo.IsDeleted() {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
+func (m *mockRepository) Undelete(ctx context.Context, id int64) error {
+	todo, exists := m.todos[id]
+	if !exists || !todo.IsDeleted() {
+		return repository.ErrTodoNotFound
+	}
+	todo.DeletedAt = nil
+	return nil
+}
+
+func (m *mockRepository) Purge(ctx context.Context, id int64) error {
+	todo, exists := m.todos[id]
+	if !exists || !todo.IsDeleted() {
 		return repository.ErrTodoNotFound
 	}
 	delete(m.todos, id)
 	return nil
 }
 
+func (m *mockRepository) GetArchived(ctx context.Context, query string) ([]*model.Todo, error) {
+	todos := make([]*model.Todo, 0)
+	for _, todo := range m.todos {
+		if todo.IsArchived() && !todo.IsDeleted() &&
+			(strings.Contains(todo.Title, query) || strings.Contains(todo.Description, query)) {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
+func (m *mockRepository) ArchiveCompletedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	var ids []int64
+	for _, todo := range m.todos {
+		if todo.IsCompleted() && !todo.IsArchived() && !todo.IsDeleted() && todo.CompletedAt.Before(before) {
+			now := time.Now()
+			todo.ArchivedAt = &now
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for id, todo := range m.todos {
+		if todo.IsDeleted() && todo.DeletedAt.Before(before) {
+			delete(m.todos, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (m *mockRepository) MarkAsCompleted(ctx context.Context, id int64) error {
 	todo, exists := m.todos[id]
 	if !exists {
 		return repository.ErrTodoNotFound
 	}
+	now := time.Now()
 	todo.Status = model.StatusCompleted
-	todo.UpdatedAt = time.Now()
+	if todo.CompletedAt == nil {
+		todo.CompletedAt = &now
+	}
+	todo.UpdatedAt = now
 	return nil
 }
 
@@ -93,6 +251,384 @@ func (m *mockRepository) AddWorkDuration(ctx context.Context, id int64, minutes
 	return nil
 }
 
+func (m *mockRepository) AddWorkLog(ctx context.Context, log *model.WorkLog) error {
+	todo, exists := m.todos[log.TodoID]
+	if !exists {
+		return repository.ErrTodoNotFound
+	}
+	todo.WorkDuration += log.Minutes
+	log.ID = int64(len(m.logs) + 1)
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func (m *mockRepository) GetWorkLog(ctx context.Context, id int64) (*model.WorkLog, error) {
+	for _, log := range m.logs {
+		if log.ID == id {
+			return log, nil
+		}
+	}
+	return nil, repository.ErrWorkLogNotFound
+}
+
+func (m *mockRepository) GetWorkLogs(ctx context.Context, todoID int64) ([]*model.WorkLog, error) {
+	logs := make([]*model.WorkLog, 0)
+	for _, log := range m.logs {
+		if log.TodoID == todoID {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (m *mockRepository) GetDailyWork(ctx context.Context, from, to time.Time) ([]model.DailyWork, error) {
+	entries := make([]model.DailyWork, 0)
+	for _, log := range m.logs {
+		if log.LoggedAt.Before(from) || !log.LoggedAt.Before(to) {
+			continue
+		}
+		day := time.Date(log.LoggedAt.Year(), log.LoggedAt.Month(), log.LoggedAt.Day(), 0, 0, 0, 0, log.LoggedAt.Location())
+		entries = append(entries, model.DailyWork{
+			TodoID:  log.TodoID,
+			Title:   m.todos[log.TodoID].Title,
+			Day:     day,
+			Minutes: log.Minutes,
+		})
+	}
+	return entries, nil
+}
+
+func (m *mockRepository) CountCompletedByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	counts := make([]model.DayCount, 0)
+	for _, todo := range m.todos {
+		if todo.CompletedAt == nil || todo.IsDeleted() || todo.CompletedAt.Before(from) || !todo.CompletedAt.Before(to) {
+			continue
+		}
+		counts = append(counts, model.DayCount{Day: timeutil.StartOfDay(*todo.CompletedAt), Count: 1})
+	}
+	return counts, nil
+}
+
+func (m *mockRepository) CountPomodorosByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	counts := make([]model.DayCount, 0)
+	for _, log := range m.logs {
+		if log.Source != model.WorkLogSourcePomodoro || log.Minutes <= 0 || log.LoggedAt.Before(from) || !log.LoggedAt.Before(to) {
+			continue
+		}
+		counts = append(counts, model.DayCount{Day: timeutil.StartOfDay(log.LoggedAt), Count: 1})
+	}
+	return counts, nil
+}
+
+func (m *mockRepository) SumWorkMinutes(ctx context.Context, from, to time.Time) (int, error) {
+	minutes := 0
+	for _, log := range m.logs {
+		if !log.LoggedAt.Before(from) && log.LoggedAt.Before(to) {
+			minutes += log.Minutes
+		}
+	}
+	return minutes, nil
+}
+
+func (m *mockRepository) SumWorkMinutesByDay(ctx context.Context, from, to time.Time) ([]model.DayCount, error) {
+	counts := make([]model.DayCount, 0)
+	for _, log := range m.logs {
+		if log.LoggedAt.Before(from) || !log.LoggedAt.Before(to) {
+			continue
+		}
+		counts = append(counts, model.DayCount{Day: timeutil.StartOfDay(log.LoggedAt), Count: log.Minutes})
+	}
+	return counts, nil
+}
+
+func (m *mockRepository) GetAverageCompletionTime(ctx context.Context) (time.Duration, int, error) {
+	var total time.Duration
+	count := 0
+	for _, todo := range m.todos {
+		if todo.CompletedAt != nil && !todo.IsDeleted() {
+			total += todo.CompletedAt.Sub(todo.CreatedAt)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return total / time.Duration(count), count, nil
+}
+
+func (m *mockRepository) CountOpenByPriority(ctx context.Context) (map[model.Priority]int, error) {
+	counts := make(map[model.Priority]int)
+	for _, todo := range m.todos {
+		if todo.IsPending() {
+			counts[todo.Priority]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *mockRepository) GetCompletionDays(ctx context.Context) ([]time.Time, error) {
+	days := make([]time.Time, 0)
+	for _, todo := range m.todos {
+		if todo.CompletedAt != nil && !todo.IsDeleted() {
+			days = append(days, timeutil.StartOfDay(*todo.CompletedAt))
+		}
+	}
+	return days, nil
+}
+
+func (m *mockRepository) GetPendingDueBefore(ctx context.Context, before time.Time) ([]*model.Todo, error) {
+	result := make([]*model.Todo, 0)
+	for _, todo := range m.todos {
+		if todo.IsPending() && todo.DueDate != nil && todo.DueDate.Before(before) {
+			result = append(result, todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRepository) MarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) (bool, error) {
+	if m.sent == nil {
+		m.sent = make(map[string]bool)
+	}
+	key := fmt.Sprintf("%d/%d/%d", todoID, dueDate.Unix(), offsetMinutes)
+	if m.sent[key] {
+		return false, nil
+	}
+	m.sent[key] = true
+	return true, nil
+}
+
+func (m *mockRepository) UnmarkReminderSent(ctx context.Context, todoID int64, dueDate time.Time, offsetMinutes int) error {
+	delete(m.sent, fmt.Sprintf("%d/%d/%d", todoID, dueDate.Unix(), offsetMinutes))
+	return nil
+}
+
+func (m *mockRepository) AddReminder(ctx context.Context, reminder *model.Reminder) error {
+	todo, exists := m.todos[reminder.TodoID]
+	if !exists {
+		return repository.ErrTodoNotFound
+	}
+	reminder.ID = int64(len(m.reminders) + 1)
+	reminder.TodoTitle = todo.Title
+	reminder.CreatedAt = time.Now()
+	m.reminders = append(m.reminders, reminder)
+	return nil
+}
+
+func (m *mockRepository) GetReminder(ctx context.Context, id int64) (*model.Reminder, error) {
+	for _, reminder := range m.reminders {
+		if reminder.ID == id {
+			return reminder, nil
+		}
+	}
+	return nil, repository.ErrReminderNotFound
+}
+
+func (m *mockRepository) GetReminders(ctx context.Context, todoID int64) ([]*model.Reminder, error) {
+	result := make([]*model.Reminder, 0)
+	for _, reminder := range m.reminders {
+		if reminder.TodoID == todoID && reminder.DismissedAt == nil {
+			result = append(result, reminder)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRepository) GetDueReminders(ctx context.Context, now time.Time) ([]*model.Reminder, error) {
+	result := make([]*model.Reminder, 0)
+	for _, reminder := range m.reminders {
+		if todo, exists := m.todos[reminder.TodoID]; exists && todo.IsPending() && reminder.IsDue(now) {
+			result = append(result, reminder)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRepository) ClaimReminder(ctx context.Context, id int64) (bool, error) {
+	reminder, err := m.GetReminder(ctx, id)
+	if err != nil || reminder.SentAt != nil {
+		return false, err
+	}
+	now := time.Now()
+	reminder.SentAt = &now
+	return true, nil
+}
+
+func (m *mockRepository) ReleaseReminder(ctx context.Context, id int64) error {
+	reminder, err := m.GetReminder(ctx, id)
+	if err != nil || reminder.DismissedAt != nil {
+		return repository.ErrReminderNotFound
+	}
+	reminder.SentAt = nil
+	return nil
+}
+
+func (m *mockRepository) RescheduleReminder(ctx context.Context, id int64, remindAt time.Time) error {
+	reminder, err := m.GetReminder(ctx, id)
+	if err != nil || reminder.DismissedAt != nil {
+		return repository.ErrReminderNotFound
+	}
+	reminder.RemindAt = remindAt
+	reminder.SentAt = nil
+	return nil
+}
+
+func (m *mockRepository) DismissReminder(ctx context.Context, id int64) error {
+	reminder, err := m.GetReminder(ctx, id)
+	if err != nil || reminder.DismissedAt != nil {
+		return repository.ErrReminderNotFound
+	}
+	now := time.Now()
+	reminder.DismissedAt = &now
+	return nil
+}
+
+func (m *mockRepository) DeleteReminders(ctx context.Context, todoID int64) (int, error) {
+	kept := make([]*model.Reminder, 0, len(m.reminders))
+	deleted := 0
+	for _, reminder := range m.reminders {
+		if reminder.TodoID == todoID && reminder.DismissedAt == nil {
+			deleted++
+			continue
+		}
+		kept = append(kept, reminder)
+	}
+	m.reminders = kept
+	return deleted, nil
+}
+
+func (m *mockRepository) GetStopwatch(ctx context.Context) (*model.Stopwatch, error) {
+	return m.stopwatch, nil
+}
+
+func (m *mockRepository) SetStopwatch(ctx context.Context, stopwatch *model.Stopwatch) error {
+	m.stopwatch = stopwatch
+	return nil
+}
+
+func (m *mockRepository) ClearStopwatch(ctx context.Context) error {
+	m.stopwatch = nil
+	return nil
+}
+
+func (m *mockRepository) RestoreTodo(ctx context.Context, todo *model.Todo) error {
+	restored := *todo
+	if existing, exists := m.todos[todo.ID]; exists {
+		restored.WorkDuration = existing.WorkDuration
+	}
+	m.todos[todo.ID] = &restored
+	return nil
+}
+
+func (m *mockRepository) AddUndoEntry(ctx context.Context, entry *model.UndoEntry) error {
+	kept := make([]*model.UndoEntry, 0, len(m.undo)+1)
+	for _, e := range m.undo {
+		if e.UndoneAt == nil {
+			kept = append(kept, e)
+		}
+	}
+	entry.ID = int64(len(kept) + 1)
+	m.undo = append(kept, entry)
+	return nil
+}
+
+func (m *mockRepository) AddUndoEntries(ctx context.Context, entries []*model.UndoEntry) error {
+	var groupID *int64
+	for _, entry := range entries {
+		_ = m.AddUndoEntry(ctx, entry)
+		if len(entries) > 1 && groupID == nil {
+			id := entry.ID
+			groupID = &id
+		}
+		entry.GroupID = groupID
+	}
+	return nil
+}
+
+func (m *mockRepository) GetUndoGroup(ctx context.Context, groupID int64) ([]*model.UndoEntry, error) {
+	entries := make([]*model.UndoEntry, 0)
+	for _, entry := range m.undo {
+		if entry.GroupID != nil && *entry.GroupID == groupID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockRepository) GetUndoEntry(ctx context.Context) (*model.UndoEntry, error) {
+	for i := len(m.undo) - 1; i >= 0; i-- {
+		if m.undo[i].UndoneAt == nil {
+			return m.undo[i], nil
+		}
+	}
+	return nil, repository.ErrUndoEntryNotFound
+}
+
+func (m *mockRepository) GetRedoEntry(ctx context.Context) (*model.UndoEntry, error) {
+	for _, entry := range m.undo {
+		if entry.UndoneAt != nil {
+			return entry, nil
+		}
+	}
+	return nil, repository.ErrUndoEntryNotFound
+}
+
+func (m *mockRepository) SetUndoEntryUndone(ctx context.Context, id int64, undone bool) error {
+	for _, entry := range m.undo {
+		if entry.ID == id {
+			entry.UndoneAt = nil
+			if undone {
+				now := time.Now()
+				entry.UndoneAt = &now
+			}
+			return nil
+		}
+	}
+	return repository.ErrUndoEntryNotFound
+}
+
+func (m *mockRepository) ApplyUndo(ctx context.Context, change *model.UndoChange) error {
+	for _, todo := range change.Restore {
+		_ = m.RestoreTodo(ctx, todo)
+	}
+	for _, id := range change.Delete {
+		if err := m.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	for _, log := range change.WorkLogs {
+		if err := m.AddWorkLog(ctx, log); err != nil {
+			return err
+		}
+	}
+	_ = m.AddTodoEvents(ctx, change.Events)
+	for _, id := range change.EntryIDs {
+		if err := m.SetUndoEntryUndone(ctx, id, change.Undone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) AddTodoEvents(ctx context.Context, events []*model.TodoEvent) error {
+	for _, event := range events {
+		event.ID = int64(len(m.events) + 1)
+		event.CreatedAt = time.Now()
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetTodoEvents(ctx context.Context, todoID int64) ([]*model.TodoEvent, error) {
+	events := make([]*model.TodoEvent, 0)
+	for _, event := range m.events {
+		if event.TodoID == todoID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
 func (m *mockRepository) Close() error {
 	return nil
 }
@@ -232,39 +768,386 @@ func TestTodoService_EditTodo_NotFound(t *testing.T) {
 	}
 }
 
-func TestTodoService_DeleteTodo(t *testing.T) {
+func TestTodoService_DeferTodo(t *testing.T) {
 	repo := newMockRepository()
 	svc := NewTodoService(repo)
 	ctx := context.Background()
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.Local)
 
-	// Create a todo
-	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	visible, _ := svc.AddTodo(ctx, "Visible", "", model.PriorityMedium, nil)
+	later, _ := svc.AddTodo(ctx, "Next month", "", model.PriorityMedium, nil)
+	soon, _ := svc.AddTodo(ctx, "Next week", "", model.PriorityMedium, nil)
 
-	// Delete it
-	err := svc.DeleteTodo(ctx, todo.ID)
-	if err != nil {
-		t.Fatalf("failed to delete todo: %v", err)
+	nextMonth := now.AddDate(0, 1, 0)
+	nextWeek := now.AddDate(0, 0, 7)
+	if err := svc.DeferTodo(ctx, later.ID, &nextMonth); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
 	}
-
-	// Verify deletion
-	_, err = repo.GetByID(ctx, todo.ID)
-	if err != repository.ErrTodoNotFound {
-		t.Error("expected todo to be deleted")
+	if err := svc.DeferTodo(ctx, soon.ID, &nextWeek); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
 	}
-}
-
-func TestTodoService_CompleteTodo(t *testing.T) {
-	repo := newMockRepository()
-	svc := NewTodoService(repo)
-	ctx := context.Background()
 
-	// Create a pending todo
-	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
-
-	// Complete it
-	err := svc.CompleteTodo(ctx, todo.ID)
+	todos, hidden, err := svc.ListVisibleTodos(ctx, now)
 	if err != nil {
-		t.Fatalf("failed to complete todo: %v", err)
+		t.Fatalf("failed to list visible todos: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != visible.ID {
+		t.Errorf("expected only todo #%d to be visible, got %d todos", visible.ID, len(todos))
+	}
+	if hidden != 2 {
+		t.Errorf("expected 2 hidden todos, got %d", hidden)
+	}
+
+	page, err := svc.ListVisibleTodosPage(ctx, now, 0, 10)
+	if err != nil {
+		t.Fatalf("failed to list the page of visible todos: %v", err)
+	}
+	if len(page.Todos) != 1 || page.Total != 1 || page.Deferred != 2 {
+		t.Errorf("expected a page of 1 of 1 todo with 2 deferred, got %d of %d with %d deferred", len(page.Todos), page.Total, page.Deferred)
+	}
+
+	deferred, err := svc.ListDeferredTodos(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to list deferred todos: %v", err)
+	}
+	if len(deferred) != 2 || deferred[0].ID != soon.ID || deferred[1].ID != later.ID {
+		t.Errorf("expected deferred todos ordered by start date, got %v", deferred)
+	}
+
+	// Deferred todos show up again once their start date has passed
+	todos, hidden, _ = svc.ListVisibleTodos(ctx, nextWeek)
+	if len(todos) != 2 || hidden != 1 {
+		t.Errorf("expected 2 visible and 1 hidden todo on the start date, got %d and %d", len(todos), hidden)
+	}
+
+	// Clearing the start date makes the todo visible right away
+	if err := svc.DeferTodo(ctx, later.ID, nil); err != nil {
+		t.Fatalf("failed to clear start date: %v", err)
+	}
+	if _, hidden, _ = svc.ListVisibleTodos(ctx, now); hidden != 1 {
+		t.Errorf("expected 1 hidden todo after clearing, got %d", hidden)
+	}
+
+	if err := svc.DeferTodo(ctx, 999, &nextWeek); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestTodoService_Trash(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Old task", "", model.PriorityMedium, nil)
+	other, _ := svc.AddTodo(ctx, "Older task", "", model.PriorityMedium, nil)
+	if err := svc.DeleteTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if err := svc.DeleteTodo(ctx, other.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	trash, err := svc.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("failed to list trash: %v", err)
+	}
+	if len(trash) != 2 {
+		t.Fatalf("expected 2 todos in the trash, got %d", len(trash))
+	}
+
+	if err := svc.RestoreFromTrash(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to restore todo: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, todo.ID); err != nil {
+		t.Errorf("expected restored todo to be visible, got %v", err)
+	}
+	if err := svc.RestoreFromTrash(ctx, todo.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound for a todo not in the trash, got %v", err)
+	}
+
+	// Undoing the restore moves the todo back to the trash
+	if entry, err := svc.Undo(ctx); err != nil || entry.Action != model.UndoActionRestore {
+		t.Fatalf("failed to undo restore: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, todo.ID); err != repository.ErrTodoNotFound {
+		t.Errorf("expected todo back in the trash, got %v", err)
+	}
+
+	// Only todos deleted before the retention period are purged
+	old := time.Now().AddDate(0, 0, -40)
+	repo.todos[other.ID].DeletedAt = &old
+	if purged, _ := svc.PurgeExpiredTrash(ctx, time.Now(), 0); purged != 0 {
+		t.Errorf("expected no purge without retention, got %d", purged)
+	}
+	purged, err := svc.PurgeExpiredTrash(ctx, time.Now(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to purge trash: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 purged todo, got %d", purged)
+	}
+
+	if err := svc.PurgeTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to purge todo: %v", err)
+	}
+	if trash, _ := svc.ListTrash(ctx); len(trash) != 0 {
+		t.Errorf("expected empty trash, got %d todos", len(trash))
+	}
+	if err := svc.PurgeTodo(ctx, todo.ID); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestTodoService_Archive(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+	now := time.Now()
+
+	pending, _ := svc.AddTodo(ctx, "Still open", "", model.PriorityMedium, nil)
+	done, _ := svc.AddTodo(ctx, "Quarterly report", "Q3 numbers", model.PriorityMedium, nil)
+	old, _ := svc.AddTodo(ctx, "Old invoice", "", model.PriorityMedium, nil)
+	for _, id := range []int64{done.ID, old.ID} {
+		if err := svc.CompleteTodo(ctx, id); err != nil {
+			t.Fatalf("failed to complete todo: %v", err)
+		}
+	}
+
+	if err := svc.ArchiveTodo(ctx, pending.ID); err != ErrNotCompleted {
+		t.Errorf("expected ErrNotCompleted, got %v", err)
+	}
+	if err := svc.ArchiveTodo(ctx, done.ID); err != nil {
+		t.Fatalf("failed to archive todo: %v", err)
+	}
+	if err := svc.BulkArchive(ctx, []int64{done.ID, pending.ID}); !errors.Is(err, ErrNotCompleted) {
+		t.Errorf("expected ErrNotCompleted from a bulk archive, got %v", err)
+	}
+	if repo.todos[done.ID].ArchivedAt == nil {
+		t.Error("expected the archived todo to stay archived")
+	}
+
+	// Only todos completed before the threshold are archived automatically
+	longAgo := now.AddDate(0, 0, -40)
+	repo.todos[old.ID].CompletedAt = &longAgo
+	count, err := svc.AutoArchive(ctx, now, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to auto-archive: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 auto-archived todo, got %d", count)
+	}
+
+	todos, _ := svc.ListTodos(ctx)
+	if len(todos) != 1 || todos[0].ID != pending.ID {
+		t.Errorf("expected only the pending todo in the list, got %d todos", len(todos))
+	}
+
+	found, err := svc.SearchArchive(ctx, " Q3 ")
+	if err != nil {
+		t.Fatalf("failed to search archive: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != done.ID {
+		t.Errorf("expected search to find the report, got %d todos", len(found))
+	}
+	if all, _ := svc.SearchArchive(ctx, ""); len(all) != 2 {
+		t.Errorf("expected 2 archived todos, got %d", len(all))
+	}
+
+	if err := svc.UnarchiveTodo(ctx, done.ID); err != nil {
+		t.Fatalf("failed to unarchive todo: %v", err)
+	}
+	if err := svc.UnarchiveTodo(ctx, done.ID); err != ErrNotArchived {
+		t.Errorf("expected ErrNotArchived, got %v", err)
+	}
+
+	// Undoing the unarchive archives the todo again
+	if entry, err := svc.Undo(ctx); err != nil || entry.Action != model.UndoActionUnarchive {
+		t.Fatalf("failed to undo unarchive: %v", err)
+	}
+	if todo, _ := repo.GetByID(ctx, done.ID); !todo.IsArchived() {
+		t.Error("expected todo to be archived again")
+	}
+}
+
+func TestTodoService_Bulk(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, title := range []string{"One", "Two", "Three"} {
+		todo, _ := svc.AddTodo(ctx, title, "", model.PriorityLow, nil)
+		ids = append(ids, todo.ID)
+	}
+	selected := ids[:2]
+
+	if err := svc.BulkComplete(ctx, nil); err != ErrNoSelection {
+		t.Errorf("expected ErrNoSelection, got %v", err)
+	}
+	if err := svc.BulkComplete(ctx, []int64{ids[0], 99}); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+	if todo, _ := repo.GetByID(ctx, ids[0]); todo.IsCompleted() {
+		t.Error("expected no todo to change when one of them does not exist")
+	}
+
+	if err := svc.BulkSetPriority(ctx, selected, model.PriorityHigh); err != nil {
+		t.Fatalf("failed to set priority: %v", err)
+	}
+	due := time.Now().AddDate(0, 0, 7)
+	if err := svc.BulkSetDueDate(ctx, selected, &due); err != nil {
+		t.Fatalf("failed to set due date: %v", err)
+	}
+	if err := svc.BulkComplete(ctx, selected); err != nil {
+		t.Fatalf("failed to complete todos: %v", err)
+	}
+	for _, id := range selected {
+		todo, _ := repo.GetByID(ctx, id)
+		if !todo.IsCompleted() || todo.CompletedAt == nil || todo.Priority != model.PriorityHigh || todo.DueDate == nil {
+			t.Errorf("todo #%d was not updated: %+v", id, todo)
+		}
+	}
+	if todo, _ := repo.GetByID(ctx, ids[2]); todo.IsCompleted() || todo.Priority != model.PriorityLow {
+		t.Error("expected the unselected todo to be unchanged")
+	}
+
+	exportPath := filepath.Join(t.TempDir(), "selected.json")
+	if err := svc.ExportSelectedToJSON(ctx, selected, exportPath); err != nil {
+		t.Fatalf("failed to export todos: %v", err)
+	}
+	var exported []*model.Todo
+	data, _ := os.ReadFile(exportPath)
+	if err := json.Unmarshal(data, &exported); err != nil || len(exported) != 2 {
+		t.Errorf("expected 2 exported todos, got %d (%v)", len(exported), err)
+	}
+
+	if err := svc.BulkDelete(ctx, selected); err != nil {
+		t.Fatalf("failed to delete todos: %v", err)
+	}
+	if todos, _ := svc.ListTodos(ctx); len(todos) != 1 {
+		t.Errorf("expected 1 remaining todo, got %d", len(todos))
+	}
+
+	// A single undo reverts the whole bulk change
+	entry, err := svc.Undo(ctx)
+	if err != nil {
+		t.Fatalf("failed to undo: %v", err)
+	}
+	if entry.GroupSize != 2 || entry.Summary() != "bulk delete of 2 todos" {
+		t.Errorf("unexpected undo summary %q", entry.Summary())
+	}
+	if todos, _ := svc.ListTodos(ctx); len(todos) != 3 {
+		t.Errorf("expected 3 todos after undo, got %d", len(todos))
+	}
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo: %v", err)
+	}
+	for _, id := range selected {
+		if todo, _ := repo.GetByID(ctx, id); todo.IsCompleted() {
+			t.Errorf("expected todo #%d to be pending after undoing the completion", id)
+		}
+	}
+	if _, err := svc.Redo(ctx); err != nil {
+		t.Fatalf("failed to redo: %v", err)
+	}
+	for _, id := range selected {
+		if todo, _ := repo.GetByID(ctx, id); !todo.IsCompleted() {
+			t.Errorf("expected todo #%d to be completed after redo", id)
+		}
+	}
+}
+
+func TestTodoService_History(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := WithSource(context.Background(), model.EventSourceTUI)
+
+	todo, err := svc.AddTodo(ctx, "Draft", "", model.PriorityLow, nil)
+	if err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+	if err := svc.EditTodo(ctx, todo.ID, "Final", "", model.PriorityHigh, nil); err != nil {
+		t.Fatalf("failed to edit todo: %v", err)
+	}
+	// Changes without a source are attributed to the CLI
+	if err := svc.DeleteTodo(context.Background(), todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo delete: %v", err)
+	}
+
+	events, err := svc.ListTodoEvents(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+
+	expected := []struct {
+		field    string
+		old, new string
+		source   model.EventSource
+	}{
+		{model.EventFieldCreated, "", "Draft", model.EventSourceTUI},
+		{model.EventFieldTitle, "Draft", "Final", model.EventSourceTUI},
+		{model.EventFieldPriority, "low", "high", model.EventSourceTUI},
+		{model.EventFieldDeletedAt, "", "*", model.EventSourceCLI},
+		{model.EventFieldDeletedAt, "*", "", model.EventSourceTUI},
+	}
+	// "*" stands for a time, which only has to be set
+	matches := func(want, got string) bool {
+		if want == "*" {
+			return got != ""
+		}
+		return want == got
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Field != want.field || got.Source != want.source {
+			t.Errorf("event %d: expected %s from %s, got %s from %s", i, want.field, want.source, got.Field, got.Source)
+		}
+		if !matches(want.old, got.OldValue) || !matches(want.new, got.NewValue) {
+			t.Errorf("event %d: expected %q → %q, got %q → %q", i, want.old, want.new, got.OldValue, got.NewValue)
+		}
+	}
+}
+
+func TestTodoService_DeleteTodo(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	// Create a todo
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+
+	// Delete it
+	err := svc.DeleteTodo(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	// Verify deletion
+	_, err = repo.GetByID(ctx, todo.ID)
+	if err != repository.ErrTodoNotFound {
+		t.Error("expected todo to be deleted")
+	}
+}
+
+func TestTodoService_CompleteTodo(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	// Create a pending todo
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+
+	// Complete it
+	err := svc.CompleteTodo(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to complete todo: %v", err)
 	}
 
 	// Verify status
@@ -274,6 +1157,61 @@ func TestTodoService_CompleteTodo(t *testing.T) {
 	}
 }
 
+func TestTodoService_QuickEdits(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	due := time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local)
+	todo, _ := svc.AddTodo(ctx, "Original", "Details", model.PriorityLow, &due)
+
+	if err := svc.SetPriority(ctx, todo.ID, model.PriorityHigh); err != nil {
+		t.Fatalf("failed to set priority: %v", err)
+	}
+	if err := svc.RenameTodo(ctx, todo.ID, "  Renamed  "); err != nil {
+		t.Fatalf("failed to rename todo: %v", err)
+	}
+	if err := svc.SetDueDate(ctx, todo.ID, nil); err != nil {
+		t.Fatalf("failed to clear due date: %v", err)
+	}
+
+	// Each change leaves the other fields alone
+	updated, _ := repo.GetByID(ctx, todo.ID)
+	if updated.Title != "Renamed" || updated.Priority != model.PriorityHigh || updated.DueDate != nil || updated.Description != "Details" {
+		t.Errorf("unexpected todo after quick edits: %+v", updated)
+	}
+
+	if err := svc.RenameTodo(ctx, todo.ID, " "); err != ErrInvalidTitle {
+		t.Errorf("expected ErrInvalidTitle for an empty title, got %v", err)
+	}
+	if err := svc.SetPriority(ctx, todo.ID, model.Priority(7)); err != ErrInvalidPriority {
+		t.Errorf("expected ErrInvalidPriority, got %v", err)
+	}
+	if err := svc.SetPriority(ctx, 999, model.PriorityLow); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+
+	// Reopening a completed todo clears its completion time
+	_ = svc.CompleteTodo(ctx, todo.ID)
+	if err := svc.ReopenTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to reopen todo: %v", err)
+	}
+	reopened, _ := repo.GetByID(ctx, todo.ID)
+	if !reopened.IsPending() || reopened.CompletedAt != nil {
+		t.Errorf("expected a pending todo without completion time, got status %d and %v", reopened.Status, reopened.CompletedAt)
+	}
+
+	// Quick edits are undone one at a time
+	entry, err := svc.Undo(ctx)
+	if err != nil || entry.Action != model.UndoActionEdit {
+		t.Fatalf("expected the reopen to be undone, got %v (%v)", entry, err)
+	}
+	undone, _ := repo.GetByID(ctx, todo.ID)
+	if !undone.IsCompleted() {
+		t.Errorf("expected the todo to be completed again after undo, got status %d", undone.Status)
+	}
+}
+
 func TestTodoService_ListTodos(t *testing.T) {
 	repo := newMockRepository()
 	svc := NewTodoService(repo)
@@ -547,3 +1485,751 @@ func TestTodoService_AddWorkDuration_NegativeMinutes(t *testing.T) {
 		t.Errorf("expected ErrInvalidWorkDuration, got %v", err)
 	}
 }
+
+func TestTodoService_AddPartialWorkDuration(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, err := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("failed to add todo: %v", err)
+	}
+
+	// A timer stopped after 3 minutes adds its time but no Pomodoro
+	if err := svc.AddPartialWorkDuration(ctx, todo.ID, 3); err != nil {
+		t.Fatalf("failed to add partial work duration: %v", err)
+	}
+
+	updated, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get updated todo: %v", err)
+	}
+	if updated.WorkDuration != 3 {
+		t.Errorf("expected work duration 3, got %d", updated.WorkDuration)
+	}
+	if len(repo.logs) != 1 || repo.logs[0].Source != model.WorkLogSourcePartialPomodoro {
+		t.Fatalf("expected a single %q work log, got %+v", model.WorkLogSourcePartialPomodoro, repo.logs)
+	}
+
+	stats, err := svc.GetStats(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if today := stats.PomodorosPerDay[len(stats.PomodorosPerDay)-1]; today != 0 {
+		t.Errorf("expected no Pomodoros today, got %d", today)
+	}
+}
+
+func TestTodoService_LogWork(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	loggedAt := time.Now().Add(-2 * time.Hour)
+
+	log, err := svc.LogWork(ctx, todo.ID, 90, "  Whiteboarding  ", loggedAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Note != "Whiteboarding" {
+		t.Errorf("expected trimmed note, got %q", log.Note)
+	}
+	if log.Source != model.WorkLogSourceManual {
+		t.Errorf("expected source %q, got %q", model.WorkLogSourceManual, log.Source)
+	}
+	if !log.LoggedAt.Equal(loggedAt) {
+		t.Errorf("expected logged at %v, got %v", loggedAt, log.LoggedAt)
+	}
+
+	// Negative entries remove time
+	if _, err := svc.LogWork(ctx, todo.ID, -30, "Overcounted", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, _ := repo.GetByID(ctx, todo.ID)
+	if updated.WorkDuration != 60 {
+		t.Errorf("expected work duration 60, got %d", updated.WorkDuration)
+	}
+}
+
+func TestTodoService_LogWork_Invalid(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+
+	tests := []struct {
+		name        string
+		id          int64
+		minutes     int
+		expectedErr error
+	}{
+		{"zero minutes", todo.ID, 0, ErrEmptyWorkLog},
+		{"total below zero", todo.ID, -10, ErrNegativeWorkTotal},
+		{"todo not found", 9999, 25, ErrTodoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.LogWork(ctx, tt.id, tt.minutes, "", time.Now())
+			if err != tt.expectedErr {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTodoService_AdjustWorkLog(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	entry, _ := svc.LogWork(ctx, todo.ID, 60, "Meeting", time.Now())
+
+	if err := svc.AdjustWorkLog(ctx, entry.ID, 45); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The original entry is kept and a correction is appended
+	logs, _ := svc.ListWorkLogs(ctx, todo.ID)
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 work logs, got %d", len(logs))
+	}
+	if entry.Minutes != 60 {
+		t.Errorf("expected original entry to keep 60 minutes, got %d", entry.Minutes)
+	}
+	if effective := model.EffectiveWorkMinutes(logs)[entry.ID]; effective != 45 {
+		t.Errorf("expected effective minutes 45, got %d", effective)
+	}
+
+	updated, _ := repo.GetByID(ctx, todo.ID)
+	if updated.WorkDuration != 45 {
+		t.Errorf("expected work duration 45, got %d", updated.WorkDuration)
+	}
+
+	// Corrections themselves cannot be adjusted
+	if err := svc.AdjustWorkLog(ctx, logs[1].ID, 10); err != ErrInvalidCorrection {
+		t.Errorf("expected ErrInvalidCorrection, got %v", err)
+	}
+
+	if err := svc.AdjustWorkLog(ctx, 9999, 10); err != ErrWorkLogNotFound {
+		t.Errorf("expected ErrWorkLogNotFound, got %v", err)
+	}
+}
+
+func TestTodoService_DeleteWorkLog(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	kept, _ := svc.LogWork(ctx, todo.ID, 25, "", time.Now())
+	deleted, _ := svc.LogWork(ctx, todo.ID, 30, "Wrong task", time.Now())
+
+	if err := svc.DeleteWorkLog(ctx, deleted.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs, _ := svc.ListWorkLogs(ctx, todo.ID)
+	effective := model.EffectiveWorkMinutes(logs)
+	if effective[deleted.ID] != 0 {
+		t.Errorf("expected deleted entry to have 0 effective minutes, got %d", effective[deleted.ID])
+	}
+	if effective[kept.ID] != 25 {
+		t.Errorf("expected kept entry to have 25 effective minutes, got %d", effective[kept.ID])
+	}
+
+	updated, _ := repo.GetByID(ctx, todo.ID)
+	if updated.WorkDuration != 25 {
+		t.Errorf("expected work duration 25, got %d", updated.WorkDuration)
+	}
+
+	// Deleting twice is a no-op
+	if err := svc.DeleteWorkLog(ctx, deleted.ID); err != nil {
+		t.Errorf("unexpected error deleting twice: %v", err)
+	}
+	logs, _ = svc.ListWorkLogs(ctx, todo.ID)
+	if len(logs) != 3 {
+		t.Errorf("expected 3 work logs, got %d", len(logs))
+	}
+}
+
+func TestTodoService_StartStopwatch(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	first, _ := svc.AddTodo(ctx, "First", "", model.PriorityMedium, nil)
+	second, _ := svc.AddTodo(ctx, "Second", "", model.PriorityMedium, nil)
+
+	previous, err := svc.StartStopwatch(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if previous != nil {
+		t.Errorf("expected no previous stopwatch, got %+v", previous)
+	}
+
+	// Pretend the first stopwatch has been running for 40 minutes
+	repo.stopwatch.StartedAt = time.Now().Add(-40 * time.Minute)
+
+	// Starting another todo auto-stops and records the first one
+	previous, err = svc.StartStopwatch(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if previous == nil || previous.TodoID != first.ID || previous.Minutes != 40 {
+		t.Errorf("expected 40 minutes recorded for todo #%d, got %+v", first.ID, previous)
+	}
+
+	updated, _ := repo.GetByID(ctx, first.ID)
+	if updated.WorkDuration != 40 {
+		t.Errorf("expected work duration 40, got %d", updated.WorkDuration)
+	}
+	if repo.logs[0].Source != model.WorkLogSourceStopwatch {
+		t.Errorf("expected source %q, got %q", model.WorkLogSourceStopwatch, repo.logs[0].Source)
+	}
+
+	running, _ := svc.GetStopwatch(ctx)
+	if running == nil || running.TodoID != second.ID {
+		t.Errorf("expected stopwatch running for todo #%d, got %+v", second.ID, running)
+	}
+
+	if _, err := svc.StartStopwatch(ctx, 9999); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestTodoService_StopStopwatch(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.StopStopwatch(ctx); err != ErrNoStopwatch {
+		t.Errorf("expected ErrNoStopwatch, got %v", err)
+	}
+
+	todo, _ := svc.AddTodo(ctx, "Test Todo", "", model.PriorityMedium, nil)
+	if _, err := svc.StartStopwatch(ctx, todo.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Less than a minute records nothing
+	result, err := svc.StopStopwatch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Minutes != 0 {
+		t.Errorf("expected 0 minutes, got %d", result.Minutes)
+	}
+	if len(repo.logs) != 0 {
+		t.Errorf("expected no work logs, got %d", len(repo.logs))
+	}
+
+	running, _ := svc.GetStopwatch(ctx)
+	if running != nil {
+		t.Errorf("expected stopwatch to be cleared, got %+v", running)
+	}
+}
+
+func TestTodoService_GetTimesheet(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Client work", "", model.PriorityMedium, nil)
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	_, _ = svc.LogWork(ctx, todo.ID, 60, "", monday.Add(10*time.Hour))
+	_, _ = svc.LogWork(ctx, todo.ID, 30, "", monday.Add(58*time.Hour))
+
+	// Times of day are truncated so whole days are reported
+	ts, err := svc.GetTimesheet(ctx, monday.Add(15*time.Hour), monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ts.From.Equal(monday) {
+		t.Errorf("expected range to start at %v, got %v", monday, ts.From)
+	}
+	if ts.Total != 90 {
+		t.Errorf("expected total 90 minutes, got %d", ts.Total)
+	}
+	if ts.DayTotals[2] != 30 {
+		t.Errorf("expected 30 minutes on Wednesday, got %d", ts.DayTotals[2])
+	}
+
+	if _, err := svc.GetTimesheet(ctx, monday, monday); err != ErrInvalidDateRange {
+		t.Errorf("expected ErrInvalidDateRange, got %v", err)
+	}
+}
+
+func TestTodoService_ExportTimesheetToCSV(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Client work", "", model.PriorityMedium, nil)
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)
+	_, _ = svc.LogWork(ctx, todo.ID, 45, "", monday.Add(10*time.Hour))
+
+	ts, err := svc.GetTimesheet(ctx, monday, monday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "timesheet.csv")
+	if err := svc.ExportTimesheetToCSV(ts, filePath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatalf("failed to open exported file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse exported CSV: %v", err)
+	}
+
+	expected := [][]string{
+		{"Todo ID", "Title", "2025-01-13", "2025-01-14", "Total (minutes)"},
+		{"1", "Client work", "45", "0", "45"},
+		{"", "Total", "45", "0", "45"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(records))
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if records[i][j] != expected[i][j] {
+				t.Errorf("record %d field %d = %q, want %q", i, j, records[i][j], expected[i][j])
+			}
+		}
+	}
+}
+
+func TestTodoService_GetStats(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	// Wednesday, 2025-01-15 18:00
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, time.Local)
+	today := timeutil.StartOfDay(now)
+
+	completed := func(title string, created, done time.Time) {
+		todo, _ := svc.AddTodo(ctx, title, "", model.PriorityMedium, nil)
+		todo.Status = model.StatusCompleted
+		todo.CreatedAt = created
+		todo.CompletedAt = &done
+	}
+	completed("Yesterday", today.AddDate(0, 0, -2), today.Add(-12*time.Hour))
+	completed("Two days ago", today.AddDate(0, 0, -3), today.Add(-36*time.Hour))
+	_, _ = svc.AddTodo(ctx, "Open", "", model.PriorityHigh, nil)
+
+	work, _ := svc.AddTodo(ctx, "Work", "", model.PriorityLow, nil)
+	repo.logs = append(repo.logs,
+		&model.WorkLog{TodoID: work.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: today.Add(9 * time.Hour)},
+		&model.WorkLog{TodoID: work.ID, Minutes: 60, Source: model.WorkLogSourceManual, LoggedAt: today.AddDate(0, 0, -2)},
+		&model.WorkLog{TodoID: work.ID, Minutes: 40, Source: model.WorkLogSourceManual, LoggedAt: today.AddDate(0, 0, -5)},
+	)
+
+	stats, err := svc.GetStats(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stats.Days) != StatsDays || !stats.Days[StatsDays-1].Equal(today) {
+		t.Fatalf("expected %d days ending today, got %v", StatsDays, stats.Days)
+	}
+	if stats.CompletedPerDay[StatsDays-2] != 1 || stats.CompletedPerDay[StatsDays-3] != 1 {
+		t.Errorf("unexpected completed per day: %v", stats.CompletedPerDay)
+	}
+	if stats.PomodorosPerDay[StatsDays-1] != 1 {
+		t.Errorf("expected 1 pomodoro today, got %v", stats.PomodorosPerDay)
+	}
+	if stats.FocusThisWeek != 85 || stats.FocusLastWeek != 40 {
+		t.Errorf("expected focus 85/40 minutes, got %d/%d", stats.FocusThisWeek, stats.FocusLastWeek)
+	}
+	// (36h + 36h) / 2
+	if stats.CompletedTotal != 2 || stats.AvgCompletion != 36*time.Hour {
+		t.Errorf("expected 36h over 2 todos, got %v over %d", stats.AvgCompletion, stats.CompletedTotal)
+	}
+	if stats.OpenByPriority[model.PriorityHigh] != 1 || stats.OpenByPriority[model.PriorityLow] != 1 {
+		t.Errorf("unexpected open todos by priority: %v", stats.OpenByPriority)
+	}
+	// Nothing completed today yet, so the streak counts from yesterday
+	if stats.CurrentStreak != 2 || stats.StreakIncludesToday {
+		t.Errorf("expected streak of 2 not including today, got %d (%v)", stats.CurrentStreak, stats.StreakIncludesToday)
+	}
+}
+
+func TestTodoService_GetGoalProgress(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, time.Local)
+	today := timeutil.StartOfDay(now)
+	goal := model.DailyGoal{Pomodoros: 2, Minutes: 60}
+
+	todo, _ := svc.AddTodo(ctx, "Focus", "", model.PriorityMedium, nil)
+	pomodoro := func(day time.Time) {
+		repo.logs = append(repo.logs, &model.WorkLog{TodoID: todo.ID, Minutes: 25, Source: model.WorkLogSourcePomodoro, LoggedAt: day.Add(9 * time.Hour)})
+	}
+	manual := func(day time.Time, minutes int) {
+		repo.logs = append(repo.logs, &model.WorkLog{TodoID: todo.ID, Minutes: minutes, Source: model.WorkLogSourceManual, LoggedAt: day.Add(10 * time.Hour)})
+	}
+
+	// Goal met yesterday and the day before, missed three days ago
+	for _, daysAgo := range []int{1, 2} {
+		day := today.AddDate(0, 0, -daysAgo)
+		pomodoro(day)
+		pomodoro(day)
+		manual(day, 10)
+	}
+	pomodoro(today.AddDate(0, 0, -3))
+
+	// Today is in progress
+	pomodoro(today)
+
+	progress, err := svc.GetGoalProgress(ctx, goal, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Pomodoros != 1 || progress.Minutes != 25 {
+		t.Errorf("expected 1 pomodoro / 25 minutes today, got %d / %d", progress.Pomodoros, progress.Minutes)
+	}
+	if progress.Met() {
+		t.Error("expected today's goal not to be met")
+	}
+	if progress.Streak != 2 || progress.StreakIncludesToday {
+		t.Errorf("expected streak of 2 not including today, got %d (%v)", progress.Streak, progress.StreakIncludesToday)
+	}
+
+	// Without a goal nothing is tracked
+	progress, err = svc.GetGoalProgress(ctx, model.DailyGoal{}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Streak != 0 || progress.Pomodoros != 0 {
+		t.Errorf("expected empty progress without a goal, got %+v", progress)
+	}
+}
+
+func TestTodoService_GetGoalProgress_StoppedEarly(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	now := time.Now()
+	today := timeutil.StartOfDay(now)
+	goal := model.DailyGoal{Pomodoros: 2}
+
+	todo, _ := svc.AddTodo(ctx, "Focus", "", model.PriorityMedium, nil)
+	log := func(day time.Time, minutes int, source model.WorkLogSource) {
+		repo.logs = append(repo.logs, &model.WorkLog{TodoID: todo.ID, Minutes: minutes, Source: source, LoggedAt: day.Add(9 * time.Hour)})
+	}
+
+	// Goal met yesterday; the day before, a stopped timer made up the second Pomodoro
+	yesterday := today.AddDate(0, 0, -1)
+	log(yesterday, 25, model.WorkLogSourcePomodoro)
+	log(yesterday, 25, model.WorkLogSourcePomodoro)
+	log(yesterday.AddDate(0, 0, -1), 25, model.WorkLogSourcePomodoro)
+	log(yesterday.AddDate(0, 0, -1), 10, model.WorkLogSourcePartialPomodoro)
+
+	// Today one Pomodoro was finished and another stopped after 3 minutes
+	log(today, 25, model.WorkLogSourcePomodoro)
+	if err := svc.AddPartialWorkDuration(ctx, todo.ID, 3); err != nil {
+		t.Fatalf("failed to add partial work duration: %v", err)
+	}
+
+	progress, err := svc.GetGoalProgress(ctx, goal, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Pomodoros != 1 || progress.Minutes != 28 {
+		t.Errorf("expected 1 pomodoro / 28 minutes today, got %d / %d", progress.Pomodoros, progress.Minutes)
+	}
+	if progress.Met() {
+		t.Error("expected a stopped timer not to complete today's goal")
+	}
+	if progress.Streak != 1 || progress.StreakIncludesToday {
+		t.Errorf("expected streak of 1 not including today, got %d (%v)", progress.Streak, progress.StreakIncludesToday)
+	}
+}
+
+func TestTodoService_ClaimDueReminders(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	due := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	soon, _ := svc.AddTodo(ctx, "Due in 10 minutes", "", model.PriorityMedium, due(10*time.Minute))
+	later, _ := svc.AddTodo(ctx, "Due in 45 minutes", "", model.PriorityMedium, due(45*time.Minute))
+	_, _ = svc.AddTodo(ctx, "Due tomorrow", "", model.PriorityMedium, due(24*time.Hour))
+	overdue, _ := svc.AddTodo(ctx, "Overdue", "", model.PriorityMedium, due(-time.Hour))
+	_, _ = svc.AddTodo(ctx, "Long overdue", "", model.PriorityMedium, due(-72*time.Hour))
+	done, _ := svc.AddTodo(ctx, "Done", "", model.PriorityMedium, due(5*time.Minute))
+	_ = svc.CompleteTodo(ctx, done.ID)
+
+	offsets := []int{60, 15}
+	reminders, err := svc.ClaimDueReminders(ctx, now, offsets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[int64]int)
+	for _, r := range reminders {
+		got[r.Todo.ID] = r.OffsetMinutes
+	}
+	expected := map[int64]int{soon.ID: 15, later.ID: 60, overdue.ID: 0}
+	if len(got) != len(expected) {
+		t.Fatalf("expected reminders %v, got %v", expected, got)
+	}
+	for id, offset := range expected {
+		if got[id] != offset {
+			t.Errorf("todo #%d: expected offset %d, got %d", id, offset, got[id])
+		}
+	}
+
+	// Reminders are only delivered once
+	reminders, err = svc.ClaimDueReminders(ctx, now.Add(time.Minute), offsets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reminders) != 0 {
+		t.Errorf("expected no new reminders, got %d", len(reminders))
+	}
+
+	// The next stage is delivered when it is reached
+	reminders, err = svc.ClaimDueReminders(ctx, now.Add(11*time.Minute), offsets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reminders) != 1 || reminders[0].Todo.ID != soon.ID || !reminders[0].IsOverdue() {
+		t.Errorf("expected an overdue reminder for todo #%d, got %v", soon.ID, reminders)
+	}
+}
+
+func TestTodoService_Reminders(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	todo, _ := svc.AddTodo(ctx, "Call the bank", "", model.PriorityMedium, nil)
+
+	if _, err := svc.AddReminder(ctx, todo.ID, now.Add(-time.Minute), now); err != ErrReminderInPast {
+		t.Errorf("expected ErrReminderInPast, got %v", err)
+	}
+	if _, err := svc.AddReminder(ctx, 999, now.Add(time.Hour), now); err != ErrTodoNotFound {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+
+	first, err := svc.AddReminder(ctx, todo.ID, now.Add(time.Hour), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.AddReminder(ctx, todo.ID, now.Add(6*time.Hour), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reminders, _ := svc.ListReminders(ctx, todo.ID)
+	if len(reminders) != 2 {
+		t.Fatalf("expected 2 reminders, got %d", len(reminders))
+	}
+
+	// Nothing is due yet
+	if claimed, _ := svc.ClaimReminders(ctx, now); len(claimed) != 0 {
+		t.Errorf("expected no due reminders, got %d", len(claimed))
+	}
+
+	// The first reminder is claimed once, but stays active until dismissed
+	later := now.Add(time.Hour)
+	claimed, err := svc.ClaimReminders(ctx, later)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != first.ID {
+		t.Fatalf("expected first reminder to be claimed, got %v (%v)", claimed, err)
+	}
+	if claimed, _ := svc.ClaimReminders(ctx, later); len(claimed) != 0 {
+		t.Errorf("expected reminder to be claimed only once, got %d", len(claimed))
+	}
+	if active, _ := svc.ActiveReminders(ctx, later); len(active) != 1 {
+		t.Errorf("expected 1 active reminder, got %d", len(active))
+	}
+
+	// Snoozing re-arms the reminder
+	if err := svc.SnoozeReminder(ctx, first.ID, later.Add(10*time.Minute)); err != nil {
+		t.Fatalf("failed to snooze: %v", err)
+	}
+	if active, _ := svc.ActiveReminders(ctx, later); len(active) != 0 {
+		t.Errorf("expected no active reminder while snoozed, got %d", len(active))
+	}
+	if claimed, _ := svc.ClaimReminders(ctx, later.Add(10*time.Minute)); len(claimed) != 1 {
+		t.Errorf("expected snoozed reminder to be claimed again, got %d", len(claimed))
+	}
+
+	// Dismissed reminders are gone for good
+	if err := svc.DismissReminder(ctx, first.ID); err != nil {
+		t.Fatalf("failed to dismiss: %v", err)
+	}
+	if err := svc.DismissReminder(ctx, first.ID); err != ErrReminderNotFound {
+		t.Errorf("expected ErrReminderNotFound, got %v", err)
+	}
+	if active, _ := svc.ActiveReminders(ctx, later.Add(time.Hour)); len(active) != 0 {
+		t.Errorf("expected no active reminders after dismissing, got %d", len(active))
+	}
+
+	cleared, err := svc.ClearReminders(ctx, todo.ID)
+	if err != nil || cleared != 1 {
+		t.Errorf("expected 1 cleared reminder, got %d (%v)", cleared, err)
+	}
+}
+
+func TestTodoService_UndoRedo(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.Undo(ctx); err != ErrNothingToUndo {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	todo, _ := svc.AddTodo(ctx, "Original", "", model.PriorityLow, nil)
+	if err := svc.EditTodo(ctx, todo.ID, "Renamed", "", model.PriorityHigh, nil); err != nil {
+		t.Fatalf("failed to edit todo: %v", err)
+	}
+	if _, err := svc.LogWork(ctx, todo.ID, 30, "Meeting", time.Now()); err != nil {
+		t.Fatalf("failed to log work: %v", err)
+	}
+	if err := svc.CompleteTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to complete todo: %v", err)
+	}
+	if err := svc.DeleteTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+
+	// Undo the delete: the todo comes back with its ID
+	entry, err := svc.Undo(ctx)
+	if err != nil {
+		t.Fatalf("failed to undo delete: %v", err)
+	}
+	if entry.Action != model.UndoActionDelete {
+		t.Errorf("expected delete to be undone first, got %s", entry.Action)
+	}
+	restored, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("todo not restored: %v", err)
+	}
+	if !restored.IsCompleted() || restored.WorkDuration != 30 {
+		t.Errorf("expected completed todo with 30m, got status %d and %dm", restored.Status, restored.WorkDuration)
+	}
+
+	// Undo the completion
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo completion: %v", err)
+	}
+	if restored, _ := repo.GetByID(ctx, todo.ID); !restored.IsPending() || restored.CompletedAt != nil {
+		t.Error("expected todo to be pending again")
+	}
+
+	// Undo the work log: a correction cancels the entry
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo work log: %v", err)
+	}
+	if restored, _ := repo.GetByID(ctx, todo.ID); restored.WorkDuration != 0 {
+		t.Errorf("expected work time to be removed, got %dm", restored.WorkDuration)
+	}
+	if len(repo.logs) != 2 || repo.logs[1].CorrectsID == nil {
+		t.Errorf("expected the undo to append a correction, got %d entries", len(repo.logs))
+	}
+
+	// Undo the edit
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo edit: %v", err)
+	}
+	if restored, _ := repo.GetByID(ctx, todo.ID); restored.Title != "Original" || restored.Priority != model.PriorityLow {
+		t.Errorf("expected original title and priority, got %q and %d", restored.Title, restored.Priority)
+	}
+
+	// Redo the edit and the work log
+	if entry, err := svc.Redo(ctx); err != nil || entry.Action != model.UndoActionEdit {
+		t.Fatalf("failed to redo edit: %v", err)
+	}
+	if _, err := svc.Redo(ctx); err != nil {
+		t.Fatalf("failed to redo work log: %v", err)
+	}
+	if restored, _ := repo.GetByID(ctx, todo.ID); restored.Title != "Renamed" || restored.WorkDuration != 30 {
+		t.Errorf("expected redone title and work time, got %q and %dm", restored.Title, restored.WorkDuration)
+	}
+
+	// A new change discards the remaining redo history
+	if err := svc.DeferTodo(ctx, todo.ID, nil); err != nil {
+		t.Fatalf("failed to defer todo: %v", err)
+	}
+	if _, err := svc.Redo(ctx); err != ErrNothingToRedo {
+		t.Errorf("expected ErrNothingToRedo, got %v", err)
+	}
+
+	// Undoing everything removes the created todo
+	for {
+		if _, err := svc.Undo(ctx); err == ErrNothingToUndo {
+			break
+		} else if err != nil {
+			t.Fatalf("failed to undo: %v", err)
+		}
+	}
+	if _, err := repo.GetByID(ctx, todo.ID); err != repository.ErrTodoNotFound {
+		t.Errorf("expected created todo to be removed, got %v", err)
+	}
+}
+
+func TestTodoService_UndoKeepsLaterChanges(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, _ := svc.AddTodo(ctx, "Original", "", model.PriorityLow, nil)
+	if err := svc.CompleteTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to complete todo: %v", err)
+	}
+	if err := svc.EditTodo(ctx, todo.ID, "Renamed", "", model.PriorityLow, nil); err != nil {
+		t.Fatalf("failed to edit todo: %v", err)
+	}
+
+	// Auto-archive archives the todo without an undo entry
+	if _, err := repo.ArchiveCompletedBefore(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("failed to archive todo: %v", err)
+	}
+
+	// Undoing the edit restores the title but leaves the todo archived
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("failed to undo edit: %v", err)
+	}
+	restored, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("failed to get todo: %v", err)
+	}
+	if restored.Title != "Original" || !restored.IsArchived() {
+		t.Errorf("expected the archived todo titled Original, got %q (archived %v)", restored.Title, restored.IsArchived())
+	}
+
+	// A todo moved to the trash since stays there
+	if err := repo.Delete(ctx, todo.ID); err != nil {
+		t.Fatalf("failed to delete todo: %v", err)
+	}
+	if _, err := svc.Redo(ctx); err != nil {
+		t.Fatalf("failed to redo edit: %v", err)
+	}
+	trash, _ := repo.GetDeleted(ctx)
+	if len(trash) != 1 || trash[0].Title != "Renamed" {
+		t.Errorf("expected the renamed todo to stay in the trash, got %+v", trash)
+	}
+}
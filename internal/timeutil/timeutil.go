@@ -0,0 +1,187 @@
+package timeutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidDuration is returned when a duration string cannot be parsed
+	ErrInvalidDuration = errors.New("invalid duration (examples: 25m, 1h, 1h30m, 90)")
+	// ErrInvalidDate is returned when a date string cannot be parsed
+	ErrInvalidDate = errors.New("invalid date (examples: today, tomorrow, fri, 2025-01-31, 15:00, +3d)")
+)
+
+// weekdays maps lowercase weekday names and abbreviations to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseMinutes parses a human-friendly duration into whole minutes
+// Examples:
+//   - "90" or "90m": 90
+//   - "1h": 60
+//   - "1h30m": 90
+//   - "1.5h": 90
+//   - "-15m": -15 (used for corrections)
+func ParseMinutes(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, ErrInvalidDuration
+	}
+
+	// Bare numbers are interpreted as minutes
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, ErrInvalidDuration
+	}
+
+	return int(math.Round(d.Minutes())), nil
+}
+
+// StartOfDay returns midnight of the day containing t in t's location
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the Monday of the week containing t
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+// ParseDate parses a human-friendly date (and optional time of day) relative to now
+// Supported forms:
+//   - Keywords: "now", "today", "tomorrow", "yesterday", "next week", "next month"
+//   - Weekdays: "mon" ... "sun" (the next occurrence, today included)
+//   - Absolute dates: "2025-01-31"
+//   - Relative offsets: "+3d", "2w", "in 30m", "+2h"
+//   - Times of day: "15:00" (today, or tomorrow if already past), "tomorrow 9:00"
+//
+// Date-only forms resolve to midnight in now's location.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "in ")
+	if s == "" {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	// Relative offsets (e.g. +30m, 2h, 3d, 1w) are measured from now
+	if t, ok := parseOffset(s, now); ok {
+		return t, nil
+	}
+
+	// Split an optional trailing time of day ("tomorrow 9:00")
+	datePart, clock := s, ""
+	if i := strings.LastIndex(s, " "); i >= 0 && strings.Contains(s[i+1:], ":") {
+		datePart, clock = strings.TrimSpace(s[:i]), s[i+1:]
+	} else if strings.Contains(s, ":") && !strings.Contains(s, "-") {
+		datePart, clock = "", s
+	}
+
+	var day time.Time
+	switch {
+	case datePart == "":
+		day = StartOfDay(now)
+	case datePart == "now":
+		return now, nil
+	case datePart == "today":
+		day = StartOfDay(now)
+	case datePart == "tomorrow":
+		day = StartOfDay(now).AddDate(0, 0, 1)
+	case datePart == "yesterday":
+		day = StartOfDay(now).AddDate(0, 0, -1)
+	case datePart == "next week":
+		day = StartOfWeek(now).AddDate(0, 0, 7)
+	case datePart == "next month":
+		y, m, _ := now.Date()
+		day = time.Date(y, m+1, 1, 0, 0, 0, 0, now.Location())
+	default:
+		if wd, ok := weekdays[datePart]; ok {
+			offset := (int(wd) - int(now.Weekday()) + 7) % 7
+			day = StartOfDay(now).AddDate(0, 0, offset)
+			break
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", datePart, now.Location())
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		day = parsed
+	}
+
+	if clock == "" {
+		return day, nil
+	}
+
+	hour, minute, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	result := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+
+	// A bare time of day that has already passed means the next occurrence
+	if datePart == "" && !result.After(now) {
+		result = result.AddDate(0, 0, 1)
+	}
+
+	return result, nil
+}
+
+// parseOffset parses relative offsets such as "+3d", "2w", "30m" or "+1h30m"
+func parseOffset(s string, now time.Time) (time.Time, bool) {
+	s = strings.TrimPrefix(s, "+")
+	if len(s) < 2 {
+		return time.Time{}, false
+	}
+
+	unit := s[len(s)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		if unit == 'w' {
+			n *= 7
+		}
+		return StartOfDay(now).AddDate(0, 0, n), true
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, false
+	}
+	return now.Add(d), true
+}
+
+// parseClock parses "15:04" style times of day
+func parseClock(s string) (int, int, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidDate
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("%w: hour out of range", ErrInvalidDate)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("%w: minute out of range", ErrInvalidDate)
+	}
+	return hour, minute, nil
+}
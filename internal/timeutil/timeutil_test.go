@@ -0,0 +1,119 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"25", 25, false},
+		{"25m", 25, false},
+		{"1h", 60, false},
+		{"1h30m", 90, false},
+		{"1.5h", 90, false},
+		{"-15m", -15, false},
+		{"-20", -20, false},
+		{" 45m ", 45, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"1x", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseMinutes(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseMinutes(%q) expected error, got %d", tt.input, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMinutes(%q) unexpected error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseMinutes(%q) = %d; expected %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	// Wednesday, 2025-01-15 10:30 local time
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+	day := func(y int, m time.Month, d, hh, mm int) time.Time {
+		return time.Date(y, m, d, hh, mm, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"now", now, false},
+		{"today", day(2025, 1, 15, 0, 0), false},
+		{"tomorrow", day(2025, 1, 16, 0, 0), false},
+		{"yesterday", day(2025, 1, 14, 0, 0), false},
+		{"wed", day(2025, 1, 15, 0, 0), false},
+		{"fri", day(2025, 1, 17, 0, 0), false},
+		{"Monday", day(2025, 1, 20, 0, 0), false},
+		{"next week", day(2025, 1, 20, 0, 0), false},
+		{"next month", day(2025, 2, 1, 0, 0), false},
+		{"2025-03-01", day(2025, 3, 1, 0, 0), false},
+		{"+3d", day(2025, 1, 18, 0, 0), false},
+		{"2w", day(2025, 1, 29, 0, 0), false},
+		{"in 30m", now.Add(30 * time.Minute), false},
+		{"+2h", now.Add(2 * time.Hour), false},
+		{"15:00", day(2025, 1, 15, 15, 0), false},
+		{"9:00", day(2025, 1, 16, 9, 0), false},
+		{"tomorrow 9:00", day(2025, 1, 16, 9, 0), false},
+		{"2025-03-01 18:45", day(2025, 3, 1, 18, 45), false},
+		{"", time.Time{}, true},
+		{"someday", time.Time{}, true},
+		{"25:00", time.Time{}, true},
+		{"2025-13-01", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseDate(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDate(%q) expected error, got %v", tt.input, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDate(%q) unexpected error: %v", tt.input, err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseDate(%q) = %v; expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Time
+		expected time.Time
+	}{
+		{"Wednesday", time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local), time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)},
+		{"Monday", time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local), time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)},
+		{"Sunday", time.Date(2025, 1, 19, 23, 59, 0, 0, time.Local), time.Date(2025, 1, 13, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := StartOfWeek(tt.input); !result.Equal(tt.expected) {
+				t.Errorf("StartOfWeek(%v) = %v; expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
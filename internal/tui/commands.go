@@ -4,14 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/gen2brain/beeep"
 	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/notify"
 	"github.com/syeeel/koto-cli-go/internal/service"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
 )
 
 // Message types for Bubbletea
@@ -22,92 +24,475 @@ type commandExecutedMsg struct {
 	err     error
 }
 
-// todosLoadedMsg is sent when todos have been loaded
+// todosLoadedMsg is sent when a page of todos has been loaded
 type todosLoadedMsg struct {
+	todos    []*model.Todo
+	offset   int // Position of the first todo in the list (0 replaces the loaded todos)
+	total    int // Number of todos in the list
+	deferred int // Number of deferred todos hidden from todos
+	err      error
+}
+
+// workLogsLoadedMsg is sent when the work log of a todo has been loaded
+type workLogsLoadedMsg struct {
+	logs []*model.WorkLog
+	err  error
+}
+
+// statsLoadedMsg is sent when the productivity statistics have been loaded
+type statsLoadedMsg struct {
+	stats *model.Stats
+	err   error
+}
+
+// goalProgressLoadedMsg is sent when today's progress towards the daily goal has been loaded
+type goalProgressLoadedMsg struct {
+	progress *model.GoalProgress
+	err      error
+}
+
+// remindersLoadedMsg is sent when the active (due, not dismissed) reminders have been loaded
+type remindersLoadedMsg struct {
+	reminders []*model.Reminder
+	err       error
+}
+
+// bulkExecutedMsg is sent when a bulk operation on the selected todos has finished
+type bulkExecutedMsg struct {
+	message string
+	err     error
+}
+
+// detailEventsLoadedMsg is sent when the history of the todo in the detail view has been loaded
+type detailEventsLoadedMsg struct {
+	todoID int64
+	events []*model.TodoEvent
+	err    error
+}
+
+// detailRemindersLoadedMsg is sent when the reminders of the todo in the detail view have been loaded
+type detailRemindersLoadedMsg struct {
+	todoID    int64
+	reminders []*model.Reminder
+	err       error
+}
+
+// trashLoadedMsg is sent when the deleted todos have been loaded
+type trashLoadedMsg struct {
+	todos []*model.Todo
+	err   error
+}
+
+// trashChangedMsg is sent when a todo was restored from or purged in the trash
+type trashChangedMsg struct {
+	message string
+	err     error
+}
+
+// archiveLoadedMsg is sent when the archived todos matching a search have been loaded
+type archiveLoadedMsg struct {
+	query string
 	todos []*model.Todo
 	err   error
 }
 
+// archiveChangedMsg is sent when a todo was brought back from the archive
+type archiveChangedMsg struct {
+	message string
+	err     error
+}
+
+// clockTickMsg is sent every minute to refresh time-dependent state
+type clockTickMsg time.Time
+
+// timesheetLoadedMsg is sent when a timesheet has been loaded
+type timesheetLoadedMsg struct {
+	timesheet *model.Timesheet
+	message   string // Result of an optional CSV export
+	err       error
+}
+
+// stopwatchLoadedMsg is sent when the running stopwatch has been loaded
+type stopwatchLoadedMsg struct {
+	stopwatch *model.Stopwatch
+	title     string // Title of the todo the stopwatch runs for
+	err       error
+}
+
+// stopwatchTickMsg is sent every second while a stopwatch is running
+type stopwatchTickMsg struct{}
+
 // pomodoroTickMsg is sent every second when the timer is running
 type pomodoroTickMsg struct{}
 
 // pomodoroAlertTickMsg is sent periodically when timer is completed and alarm is active
 type pomodoroAlertTickMsg struct{}
 
+// pomodoroSnoozeEndMsg is sent when a snoozed completion alert should sound again
+type pomodoroSnoozeEndMsg struct{}
+
+// helpRequestedMsg is sent by /help to open the help view
+type helpRequestedMsg struct{}
+
 // pomodoroCompleteMsg is sent when the timer reaches zero
 type pomodoroCompleteMsg struct {
 	todoID int64 // ID of todo that was worked on (0 if general timer)
 }
 
-// parseAndExecuteCommand parses and executes a command
-func parseAndExecuteCommand(svc *service.TodoService, input string) tea.Cmd {
+// tuiContext returns the context for service calls, which records changes as made in the TUI
+func tuiContext() context.Context {
+	return service.WithSource(context.Background(), model.EventSourceTUI)
+}
+
+// loadTodos loads a page of the todos that are not deferred from the service
+// Todos after offset are appended to the loaded ones; offset 0 loads the list anew.
+func loadTodos(svc *service.TodoService, offset, limit int) tea.Cmd {
 	return func() tea.Msg {
-		input = strings.TrimSpace(input)
+		page, err := svc.ListVisibleTodosPage(tuiContext(), time.Now(), offset, limit)
+		if err != nil {
+			return todosLoadedMsg{offset: offset, err: err}
+		}
+		return todosLoadedMsg{todos: page.Todos, offset: offset, total: page.Total, deferred: page.Deferred}
+	}
+}
 
-		// Check if input starts with /
-		if !strings.HasPrefix(input, "/") {
-			return commandExecutedMsg{
-				err: errors.New("commands must start with /"),
+// maxIDListSize limits how many todos an ID list can refer to
+const maxIDListSize = 1000
+
+// parseIDList parses a list of todo IDs such as "3,5,9-12"
+// "." refers to the focused todo (0 if none). Duplicates are dropped and the
+// order of first appearance is kept.
+func parseIDList(arg string, focused int64) ([]int64, error) {
+	invalid := func(part string) error {
+		return fmt.Errorf("invalid todo ID %q (use e.g. 3,5,9-12 or . for the focused todo)", part)
+	}
+
+	var ids []int64
+	seen := make(map[int64]bool)
+	add := func(id int64) error {
+		if !seen[id] {
+			if len(ids) == maxIDListSize {
+				return fmt.Errorf("too many todo IDs (at most %d)", maxIDListSize)
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(arg, ",") {
+		part = strings.TrimSpace(part)
+		if part == "." {
+			if focused == 0 {
+				return nil, errors.New("no focused todo for \".\"")
+			}
+			if err := add(focused); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
-		// Parse command and arguments
-		parts := strings.Fields(input)
-		if len(parts) == 0 {
-			return commandExecutedMsg{
-				err: errors.New("no command provided"),
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || from <= 0 {
+			return nil, invalid(part)
+		}
+		to := from
+		if isRange {
+			to, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || to < from {
+				return nil, invalid(part)
 			}
 		}
 
-		command := parts[0]
-		args := parts[1:]
+		for id := from; id <= to; id++ {
+			if err := add(id); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-		ctx := context.Background()
+	return ids, nil
+}
 
-		// Execute command
-		// Note: /export and /import are now handled via dedicated views in handleEnter()
-		switch command {
-		case "/done":
-			return handleDoneCommand(ctx, svc, args)
-		case "/list":
-			return handleListCommand(ctx, svc, args)
-		case "/help":
-			return commandExecutedMsg{message: "Press '?' to view help"}
-		case "/exit":
-			return tea.Quit()
-		default:
-			return commandExecutedMsg{
-				err: fmt.Errorf("unknown command: %s", command),
+// parseSingleID parses the ID of a command that works on one todo ("3" or ".")
+func parseSingleID(arg string, focused int64) (int64, error) {
+	ids, err := parseIDList(arg, focused)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) != 1 {
+		return 0, errors.New("this command takes a single todo ID")
+	}
+	return ids[0], nil
+}
+
+// applyToIDs runs fn for every todo ID and summarizes the outcome
+// describe builds the message from the todos that succeeded ("todo #3" or
+// "todos #3, #5"); failures are reported per ID in the error.
+func applyToIDs(ids []int64, fn func(id int64) error, describe func(subject string) string) commandExecutedMsg {
+	if len(ids) == 1 {
+		if err := fn(ids[0]); err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: describe(fmt.Sprintf("todo #%d", ids[0]))}
+	}
+
+	var succeeded, failed []string
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			failed = append(failed, fmt.Sprintf("#%d: %v", id, err))
+			continue
+		}
+		succeeded = append(succeeded, fmt.Sprintf("#%d", id))
+	}
+
+	var msg commandExecutedMsg
+	switch len(succeeded) {
+	case 0:
+	case 1:
+		msg.message = describe("todo " + succeeded[0])
+	default:
+		msg.message = describe("todos " + strings.Join(succeeded, ", "))
+	}
+	if len(failed) > 0 {
+		msg.err = fmt.Errorf("%d of %d failed: %s", len(failed), len(ids), strings.Join(failed, "; "))
+	}
+	return msg
+}
+
+// applyToIDsTogether checks every todo ID and applies fn once to the IDs that passed,
+// so a grouped service operation records them as a single undo step
+// The outcome is summarized like applyToIDs, with failed checks reported per ID.
+func applyToIDsTogether(ids []int64, check func(id int64) error, fn func(ids []int64) error, describe func(subject string) string) commandExecutedMsg {
+	var valid []int64
+	msg := applyToIDs(ids, func(id int64) error {
+		if err := check(id); err != nil {
+			return err
+		}
+		valid = append(valid, id)
+		return nil
+	}, describe)
+
+	if len(valid) > 0 {
+		if err := fn(valid); err != nil {
+			return commandExecutedMsg{err: err}
+		}
+	}
+	return msg
+}
+
+// quickAdd is a todo described on one line, e.g. "/add Fix login bug !high @due:fri #auth -- details"
+type quickAdd struct {
+	title       string
+	description string
+	priority    model.Priority
+	dueDate     *time.Time
+	tags        []string
+}
+
+// parseQuickAdd parses the arguments of a one-line /add
+// "!<priority>" sets the priority (medium by default), "@due:<date>" the due date
+// and "#<tag>" adds a tag; everything after "--" is the description and the
+// remaining words form the title.
+func parseQuickAdd(args []string, now time.Time) (quickAdd, error) {
+	todo := quickAdd{priority: model.PriorityMedium}
+	var title []string
+
+	for i, arg := range args {
+		if arg == "--" {
+			todo.description = strings.Join(args[i+1:], " ")
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(arg, "!") && len(arg) > 1:
+			priority, err := parsePriority(arg[1:])
+			if err != nil {
+				return quickAdd{}, err
+			}
+			todo.priority = priority
+		case strings.HasPrefix(arg, "@due:"):
+			dueDate, err := timeutil.ParseDate(strings.TrimPrefix(arg, "@due:"), now)
+			if err != nil {
+				return quickAdd{}, err
 			}
+			todo.dueDate = &dueDate
+		case strings.HasPrefix(arg, "#") && len(arg) > 1:
+			todo.tags = append(todo.tags, arg[1:])
+		default:
+			title = append(title, arg)
 		}
 	}
+
+	todo.title = strings.Join(title, " ")
+	if todo.title == "" {
+		return quickAdd{}, errors.New("usage: /add <title> [!priority] [@due:date] [#tag] [-- description]")
+	}
+	return todo, nil
 }
 
-// loadTodos loads all todos from the service
-func loadTodos(svc *service.TodoService) tea.Cmd {
-	return func() tea.Msg {
-		todos, err := svc.ListTodos(context.Background())
-		return todosLoadedMsg{todos: todos, err: err}
+// handleQuickAddCommand handles /add with arguments (adds the todo described on the line)
+func handleQuickAddCommand(ctx context.Context, svc *service.TodoService, args []string, _ int64) commandExecutedMsg {
+	todo, err := parseQuickAdd(args, time.Now())
+	if err != nil {
+		return commandExecutedMsg{err: err}
 	}
+
+	added, err := svc.AddTodo(ctx, todo.title, todo.description, todo.priority, todo.dueDate, todo.tags...)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return commandExecutedMsg{message: fmt.Sprintf("Added todo #%d: %s", added.ID, added.Title)}
+}
+
+// handleDoneCommand handles the /done command (deletes the todos)
+func handleDoneCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
+	if len(args) != 1 {
+		return commandExecutedMsg{err: errors.New("usage: /done <ids>")}
+	}
+
+	ids, err := parseIDList(args[0], focused)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return applyToIDsTogether(ids, func(id int64) error {
+		_, err := svc.GetTodo(ctx, id)
+		return err
+	}, func(ids []int64) error {
+		return svc.BulkDelete(ctx, ids)
+	}, func(subject string) string {
+		return fmt.Sprintf("Deleted %s (press u to undo)", subject)
+	})
+}
+
+// handleLogCommand handles the /log command (records work time manually)
+func handleLogCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
+	// Extract the optional --at flag, everything else is positional
+	loggedAt := time.Now()
+	var positional []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--at=") {
+			at, err := timeutil.ParseDate(strings.TrimPrefix(arg, "--at="), time.Now())
+			if err != nil {
+				return commandExecutedMsg{err: err}
+			}
+			loggedAt = at
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	if len(positional) < 2 {
+		return commandExecutedMsg{err: errors.New("usage: /log <ids> <duration> [note] [--at=date]")}
+	}
+
+	ids, err := parseIDList(positional[0], focused)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	minutes, err := timeutil.ParseMinutes(positional[1])
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	note := strings.Join(positional[2:], " ")
+	return applyToIDs(ids, func(id int64) error {
+		_, err := svc.LogWork(ctx, id, minutes, note, loggedAt)
+		return err
+	}, func(subject string) string {
+		return fmt.Sprintf("Logged %s for %s on %s", formatSignedMinutes(minutes), subject, loggedAt.Format("2006-01-02"))
+	})
 }
 
-// handleDoneCommand handles the /done command (deletes the todo)
-func handleDoneCommand(ctx context.Context, svc *service.TodoService, args []string) commandExecutedMsg {
+// handleStartCommand handles the /start command (starts the stopwatch for a todo)
+func handleStartCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
 	if len(args) != 1 {
-		return commandExecutedMsg{err: errors.New("usage: /done <id>")}
+		return commandExecutedMsg{err: errors.New("usage: /start <id>")}
 	}
 
-	id, err := strconv.ParseInt(args[0], 10, 64)
+	id, err := parseSingleID(args[0], focused)
 	if err != nil {
-		return commandExecutedMsg{err: errors.New("invalid todo ID")}
+		return commandExecutedMsg{err: err}
 	}
 
-	err = svc.DeleteTodo(ctx, id)
+	previous, err := svc.StartStopwatch(ctx, id)
 	if err != nil {
 		return commandExecutedMsg{err: err}
 	}
 
-	return commandExecutedMsg{message: fmt.Sprintf("Deleted todo #%d", id)}
+	message := fmt.Sprintf("Stopwatch started for todo #%d", id)
+	if previous != nil {
+		message += fmt.Sprintf(" (stopped todo #%d, %s)", previous.TodoID, formatRecordedMinutes(previous.Minutes))
+	}
+	return commandExecutedMsg{message: message}
+}
+
+// handleStopCommand handles the /stop command (stops the stopwatch and records the time)
+func handleStopCommand(ctx context.Context, svc *service.TodoService, args []string) commandExecutedMsg {
+	if len(args) != 0 {
+		return commandExecutedMsg{err: errors.New("usage: /stop")}
+	}
+	return stopStopwatch(ctx, svc)
+}
+
+// stopStopwatch stops the running stopwatch and reports the recorded time
+func stopStopwatch(ctx context.Context, svc *service.TodoService) commandExecutedMsg {
+	result, err := svc.StopStopwatch(ctx)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return commandExecutedMsg{
+		message: fmt.Sprintf("Stopwatch stopped for todo #%d (%s)", result.TodoID, formatRecordedMinutes(result.Minutes)),
+	}
+}
+
+// handleRemindCommand handles the /remind command (sets or clears reminders of todos)
+func handleRemindCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
+	if len(args) < 2 {
+		return commandExecutedMsg{err: errors.New("usage: /remind <ids> <when> | /remind <ids> clear")}
+	}
+
+	ids, err := parseIDList(args[0], focused)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	if len(args) == 2 && strings.EqualFold(args[1], "clear") {
+		cleared := 0
+		return applyToIDs(ids, func(id int64) error {
+			count, err := svc.ClearReminders(ctx, id)
+			cleared += count
+			return err
+		}, func(subject string) string {
+			return fmt.Sprintf("Cleared %d reminder(s) of %s", cleared, subject)
+		})
+	}
+
+	now := time.Now()
+	remindAt, err := timeutil.ParseDate(strings.Join(args[1:], " "), now)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return applyToIDs(ids, func(id int64) error {
+		_, err := svc.AddReminder(ctx, id, remindAt, now)
+		return err
+	}, func(subject string) string {
+		return fmt.Sprintf("Reminder set for %s at %s", subject, remindAt.Format("Mon 2006-01-02 15:04"))
+	})
+}
+
+// formatRecordedMinutes describes the minutes recorded by a stopwatch
+func formatRecordedMinutes(minutes int) string {
+	if minutes <= 0 {
+		return "less than a minute, nothing recorded"
+	}
+	return model.FormatMinutes(minutes) + " recorded"
 }
 
 // handleListCommand handles the /list command
@@ -115,6 +500,9 @@ func handleListCommand(ctx context.Context, svc *service.TodoService, args []str
 	// Parse status filter
 	var status *model.TodoStatus
 	for _, arg := range args {
+		if arg == "--deferred" {
+			return listDeferredTodos(ctx, svc)
+		}
 		if strings.HasPrefix(arg, "--status=") {
 			statusStr := strings.TrimPrefix(arg, "--status=")
 			switch strings.ToLower(statusStr) {
@@ -151,6 +539,542 @@ func handleListCommand(ctx context.Context, svc *service.TodoService, args []str
 	return commandExecutedMsg{message: fmt.Sprintf("Showing %d todos", len(todos))}
 }
 
+// listDeferredTodos reports the deferred todos and their start dates
+func listDeferredTodos(ctx context.Context, svc *service.TodoService) commandExecutedMsg {
+	todos, err := svc.ListDeferredTodos(ctx, time.Now())
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+	if len(todos) == 0 {
+		return commandExecutedMsg{message: "No deferred todos"}
+	}
+
+	items := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		items = append(items, fmt.Sprintf("#%d %s (from %s)", todo.ID, todo.Title, todo.StartDate.Format("2006-01-02")))
+	}
+	return commandExecutedMsg{message: "Deferred: " + strings.Join(items, ", ")}
+}
+
+// handleDeferCommand handles the /defer command (hides todos until a start date)
+func handleDeferCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
+	if len(args) < 2 {
+		return commandExecutedMsg{err: errors.New("usage: /defer <ids> <when> | /defer <ids> clear")}
+	}
+
+	ids, err := parseIDList(args[0], focused)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	if len(args) == 2 && strings.EqualFold(args[1], "clear") {
+		return applyToIDs(ids, func(id int64) error {
+			return svc.DeferTodo(ctx, id, nil)
+		}, func(subject string) string {
+			return fmt.Sprintf("Stopped deferring %s", subject)
+		})
+	}
+
+	startDate, err := timeutil.ParseDate(strings.Join(args[1:], " "), time.Now())
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return applyToIDs(ids, func(id int64) error {
+		return svc.DeferTodo(ctx, id, &startDate)
+	}, func(subject string) string {
+		return fmt.Sprintf("Deferred %s until %s", subject, startDate.Format("Mon 2006-01-02 15:04"))
+	})
+}
+
+// loadTrash loads the deleted todos
+func loadTrash(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		todos, err := svc.ListTrash(tuiContext())
+		return trashLoadedMsg{todos: todos, err: err}
+	}
+}
+
+// restoreFromTrashCmd restores a deleted todo
+func restoreFromTrashCmd(svc *service.TodoService, id int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.RestoreFromTrash(tuiContext(), id); err != nil {
+			return trashChangedMsg{err: err}
+		}
+		return trashChangedMsg{message: fmt.Sprintf("Restored todo #%d", id)}
+	}
+}
+
+// purgeTodoCmd permanently deletes a todo in the trash
+func purgeTodoCmd(svc *service.TodoService, id int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.PurgeTodo(tuiContext(), id); err != nil {
+			return trashChangedMsg{err: err}
+		}
+		return trashChangedMsg{message: fmt.Sprintf("Permanently deleted todo #%d", id)}
+	}
+}
+
+// handleArchiveCommand handles the /archive command (archives completed todos)
+// Without arguments /archive opens the archive view, see handleEnter.
+func handleArchiveCommand(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg {
+	if len(args) != 1 {
+		return commandExecutedMsg{err: errors.New("usage: /archive <ids>")}
+	}
+
+	ids, err := parseIDList(args[0], focused)
+	if err != nil {
+		return commandExecutedMsg{err: err}
+	}
+
+	return applyToIDsTogether(ids, func(id int64) error {
+		todo, err := svc.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !todo.IsCompleted() {
+			return service.ErrNotCompleted
+		}
+		return nil
+	}, func(ids []int64) error {
+		return svc.BulkArchive(ctx, ids)
+	}, func(subject string) string {
+		return fmt.Sprintf("Archived %s (press u to undo)", subject)
+	})
+}
+
+// loadArchive loads the archived todos matching query
+func loadArchive(svc *service.TodoService, query string) tea.Cmd {
+	return func() tea.Msg {
+		todos, err := svc.SearchArchive(tuiContext(), query)
+		return archiveLoadedMsg{query: query, todos: todos, err: err}
+	}
+}
+
+// unarchiveCmd brings an archived todo back to the list
+func unarchiveCmd(svc *service.TodoService, id int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.UnarchiveTodo(tuiContext(), id); err != nil {
+			return archiveChangedMsg{err: err}
+		}
+		return archiveChangedMsg{message: fmt.Sprintf("Unarchived todo #%d", id)}
+	}
+}
+
+// undoCmd reverts the latest change
+func undoCmd(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := svc.Undo(tuiContext())
+		if err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: "Undid " + entry.Summary()}
+	}
+}
+
+// redoCmd applies the most recently undone change again
+func redoCmd(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := svc.Redo(tuiContext())
+		if err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: "Redid " + entry.Summary()}
+	}
+}
+
+// loadWorkLogs loads the work log entries of a todo
+func loadWorkLogs(svc *service.TodoService, todoID int64) tea.Cmd {
+	return func() tea.Msg {
+		logs, err := svc.ListWorkLogs(tuiContext(), todoID)
+		return workLogsLoadedMsg{logs: logs, err: err}
+	}
+}
+
+// parseReportArgs parses the /report arguments into a date range and optional CSV path
+// The range defaults to the current week (Monday to Sunday).
+func parseReportArgs(args []string, now time.Time) (from, to time.Time, csvPath string, err error) {
+	from = timeutil.StartOfWeek(now)
+	to = from.AddDate(0, 0, 7)
+
+	// The end depends on --from, so it is applied once every flag is known
+	var last *time.Time
+	fromGiven := false
+	for _, arg := range args {
+		switch {
+		case arg == "--week=this":
+			// Default range
+		case arg == "--week=last":
+			from = from.AddDate(0, 0, -7)
+			to = to.AddDate(0, 0, -7)
+		case strings.HasPrefix(arg, "--from="):
+			from, err = timeutil.ParseDate(strings.TrimPrefix(arg, "--from="), now)
+			if err != nil {
+				return from, to, "", err
+			}
+			from = timeutil.StartOfDay(from)
+			fromGiven = true
+		case strings.HasPrefix(arg, "--to="):
+			date, parseErr := timeutil.ParseDate(strings.TrimPrefix(arg, "--to="), now)
+			if parseErr != nil {
+				return from, to, "", parseErr
+			}
+			last = &date
+		case strings.HasPrefix(arg, "--csv="):
+			csvPath = expandHomePath(strings.TrimPrefix(arg, "--csv="))
+		default:
+			return from, to, "", errors.New("usage: /report [--week=this|last] [--from=date] [--to=date] [--csv=path]")
+		}
+	}
+
+	switch {
+	case last != nil:
+		to = timeutil.StartOfDay(*last).AddDate(0, 0, 1) // --to is inclusive
+	case fromGiven:
+		to = timeutil.StartOfDay(now).AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		return from, to, "", service.ErrInvalidDateRange
+	}
+
+	return from, to, csvPath, nil
+}
+
+// bulkCmd applies a /bulk operation to the selected todos
+func bulkCmd(svc *service.TodoService, ids []int64, args []string) tea.Cmd {
+	return func() tea.Msg {
+		message, err := runBulkOperation(tuiContext(), svc, ids, args)
+		return bulkExecutedMsg{message: message, err: err}
+	}
+}
+
+// runBulkOperation runs a /bulk operation and returns the status message
+func runBulkOperation(ctx context.Context, svc *service.TodoService, ids []int64, args []string) (string, error) {
+	usage := errors.New("usage: /bulk complete | delete | priority <low|medium|high> | due <date|clear> | export [filepath]")
+	if len(args) == 0 {
+		return "", usage
+	}
+
+	count := len(ids)
+	switch args[0] {
+	case "complete":
+		if len(args) != 1 {
+			return "", usage
+		}
+		if err := svc.BulkComplete(ctx, ids); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Completed %d todo(s) (press u to undo)", count), nil
+
+	case "delete":
+		if len(args) != 1 {
+			return "", usage
+		}
+		if err := svc.BulkDelete(ctx, ids); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Deleted %d todo(s) (press u to undo)", count), nil
+
+	case "priority":
+		if len(args) != 2 {
+			return "", usage
+		}
+		priority, err := parsePriority(args[1])
+		if err != nil {
+			return "", err
+		}
+		if err := svc.BulkSetPriority(ctx, ids, priority); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Set priority of %d todo(s) to %s", count, strings.ToLower(args[1])), nil
+
+	case "due":
+		if len(args) < 2 {
+			return "", usage
+		}
+		if len(args) == 2 && args[1] == "clear" {
+			if err := svc.BulkSetDueDate(ctx, ids, nil); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Cleared the due date of %d todo(s)", count), nil
+		}
+		dueDate, err := timeutil.ParseDate(strings.Join(args[1:], " "), time.Now())
+		if err != nil {
+			return "", err
+		}
+		if err := svc.BulkSetDueDate(ctx, ids, &dueDate); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Set due date of %d todo(s) to %s", count, dueDate.Format("Mon 2006-01-02 15:04")), nil
+
+	case "export":
+		if len(args) > 2 {
+			return "", usage
+		}
+		filePath := fmt.Sprintf("%s/.koto/export_selected_%s.json", os.Getenv("HOME"), time.Now().Format("20060102_150405"))
+		if len(args) == 2 {
+			filePath = expandHomePath(args[1])
+		}
+		if err := svc.ExportSelectedToJSON(ctx, ids, filePath); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Exported %d todo(s) to %s", count, filePath), nil
+
+	default:
+		return "", usage
+	}
+}
+
+// parsePriority parses a priority name or number (1 = low, 2 = medium, 3 = high)
+func parsePriority(s string) (model.Priority, error) {
+	switch strings.ToLower(s) {
+	case "1", "l", "low":
+		return model.PriorityLow, nil
+	case "2", "m", "medium":
+		return model.PriorityMedium, nil
+	case "3", "h", "high":
+		return model.PriorityHigh, nil
+	default:
+		return 0, service.ErrInvalidPriority
+	}
+}
+
+// expandHomePath expands a leading ~/ to the home directory
+func expandHomePath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return os.Getenv("HOME") + path[1:]
+	}
+	return path
+}
+
+// loadStats loads the productivity statistics
+func loadStats(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := svc.GetStats(tuiContext(), time.Now())
+		return statsLoadedMsg{stats: stats, err: err}
+	}
+}
+
+// loadGoalProgress loads today's progress towards the daily goal
+func loadGoalProgress(svc *service.TodoService, goal model.DailyGoal) tea.Cmd {
+	return func() tea.Msg {
+		progress, err := svc.GetGoalProgress(tuiContext(), goal, time.Now())
+		return goalProgressLoadedMsg{progress: progress, err: err}
+	}
+}
+
+// clockTick returns a command that sends a clockTickMsg after a minute
+func clockTick() tea.Cmd {
+	return tea.Tick(time.Minute, func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+// sendDueReminders delivers the due date reminders that are due now
+// Reminders are shared with the reminder daemon, so each is delivered only once
+// even when both are running. A reminder that fails to send is released, so
+// that the next tick retries it.
+func sendDueReminders(svc *service.TodoService, notifier notify.Notifier, offsets []int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := tuiContext()
+		reminders, err := svc.ClaimDueReminders(ctx, time.Now(), offsets)
+		if err != nil {
+			return nil // Reminders are best effort; the next tick retries
+		}
+		sent := 0
+		for _, r := range reminders {
+			if err := notifier.Notify(r.Title(), r.Message()); err != nil {
+				_ = svc.ReleaseDueReminder(ctx, r) // Best effort as well
+				continue
+			}
+			sent++
+		}
+
+		todoReminders, err := svc.ClaimReminders(ctx, time.Now())
+		if err != nil {
+			return nil
+		}
+		for _, r := range todoReminders {
+			if err := notifier.Notify("🔔 Reminder", r.Message()); err != nil {
+				_ = svc.ReleaseReminder(ctx, r.ID) // Best effort as well
+				continue
+			}
+			sent++
+		}
+
+		if sent > 0 {
+			_ = notifier.Beep()
+		}
+		return nil
+	}
+}
+
+// loadActiveReminders loads the reminders that are due and not dismissed
+func loadActiveReminders(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		reminders, err := svc.ActiveReminders(tuiContext(), time.Now())
+		return remindersLoadedMsg{reminders: reminders, err: err}
+	}
+}
+
+// loadDetailReminders loads the reminders of the todo shown in the detail view
+func loadDetailReminders(svc *service.TodoService, todoID int64) tea.Cmd {
+	return func() tea.Msg {
+		reminders, err := svc.ListReminders(tuiContext(), todoID)
+		return detailRemindersLoadedMsg{todoID: todoID, reminders: reminders, err: err}
+	}
+}
+
+// loadDetailEvents loads the history of the todo shown in the detail view
+func loadDetailEvents(svc *service.TodoService, todoID int64) tea.Cmd {
+	return func() tea.Msg {
+		events, err := svc.ListTodoEvents(tuiContext(), todoID)
+		return detailEventsLoadedMsg{todoID: todoID, events: events, err: err}
+	}
+}
+
+// snoozeReminderCmd moves a reminder to the time computed from now
+func snoozeReminderCmd(svc *service.TodoService, id int64, until func(now time.Time) (time.Time, error)) tea.Cmd {
+	return func() tea.Msg {
+		at, err := until(time.Now())
+		if err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		if err := svc.SnoozeReminder(tuiContext(), id, at); err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: fmt.Sprintf("Reminder snoozed until %s", at.Format("Mon 15:04"))}
+	}
+}
+
+// dismissReminderCmd dismisses a reminder
+func dismissReminderCmd(svc *service.TodoService, id int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.DismissReminder(tuiContext(), id); err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: "Reminder dismissed"}
+	}
+}
+
+// snoozeFor returns a snooze target that is d after now
+func snoozeFor(d time.Duration) func(now time.Time) (time.Time, error) {
+	return func(now time.Time) (time.Time, error) {
+		return now.Add(d), nil
+	}
+}
+
+// snoozeUntilTomorrowMorning returns the snooze target for "tomorrow 9:00"
+func snoozeUntilTomorrowMorning(now time.Time) (time.Time, error) {
+	return timeutil.ParseDate("tomorrow 9:00", now)
+}
+
+// parseGoalArgs parses the /goal arguments into a daily goal
+// Bare numbers (or "8p") are Pomodoros, durations ("4h", "90m") are focus time,
+// and "off" clears the goal.
+func parseGoalArgs(args []string) (model.DailyGoal, error) {
+	usage := errors.New("usage: /goal <pomodoros> [duration] | /goal <duration> | /goal off")
+
+	var goal model.DailyGoal
+	if len(args) == 1 && strings.EqualFold(args[0], "off") {
+		return goal, nil
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return goal, usage
+	}
+
+	for _, arg := range args {
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(arg), "p")); err == nil {
+			if n <= 0 || goal.Pomodoros != 0 {
+				return model.DailyGoal{}, usage
+			}
+			goal.Pomodoros = n
+			continue
+		}
+
+		minutes, err := timeutil.ParseMinutes(arg)
+		if err != nil || minutes <= 0 || goal.Minutes != 0 {
+			return model.DailyGoal{}, usage
+		}
+		goal.Minutes = minutes
+	}
+
+	return goal, nil
+}
+
+// daySummaryDue returns true if the end-of-day summary should be shown at now
+// summaryTime is a "15:04" time of day (empty disables the summary) and
+// shownOn is the day the summary was last shown.
+func daySummaryDue(now time.Time, summaryTime, shownOn string) bool {
+	if summaryTime == "" || shownOn == now.Format("2006-01-02") {
+		return false
+	}
+
+	clock, err := time.Parse("15:04", summaryTime)
+	if err != nil {
+		return false
+	}
+
+	due := timeutil.StartOfDay(now).Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	return !now.Before(due)
+}
+
+// loadTimesheet loads the timesheet for [from, to) and optionally exports it to CSV
+func loadTimesheet(svc *service.TodoService, from, to time.Time, csvPath string) tea.Cmd {
+	return func() tea.Msg {
+		ts, err := svc.GetTimesheet(tuiContext(), from, to)
+		if err != nil {
+			return timesheetLoadedMsg{err: err}
+		}
+
+		if csvPath == "" {
+			return timesheetLoadedMsg{timesheet: ts}
+		}
+
+		if err := svc.ExportTimesheetToCSV(ts, csvPath); err != nil {
+			return timesheetLoadedMsg{timesheet: ts, err: err}
+		}
+		return timesheetLoadedMsg{timesheet: ts, message: "Timesheet exported to " + csvPath}
+	}
+}
+
+// loadStopwatch loads the running stopwatch from the service
+func loadStopwatch(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		stopwatch, err := svc.GetStopwatch(tuiContext())
+		if err != nil || stopwatch == nil {
+			return stopwatchLoadedMsg{stopwatch: stopwatch, err: err}
+		}
+		todo, err := svc.GetTodo(tuiContext(), stopwatch.TodoID)
+		if err != nil {
+			return stopwatchLoadedMsg{stopwatch: stopwatch, err: err}
+		}
+		return stopwatchLoadedMsg{stopwatch: stopwatch, title: todo.Title}
+	}
+}
+
+// startStopwatchCmd starts the stopwatch for a todo
+func startStopwatchCmd(svc *service.TodoService, todoID int64) tea.Cmd {
+	return func() tea.Msg {
+		return handleStartCommand(tuiContext(), svc, []string{strconv.FormatInt(todoID, 10)}, 0)
+	}
+}
+
+// stopStopwatchCmd stops the running stopwatch and records the elapsed time
+func stopStopwatchCmd(svc *service.TodoService) tea.Cmd {
+	return func() tea.Msg {
+		return stopStopwatch(tuiContext(), svc)
+	}
+}
+
+// tickStopwatch creates a command that waits 1 second and sends a stopwatch tick message
+func tickStopwatch() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return stopwatchTickMsg{}
+	})
+}
+
 // tickPomodoro creates a command that waits 1 second and sends a tick message
 func tickPomodoro() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -165,12 +1089,19 @@ func tickPomodoroAlert() tea.Cmd {
 	})
 }
 
+// snoozePomodoroAlert creates a command that sends a snooze end message after the snooze period
+func snoozePomodoroAlert(minutes int) tea.Cmd {
+	return tea.Tick(time.Duration(minutes)*time.Minute, func(t time.Time) tea.Msg {
+		return pomodoroSnoozeEndMsg{}
+	})
+}
+
 // completePomodoroWithRecording handles timer completion and records work duration
-func completePomodoroWithRecording(svc *service.TodoService, todoID int64) tea.Cmd {
+func completePomodoroWithRecording(svc *service.TodoService, notifier notify.Notifier, todoID int64) tea.Cmd {
 	return func() tea.Msg {
 		// If this was a task-specific timer, record the work duration
 		if todoID > 0 {
-			ctx := context.Background()
+			ctx := tuiContext()
 			err := svc.AddWorkDuration(ctx, todoID, 25) // 25 minutes
 			if err != nil {
 				return commandExecutedMsg{
@@ -179,9 +1110,14 @@ func completePomodoroWithRecording(svc *service.TodoService, todoID int64) tea.C
 			}
 		}
 
-		// Play beep sound (880Hz, 500ms) - high and long
-		// Ignore errors - beep failure should not break the app
-		_ = beeep.Beep(880.0, 500)
+		// Notify outside the terminal and play the alert sound
+		// Ignore errors - notification failure should not break the app
+		message := "Time for a break!"
+		if todoID > 0 {
+			message = fmt.Sprintf("25 minutes recorded for todo #%d. Time for a break!", todoID)
+		}
+		_ = notifier.Notify("🍅 Pomodoro complete", message)
+		_ = notifier.Beep()
 
 		return pomodoroCompleteMsg{todoID: todoID}
 	}
@@ -190,8 +1126,8 @@ func completePomodoroWithRecording(svc *service.TodoService, todoID int64) tea.C
 // recordPartialPomodoro records partial work duration when timer is stopped early
 func recordPartialPomodoro(svc *service.TodoService, todoID int64, minutes int) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		err := svc.AddWorkDuration(ctx, todoID, minutes)
+		ctx := tuiContext()
+		err := svc.AddPartialWorkDuration(ctx, todoID, minutes)
 		if err != nil {
 			return commandExecutedMsg{
 				err: fmt.Errorf("failed to record work duration: %w", err),
@@ -0,0 +1,424 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/history"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+func TestParseReportArgs(t *testing.T) {
+	// Wednesday, 2025-01-15
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantCSV  string
+		wantErr  bool
+	}{
+		{"default is this week", nil, day(1, 13), day(1, 20), "", false},
+		{"last week", []string{"--week=last"}, day(1, 6), day(1, 13), "", false},
+		{"custom range is inclusive", []string{"--from=2025-01-01", "--to=2025-01-31"}, day(1, 1), day(2, 1), "", false},
+		{"custom range in reverse order", []string{"--to=2025-01-31", "--from=2025-01-01"}, day(1, 1), day(2, 1), "", false},
+		{"to only ends the current week early", []string{"--to=2025-01-16"}, day(1, 13), day(1, 17), "", false},
+		{"from only runs until today", []string{"--from=2025-01-10"}, day(1, 10), day(1, 16), "", false},
+		{"csv path", []string{"--csv=/tmp/ts.csv"}, day(1, 13), day(1, 20), "/tmp/ts.csv", false},
+		{"end before start", []string{"--from=2025-01-10", "--to=2025-01-01"}, time.Time{}, time.Time{}, "", true},
+		{"unknown flag", []string{"--month"}, time.Time{}, time.Time{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, csvPath, err := parseReportArgs(tt.args, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseReportArgs(%v) expected error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReportArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("parseReportArgs(%v) = [%v, %v); want [%v, %v)", tt.args, from, to, tt.wantFrom, tt.wantTo)
+			}
+			if csvPath != tt.wantCSV {
+				t.Errorf("parseReportArgs(%v) csv = %q; want %q", tt.args, csvPath, tt.wantCSV)
+			}
+		})
+	}
+}
+
+func TestParseGoalArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected model.DailyGoal
+		wantErr  bool
+	}{
+		{"pomodoros", []string{"8"}, model.DailyGoal{Pomodoros: 8}, false},
+		{"pomodoros with suffix", []string{"8p"}, model.DailyGoal{Pomodoros: 8}, false},
+		{"duration", []string{"4h"}, model.DailyGoal{Minutes: 240}, false},
+		{"both", []string{"8", "4h"}, model.DailyGoal{Pomodoros: 8, Minutes: 240}, false},
+		{"both reversed", []string{"3h30m", "6"}, model.DailyGoal{Pomodoros: 6, Minutes: 210}, false},
+		{"off", []string{"off"}, model.DailyGoal{}, false},
+		{"no arguments", nil, model.DailyGoal{}, true},
+		{"two counts", []string{"8", "6"}, model.DailyGoal{}, true},
+		{"zero", []string{"0"}, model.DailyGoal{}, true},
+		{"invalid", []string{"lots"}, model.DailyGoal{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal, err := parseGoalArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseGoalArgs(%v) expected error, got %+v", tt.args, goal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseGoalArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if goal != tt.expected {
+				t.Errorf("parseGoalArgs(%v) = %+v; expected %+v", tt.args, goal, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDaySummaryDue(t *testing.T) {
+	at := func(hh, mm int) time.Time { return time.Date(2025, 1, 15, hh, mm, 0, 0, time.Local) }
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		summaryTime string
+		shownOn     string
+		expected    bool
+	}{
+		{"before summary time", at(17, 59), "18:00", "", false},
+		{"at summary time", at(18, 0), "18:00", "", true},
+		{"shown yesterday", at(21, 0), "18:00", "2025-01-14", true},
+		{"already shown today", at(21, 0), "18:00", "2025-01-15", false},
+		{"disabled", at(21, 0), "", "", false},
+		{"invalid time", at(21, 0), "6pm", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daySummaryDue(tt.now, tt.summaryTime, tt.shownOn); got != tt.expected {
+				t.Errorf("daySummaryDue() = %v; expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleSelectKey(t *testing.T) {
+	m := Model{todos: []*model.Todo{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}}
+
+	// Space toggles the focused todo and anchors the range
+	m.cursor = 1
+	m.handleSelectKey(" ")
+	m.cursor = 3
+	m.handleSelectKey("V")
+	if ids := m.selectedIDs(); !slices.Equal(ids, []int64{2, 3, 4}) {
+		t.Errorf("expected todos 2-4 to be selected, got %v", ids)
+	}
+
+	m.handleSelectKey(" ")
+	if ids := m.selectedIDs(); !slices.Equal(ids, []int64{2, 3}) {
+		t.Errorf("expected todo 4 to be unselected, got %v", ids)
+	}
+
+	// * selects everything, and clears the selection when everything is selected
+	m.handleSelectKey("*")
+	if ids := m.selectedIDs(); len(ids) != 5 {
+		t.Errorf("expected all todos to be selected, got %v", ids)
+	}
+	m.handleSelectKey("*")
+	if ids := m.selectedIDs(); len(ids) != 0 {
+		t.Errorf("expected an empty selection, got %v", ids)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected model.Priority
+		wantErr  bool
+	}{
+		{"low", model.PriorityLow, false},
+		{"2", model.PriorityMedium, false},
+		{"High", model.PriorityHigh, false},
+		{"urgent", 0, true},
+	}
+
+	for _, tt := range tests {
+		priority, err := parsePriority(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePriority(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && priority != tt.expected {
+			t.Errorf("parsePriority(%q) = %v; expected %v", tt.input, priority, tt.expected)
+		}
+	}
+}
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		input   string
+		focused int64
+		want    []int64
+		wantErr bool
+	}{
+		{"3", 0, []int64{3}, false},
+		{"3,5,9-12", 0, []int64{3, 5, 9, 10, 11, 12}, false},
+		{"5,3,5,4-6", 0, []int64{5, 3, 4, 6}, false},
+		{".", 7, []int64{7}, false},
+		{"1,.", 7, []int64{1, 7}, false},
+		{".", 0, nil, true},
+		{"abc", 0, nil, true},
+		{"0", 0, nil, true},
+		{"12-9", 0, nil, true},
+		{"3,", 0, nil, true},
+		{"1-5000", 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		ids, err := parseIDList(tt.input, tt.focused)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseIDList(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("parseIDList(%q) = %v; expected %v", tt.input, ids, tt.want)
+		}
+	}
+}
+
+func TestApplyToIDs(t *testing.T) {
+	describe := func(subject string) string { return "Deleted " + subject }
+	failOn := func(bad ...int64) func(int64) error {
+		return func(id int64) error {
+			if slices.Contains(bad, id) {
+				return errors.New("todo not found")
+			}
+			return nil
+		}
+	}
+
+	msg := applyToIDs([]int64{3}, failOn(), describe)
+	if msg.err != nil || msg.message != "Deleted todo #3" {
+		t.Errorf("single ID: got %q, %v", msg.message, msg.err)
+	}
+
+	msg = applyToIDs([]int64{3}, failOn(3), describe)
+	if msg.err == nil || msg.err.Error() != "todo not found" {
+		t.Errorf("single failing ID: expected the plain error, got %v", msg.err)
+	}
+
+	msg = applyToIDs([]int64{3, 5, 11}, failOn(11), describe)
+	if msg.message != "Deleted todos #3, #5" {
+		t.Errorf("expected successes in message, got %q", msg.message)
+	}
+	if msg.err == nil || msg.err.Error() != "1 of 3 failed: #11: todo not found" {
+		t.Errorf("expected per-ID failures, got %v", msg.err)
+	}
+
+	msg = applyToIDs([]int64{3, 5}, failOn(3, 5), describe)
+	if msg.message != "" || msg.err == nil {
+		t.Errorf("expected only an error when all fail, got %q, %v", msg.message, msg.err)
+	}
+}
+
+func TestIDListCommands_UndoTogether(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, title := range []string{"Ship", "Test", "Fix"} {
+		todo, err := svc.AddTodo(ctx, title, "", model.PriorityMedium, nil)
+		if err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+		ids = append(ids, todo.ID)
+	}
+	visible := func() int {
+		todos, err := svc.ListTodos(ctx)
+		if err != nil {
+			t.Fatalf("ListTodos failed: %v", err)
+		}
+		return len(todos)
+	}
+
+	// A missing ID is reported, the others are deleted as one undo step
+	msg := handleDoneCommand(ctx, svc, []string{"1-3,99"}, 0)
+	if msg.message != "Deleted todos #1, #2, #3 (press u to undo)" {
+		t.Errorf("unexpected message %q", msg.message)
+	}
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "#99") {
+		t.Errorf("expected #99 to fail, got %v", msg.err)
+	}
+	if n := visible(); n != 0 {
+		t.Fatalf("expected every todo deleted, %d left", n)
+	}
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if n := visible(); n != 3 {
+		t.Errorf("expected one undo to restore all 3 todos, got %d", n)
+	}
+
+	// Archiving checks each todo and archives the completed ones together
+	for _, id := range ids[:2] {
+		if err := svc.CompleteTodo(ctx, id); err != nil {
+			t.Fatalf("CompleteTodo failed: %v", err)
+		}
+	}
+	msg = handleArchiveCommand(ctx, svc, []string{"1-3"}, 0)
+	if msg.message != "Archived todos #1, #2 (press u to undo)" {
+		t.Errorf("unexpected message %q", msg.message)
+	}
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "#3: "+service.ErrNotCompleted.Error()) {
+		t.Errorf("expected #3 to fail, got %v", msg.err)
+	}
+	if n := visible(); n != 1 {
+		t.Fatalf("expected 2 todos archived, %d visible", n)
+	}
+	if _, err := svc.Undo(ctx); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if n := visible(); n != 3 {
+		t.Errorf("expected one undo to unarchive both todos, %d visible", n)
+	}
+}
+
+func TestInputHistory(t *testing.T) {
+	hist, _ := history.Load("", 10)
+	for _, entry := range []string{"/pomo 3", "/list", "/pomo 12"} {
+		_ = hist.Add(entry)
+	}
+
+	input := textinput.New()
+	input.Focus()
+	m := Model{viewMode: ViewModeList, input: input, history: hist}
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			updated, _ := m.Update(key)
+			m = updated.(Model)
+		}
+	}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	text := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	expectInput := func(step, want string) {
+		t.Helper()
+		if got := m.input.Value(); got != want {
+			t.Errorf("%s: expected input %q, got %q", step, want, got)
+		}
+	}
+
+	// With an empty input up moves the cursor instead
+	press(up)
+	expectInput("up on empty input", "")
+
+	// With an empty input Ctrl+R redoes instead of searching
+	press(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.historySearch != nil {
+		t.Error("expected Ctrl+R on empty input not to start a search")
+	}
+
+	// Up and down recall the commands starting with the typed text
+	press(text("/po"), up)
+	expectInput("first up", "/pomo 12")
+	press(up, up)
+	expectInput("up past the oldest match", "/pomo 3")
+	press(down)
+	expectInput("down", "/pomo 12")
+	press(down)
+	expectInput("down past the newest match", "/po")
+
+	// Ctrl+R searches the history for the typed text, Esc restores it
+	press(tea.KeyMsg{Type: tea.KeyEsc}, text("li"), tea.KeyMsg{Type: tea.KeyCtrlR})
+	expectInput("reverse search", "/list")
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	expectInput("cancelled search", "li")
+	if m.historySearch != nil {
+		t.Error("expected the search to end")
+	}
+
+	// Refining the query and Ctrl+R find older matches
+	press(tea.KeyMsg{Type: tea.KeyEsc}, text("p"), tea.KeyMsg{Type: tea.KeyCtrlR})
+	expectInput("search for p", "/pomo 12")
+	press(tea.KeyMsg{Type: tea.KeyCtrlR})
+	expectInput("older match", "/pomo 3")
+	press(text("x"))
+	if m.historySearch == nil || m.historySearch.match != "" {
+		t.Errorf("expected no match for px, got %+v", m.historySearch)
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	expectInput("shorter query", "/pomo 12")
+}
+
+func TestParseQuickAdd(t *testing.T) {
+	// Wednesday, 2025-01-15
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+
+	todo, err := parseQuickAdd(strings.Fields("Fix login bug !high @due:fri #auth #UI -- Users get logged out"), now)
+	if err != nil {
+		t.Fatalf("parseQuickAdd failed: %v", err)
+	}
+	if todo.title != "Fix login bug" {
+		t.Errorf("expected title %q, got %q", "Fix login bug", todo.title)
+	}
+	if todo.description != "Users get logged out" {
+		t.Errorf("expected description %q, got %q", "Users get logged out", todo.description)
+	}
+	if todo.priority != model.PriorityHigh {
+		t.Errorf("expected high priority, got %v", todo.priority)
+	}
+	if todo.dueDate == nil || todo.dueDate.Weekday() != time.Friday || todo.dueDate.Day() != 17 {
+		t.Errorf("expected due Friday the 17th, got %v", todo.dueDate)
+	}
+	if !slices.Equal(todo.tags, []string{"auth", "UI"}) {
+		t.Errorf("expected tags [auth UI], got %v", todo.tags)
+	}
+
+	// Markers after -- belong to the description, and the priority defaults to medium
+	todo, err = parseQuickAdd(strings.Fields("Write notes -- see #12 !important"), now)
+	if err != nil {
+		t.Fatalf("parseQuickAdd failed: %v", err)
+	}
+	if todo.title != "Write notes" || todo.description != "see #12 !important" || todo.tags != nil {
+		t.Errorf("unexpected quick add %+v", todo)
+	}
+	if todo.priority != model.PriorityMedium || todo.dueDate != nil {
+		t.Errorf("expected medium priority and no due date, got %+v", todo)
+	}
+
+	for _, input := range []string{"!high #auth", "Title !urgent", "Title @due:someday", "-- only a description"} {
+		if _, err := parseQuickAdd(strings.Fields(input), now); err == nil {
+			t.Errorf("parseQuickAdd(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Operations asked for confirmation, as listed in the confirm.skip setting
+// Permanent deletions from the trash are always confirmed, whatever confirm.skip says.
+const (
+	confirmDelete = "delete"
+	confirmBulk   = "bulk"
+	confirmImport = "import"
+	confirmPurge  = "purge"
+)
+
+// maxConfirmDetails limits the todos listed in the confirmation dialog
+const maxConfirmDetails = 5
+
+// confirmation is an operation waiting for the user to confirm it
+type confirmation struct {
+	kind    string   // confirmDelete, confirmBulk, confirmImport or confirmPurge
+	title   string   // Question, e.g. "Delete 3 todos?"
+	details []string // Lines describing what is affected
+	typed   string   // Text to type to confirm ("" if y is enough)
+	input   textinput.Model
+	run     func(m *Model) tea.Cmd // Runs the operation
+}
+
+// skippable reports whether the user may choose not to be asked again for this kind of operation
+// This matches config.ConfirmConfig.Skips, which always confirms purges.
+func (c confirmation) skippable() bool {
+	return c.kind != confirmPurge
+}
+
+// destructive reports whether the operation changes or deletes todos, which
+// requires an explicit y rather than Enter, so a repeated Enter cannot confirm it
+func (c confirmation) destructive() bool {
+	return c.kind != confirmImport
+}
+
+// confirmOperation runs the operation on count todos once the user confirms it
+// Operations listed in confirm.skip run right away, unless they affect more
+// todos than confirm.typed_above, which always requires typing the count.
+func (m *Model) confirmOperation(c confirmation, count int) tea.Cmd {
+	if m.config.Confirm.RequiresTyping(count) {
+		c.typed = strconv.Itoa(count)
+		c.input = textinput.New()
+		c.input.Prompt = "> "
+		c.input.Placeholder = c.typed
+		c.input.CharLimit = 10
+		c.input.Width = 20
+		c.input.Focus()
+	} else if m.config.Confirm.Skips(c.kind) {
+		return c.run(m)
+	}
+
+	m.confirm = &c
+	m.message = ""
+	m.err = nil
+	return nil
+}
+
+// confirmTodoDetails describes the todos with the given IDs for the confirmation dialog
+func (m *Model) confirmTodoDetails(ids []int64) []string {
+	var details []string
+	for _, id := range ids {
+		if len(details) == maxConfirmDetails {
+			details = append(details, fmt.Sprintf("… and %d more", len(ids)-maxConfirmDetails))
+			break
+		}
+		line := fmt.Sprintf("#%d", id)
+		if todo, err := m.findTodo(id); err == nil {
+			line += " " + todo.Title
+		}
+		details = append(details, line)
+	}
+	return details
+}
+
+// handleConfirmKey handles a key while the confirmation dialog is shown
+// y runs the operation (Enter too unless it is destructive), a runs it and
+// stops asking for this kind of operation (unless it cannot be skipped), n or
+// Esc cancels. A typed confirmation runs on Enter once the count has been typed.
+func (m *Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		return m, m.cancelConfirm()
+	}
+
+	if c.typed != "" {
+		if msg.String() != "enter" {
+			var cmd tea.Cmd
+			c.input, cmd = c.input.Update(msg)
+			m.err = nil
+			return m, cmd
+		}
+		if strings.TrimSpace(c.input.Value()) != c.typed {
+			m.err = fmt.Errorf("type %s to confirm", c.typed)
+			return m, nil
+		}
+		m.confirm = nil
+		m.err = nil
+		return m, c.run(m)
+	}
+
+	switch msg.String() {
+	case "enter":
+		if c.destructive() {
+			return m, nil
+		}
+		m.confirm = nil
+		return m, c.run(m)
+
+	case "y", "Y":
+		m.confirm = nil
+		return m, c.run(m)
+
+	case "a", "A":
+		if !c.skippable() {
+			return m, nil
+		}
+		m.confirm = nil
+		previous := m.config.Confirm.Skip
+		m.config.Confirm.Skip = append(slices.Clone(previous), c.kind)
+		cmd := c.run(m)
+		if err := m.config.Save(); err != nil {
+			m.config.Confirm.Skip = previous
+			m.err = err
+		}
+		return m, cmd
+
+	case "n", "N":
+		return m, m.cancelConfirm()
+	}
+	return m, nil
+}
+
+// cancelConfirm closes the confirmation dialog without running the operation
+func (m *Model) cancelConfirm() tea.Cmd {
+	m.confirm = nil
+	m.message = "Cancelled"
+	m.err = nil
+	return nil
+}
+
+// renderConfirmView renders the confirmation dialog in the middle of the screen
+func (m Model) renderConfirmView() string {
+	c := m.confirm
+	var s strings.Builder
+
+	s.WriteString(lipgloss.NewStyle().Foreground(accentRed).Bold(true).Render(c.title))
+	s.WriteString("\n")
+	for _, line := range c.details {
+		s.WriteString("\n  " + line)
+	}
+	s.WriteString("\n\n")
+
+	if c.typed != "" {
+		s.WriteString(fmt.Sprintf("This affects %s todos. Type %s to confirm:\n", c.typed, lipgloss.NewStyle().Foreground(accentGreen).Bold(true).Render(c.typed)))
+		s.WriteString(c.input.View())
+		s.WriteString("\n")
+		if m.err != nil {
+			s.WriteString(errorStyle.Render("✗ " + m.err.Error()))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("Enter: confirm | Esc: cancel"))
+	} else {
+		keys := []string{"y/Enter: confirm"}
+		if c.destructive() {
+			keys[0] = "y: confirm"
+		}
+		if c.skippable() {
+			keys = append(keys, "a: always (don't ask again)")
+		}
+		keys = append(keys, "n/Esc: cancel")
+		s.WriteString(helpStyle.Render(strings.Join(keys, " | ")))
+	}
+
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(accentRed).
+		Padding(1, 2).
+		Render(s.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
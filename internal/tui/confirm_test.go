@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/config"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+func TestConfirmOperation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".koto"), 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+	ctx := context.Background()
+
+	var todos []*model.Todo
+	for _, title := range []string{"Ship", "Test", "Fix"} {
+		todo, err := svc.AddTodo(ctx, title, "", model.PriorityMedium, nil)
+		if err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+		todos = append(todos, todo)
+	}
+
+	m := &Model{service: svc, config: cfg, viewMode: ViewModeDetail, detailTodoID: todos[0].ID, todos: todos}
+	// press sends the keys and runs the command of the last one
+	press := func(keys ...tea.KeyMsg) {
+		var cmd tea.Cmd
+		for _, key := range keys {
+			_, cmd = m.handleConfirmKey(key)
+		}
+		if cmd != nil {
+			cmd()
+		}
+	}
+	text := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	exists := func(id int64) bool {
+		_, err := repo.GetByID(ctx, id)
+		return err == nil
+	}
+
+	// d in the detail view asks first, n cancels
+	switch updated, _ := m.Update(text("d")); next := updated.(type) {
+	case Model:
+		m = &next
+	case *Model:
+		m = next
+	}
+	if m.confirm == nil || m.confirm.details[0] != "#1 Ship" {
+		t.Fatalf("expected a confirmation for #1 Ship, got %+v", m.confirm)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.confirm == nil || !exists(todos[0].ID) {
+		t.Fatal("expected Enter not to confirm the deletion")
+	}
+	press(text("n"))
+	if m.confirm != nil || m.message != "Cancelled" || !exists(todos[0].ID) {
+		t.Fatal("expected n to cancel the deletion")
+	}
+
+	// a deletes and stops asking for deletions
+	m.confirmOperation(confirmation{kind: confirmDelete, run: func(m *Model) tea.Cmd {
+		return func() tea.Msg { return svc.DeleteTodo(ctx, todos[0].ID) }
+	}}, 1)
+	press(text("a"))
+	if exists(todos[0].ID) || m.err != nil {
+		t.Fatalf("expected a to delete the todo, got error %v", m.err)
+	}
+	reloaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reloaded.Confirm.Skips(confirmDelete) {
+		t.Error("expected the skipped confirmation to be saved")
+	}
+	ran := false
+	m.confirmOperation(confirmation{kind: confirmDelete, run: func(m *Model) tea.Cmd { ran = true; return nil }}, 1)
+	if !ran || m.confirm != nil {
+		t.Error("expected a skipped confirmation to run right away")
+	}
+
+	// More todos than typed_above require typing their count, even when skipped
+	cfg.Confirm.TypedAbove = 1
+	cmd := lookupCommand("/done").run(m, []string{"2-3"})
+	if cmd != nil || m.confirm == nil || m.confirm.typed != "2" {
+		t.Fatalf("expected a typed confirmation, got %+v", m.confirm)
+	}
+	press(text("y"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.confirm == nil || m.err == nil || !exists(todos[1].ID) {
+		t.Fatal("expected the wrong count to be rejected")
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, text("2"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.confirm != nil || exists(todos[1].ID) || exists(todos[2].ID) {
+		t.Error("expected the todos to be deleted once the count is typed")
+	}
+}
+
+func TestTrashPurgeConfirmation(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, err := svc.AddTodo(ctx, "Old draft", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	if err := svc.DeleteTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+	trash, err := svc.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+
+	m := &Model{service: svc, config: &config.Config{Confirm: config.ConfirmConfig{TypedAbove: 10}}, viewMode: ViewModeTrash, trash: trash}
+	press := func(key string) tea.Cmd {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		switch next := updated.(type) {
+		case Model:
+			m = &next
+		case *Model:
+			m = next
+		}
+		return cmd
+	}
+	inTrash := func() bool {
+		trash, err := svc.ListTrash(ctx)
+		return err == nil && len(trash) == 1
+	}
+
+	// X asks first, n cancels
+	if cmd := press("X"); cmd != nil || m.confirm == nil || m.confirm.details[0] != "#1 Old draft" {
+		t.Fatalf("expected a confirmation for #1 Old draft, got %+v", m.confirm)
+	}
+	press("n")
+	if m.confirm != nil || !inTrash() {
+		t.Fatal("expected n to keep the todo in the trash")
+	}
+
+	// Skipping delete confirmations does not skip purges, which cannot be skipped
+	m.config.Confirm.Skip = []string{confirmDelete}
+	press("X")
+	if m.confirm == nil || m.confirm.kind != confirmPurge {
+		t.Fatal("expected the purge to ask even with deletions skipped")
+	}
+	press("a")
+	switch updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); next := updated.(type) {
+	case Model:
+		m = &next
+	case *Model:
+		m = next
+	}
+	if m.confirm == nil || !inTrash() {
+		t.Fatal("expected neither a nor Enter to confirm the purge")
+	}
+
+	// y purges the todo
+	cmd := press("y")
+	if cmd == nil {
+		t.Fatal("expected y to purge the todo")
+	}
+	cmd()
+	if inTrash() {
+		t.Error("expected the todo to be purged")
+	}
+}
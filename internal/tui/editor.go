@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxDescriptionLength limits the descriptions typed in the add and edit views
+const maxDescriptionLength = 10000
+
+// editorFinishedMsg is sent when the external editor exits
+type editorFinishedMsg struct {
+	content string // Edited text
+	err     error
+}
+
+// newDescriptionInput returns a focused multi-line input holding the description
+func newDescriptionInput(value string) textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Enter description (optional)..."
+	ta.ShowLineNumbers = false
+	ta.CharLimit = maxDescriptionLength
+	ta.SetWidth(80)
+	ta.SetHeight(8)
+	ta.SetValue(value)
+	ta.Focus()
+	return ta
+}
+
+// editorCommand returns the command line of the external editor
+// $VISUAL is preferred over $EDITOR; vi is used if neither is set.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openEditor suspends the program and edits the text in the external editor
+// The text is written to a temp file, which is read back and removed when the
+// editor exits.
+func openEditor(text string) tea.Cmd {
+	path, err := writeEditorFile(text)
+	if err != nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: err}
+		}
+	}
+
+	args := append(editorCommand(), path)
+	c := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer func() {
+			_ = os.Remove(path) // Ignore remove error, a leftover temp file is harmless
+		}()
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("failed to run editor: %w", err)}
+		}
+		content, err := readEditorFile(path)
+		return editorFinishedMsg{content: content, err: err}
+	})
+}
+
+// writeEditorFile writes the text to a new temp file and returns its path
+func writeEditorFile(text string) (string, error) {
+	f, err := os.CreateTemp("", "koto-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()           // Ignore close error, the write error is more important
+		_ = os.Remove(f.Name()) // Ignore remove error, a leftover temp file is harmless
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name()) // Ignore remove error, a leftover temp file is harmless
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// readEditorFile reads the edited text, dropping the trailing newlines editors add
+func readEditorFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.TrimRight(text, "\n"), nil
+}
+
+// handleDescriptionKey handles a key in the description step of the add view
+// Enter starts a new line, Ctrl+S or Tab continues to the priority step and
+// Ctrl+E opens the description in the external editor.
+func (m *Model) handleDescriptionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+s", "tab":
+		return m.handleAddTodoEnter()
+	case "ctrl+e":
+		m.err = nil
+		return m, openEditor(m.descInput.Value())
+	}
+
+	var cmd tea.Cmd
+	m.descInput, cmd = m.descInput.Update(msg)
+	return m, cmd
+}
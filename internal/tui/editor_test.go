@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(); !slices.Equal(got, []string{"vi"}) {
+		t.Errorf("expected vi without an editor set, got %v", got)
+	}
+
+	t.Setenv("EDITOR", "code --wait")
+	if got := editorCommand(); !slices.Equal(got, []string{"code", "--wait"}) {
+		t.Errorf("expected $EDITOR split into arguments, got %v", got)
+	}
+
+	t.Setenv("VISUAL", "nano")
+	if got := editorCommand(); !slices.Equal(got, []string{"nano"}) {
+		t.Errorf("expected $VISUAL to be preferred, got %v", got)
+	}
+}
+
+func TestEditorFile(t *testing.T) {
+	path, err := writeEditorFile("first line\nsecond line")
+	if err != nil {
+		t.Fatalf("writeEditorFile failed: %v", err)
+	}
+	defer os.Remove(path)
+
+	// Simulate an editor saving the file with Windows line endings and a trailing newline
+	if err := os.WriteFile(path, []byte("first line\r\nchanged line\r\n\r\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	content, err := readEditorFile(path)
+	if err != nil {
+		t.Fatalf("readEditorFile failed: %v", err)
+	}
+	if content != "first line\nchanged line" {
+		t.Errorf("expected the edited text without trailing newlines, got %q", content)
+	}
+}
+
+func TestDescriptionStep(t *testing.T) {
+	input := textinput.New()
+	input.Focus()
+	m := Model{viewMode: ViewModeAddTodo, input: input}
+	// The add view handlers return a *Model
+	send := func(msg tea.Msg) {
+		switch updated, _ := m.Update(msg); updated := updated.(type) {
+		case Model:
+			m = updated
+		case *Model:
+			m = *updated
+		}
+	}
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			send(key)
+		}
+	}
+	text := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// Enter continues from the title and starts new lines in the description
+	press(text("Title"), enter, text("line one"), enter, text("line two"))
+	if m.addTodoStep != 1 || m.descInput.Value() != "line one\nline two" {
+		t.Fatalf("expected a two-line description, got step %d with %q", m.addTodoStep, m.descInput.Value())
+	}
+
+	// Text from the external editor replaces the description
+	send(editorFinishedMsg{content: "from\nthe editor"})
+	if got := m.descInput.Value(); got != "from\nthe editor" {
+		t.Errorf("expected the editor text, got %q", got)
+	}
+
+	// Esc keeps the description when going back to the title
+	press(tea.KeyMsg{Type: tea.KeyEsc}, enter)
+	if got := m.descInput.Value(); got != "from\nthe editor" {
+		t.Errorf("expected the description to be kept, got %q", got)
+	}
+
+	// Ctrl+S continues to the priority step
+	press(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.addTodoStep != 2 || m.addTodoDescription != "from\nthe editor" {
+		t.Errorf("expected the priority step with the description saved, got step %d with %q", m.addTodoStep, m.addTodoDescription)
+	}
+}
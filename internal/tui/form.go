@@ -0,0 +1,312 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
+)
+
+// Fields of the edit form, in Tab order
+const (
+	formFieldTitle = iota
+	formFieldDescription
+	formFieldPriority
+	formFieldDue
+	formFieldCount // Number of fields
+)
+
+// formFieldLabels are the labels shown in front of the form fields
+var formFieldLabels = [formFieldCount]string{
+	formFieldTitle:       "Title",
+	formFieldDescription: "Description",
+	formFieldPriority:    "Priority",
+	formFieldDue:         "Due date",
+}
+
+// formPriorities are the choices of the priority field, in display order
+var formPriorities = []struct {
+	priority model.Priority
+	label    string
+}{
+	{model.PriorityLow, "Low"},
+	{model.PriorityMedium, "Medium"},
+	{model.PriorityHigh, "High"},
+}
+
+// todoFormValues are the raw values of the edit form fields
+type todoFormValues struct {
+	title       string
+	description string
+	priority    model.Priority
+	due         string
+}
+
+// todoForm edits all fields of a todo on one screen
+type todoForm struct {
+	todoID         int64
+	title          textinput.Model
+	description    textarea.Model
+	priority       model.Priority
+	due            textinput.Model
+	focus          int                    // Focused field (one of the formField* constants)
+	errs           [formFieldCount]string // Validation error of each field ("" if valid)
+	initial        todoFormValues         // Values the form was opened with
+	confirmDiscard bool                   // Whether Esc is waiting for the discard confirmation
+}
+
+// newTodoForm returns the edit form of the todo with the title focused
+func newTodoForm(todo *model.Todo, width int) todoForm {
+	title := textinput.New()
+	title.Placeholder = "Todo title"
+	title.CharLimit = 500
+	title.Width = width
+	title.SetValue(todo.Title)
+
+	due := textinput.New()
+	due.Placeholder = "none (e.g. fri, 2025-12-31, tomorrow 9:00, +3d)"
+	due.CharLimit = 50
+	due.Width = width
+	due.SetValue(formatFormDate(todo.DueDate))
+
+	description := newDescriptionInput(todo.Description)
+	description.SetWidth(width)
+	description.Blur()
+
+	f := todoForm{
+		todoID:      todo.ID,
+		title:       title,
+		description: description,
+		priority:    todo.Priority,
+		due:         due,
+	}
+	f.initial = f.values()
+	f.setFocus(formFieldTitle)
+	return f
+}
+
+// formatFormDate formats a due date so the due date field parses it back
+func formatFormDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	if t.Equal(timeutil.StartOfDay(*t)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// values returns the current raw field values
+func (f *todoForm) values() todoFormValues {
+	return todoFormValues{
+		title:       f.title.Value(),
+		description: f.description.Value(),
+		priority:    f.priority,
+		due:         f.due.Value(),
+	}
+}
+
+// dirty reports whether any field differs from the values the form was opened with
+func (f *todoForm) dirty() bool {
+	return f.values() != f.initial
+}
+
+// setFocus focuses the field, validating the field that loses the focus
+func (f *todoForm) setFocus(field int) {
+	if field != f.focus {
+		f.validateField(f.focus, time.Now())
+	}
+	f.focus = field
+
+	f.title.Blur()
+	f.description.Blur()
+	f.due.Blur()
+	switch field {
+	case formFieldTitle:
+		f.title.Focus()
+	case formFieldDescription:
+		f.description.Focus()
+	case formFieldDue:
+		f.due.Focus()
+	}
+}
+
+// validateField checks the value of the field and records its error
+func (f *todoForm) validateField(field int, now time.Time) {
+	f.errs[field] = ""
+	switch field {
+	case formFieldTitle:
+		if strings.TrimSpace(f.title.Value()) == "" {
+			f.errs[field] = "title cannot be empty"
+		}
+	case formFieldDue:
+		if value := strings.TrimSpace(f.due.Value()); value != "" {
+			if _, err := timeutil.ParseDate(value, now); err != nil {
+				f.errs[field] = fmt.Sprintf("invalid due date %q", value)
+			}
+		}
+	}
+}
+
+// validate checks every field and returns the due date
+// On failure the first invalid field is focused.
+func (f *todoForm) validate(now time.Time) (*time.Time, error) {
+	for field := 0; field < formFieldCount; field++ {
+		f.validateField(field, now)
+	}
+	for field, msg := range f.errs {
+		if msg != "" {
+			f.setFocus(field)
+			return nil, errors.New(msg)
+		}
+	}
+
+	value := strings.TrimSpace(f.due.Value())
+	if value == "" {
+		return nil, nil
+	}
+	due, err := timeutil.ParseDate(value, now)
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
+// cyclePriority moves the priority choice by delta, wrapping around
+func (f *todoForm) cyclePriority(delta int) {
+	for i, p := range formPriorities {
+		if p.priority == f.priority {
+			f.priority = formPriorities[(i+delta+len(formPriorities))%len(formPriorities)].priority
+			return
+		}
+	}
+	f.priority = model.PriorityMedium
+}
+
+// openEditForm shows the edit form of the todo
+func (m *Model) openEditForm(todo *model.Todo) {
+	m.viewMode = ViewModeEditTodo
+	m.editForm = newTodoForm(todo, calculateDynamicWidths(m.width).EditInput)
+	m.message = ""
+	m.err = nil
+}
+
+// closeEditForm returns from the edit form to the list view
+func (m *Model) closeEditForm(message string) {
+	m.viewMode = ViewModeList
+	m.input.SetValue("")
+	m.message = message
+	m.err = nil
+}
+
+// handleEditFormKey handles a key in the edit form
+// Tab and Shift+Tab move between the fields, Ctrl+S saves and Esc discards
+// the changes (after a confirmation if there are any).
+func (m *Model) handleEditFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := &m.editForm
+
+	if msg.String() == "ctrl+c" {
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	if f.confirmDiscard {
+		f.confirmDiscard = false
+		if msg.String() == "y" || msg.String() == "Y" {
+			m.closeEditForm("Edit cancelled")
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		if f.dirty() {
+			f.confirmDiscard = true
+			return m, nil
+		}
+		m.closeEditForm("Edit cancelled")
+		return m, nil
+
+	case "ctrl+s":
+		return m.saveEditForm()
+
+	case "tab":
+		f.setFocus((f.focus + 1) % formFieldCount)
+		return m, nil
+
+	case "shift+tab":
+		f.setFocus((f.focus + formFieldCount - 1) % formFieldCount)
+		return m, nil
+
+	case "enter":
+		// Enter starts a new line in the description and moves on from the other fields
+		if f.focus == formFieldDue {
+			return m.saveEditForm()
+		}
+		if f.focus != formFieldDescription {
+			f.setFocus(f.focus + 1)
+			return m, nil
+		}
+
+	case "ctrl+e":
+		if f.focus == formFieldDescription {
+			m.err = nil
+			return m, openEditor(f.description.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.focus {
+	case formFieldTitle:
+		f.title, cmd = f.title.Update(msg)
+	case formFieldDescription:
+		f.description, cmd = f.description.Update(msg)
+	case formFieldPriority:
+		switch msg.String() {
+		case "left":
+			f.cyclePriority(-1)
+		case "right", " ":
+			f.cyclePriority(1)
+		default:
+			// 1-3 or l/m/h pick a priority directly
+			if priority, err := parsePriority(msg.String()); err == nil {
+				f.priority = priority
+			}
+		}
+	case formFieldDue:
+		f.due, cmd = f.due.Update(msg)
+	}
+	// Editing a field clears its error until it is validated again
+	f.errs[f.focus] = ""
+	return m, cmd
+}
+
+// saveEditForm validates the edit form and saves the todo
+func (m *Model) saveEditForm() (tea.Model, tea.Cmd) {
+	f := &m.editForm
+	dueDate, err := f.validate(time.Now())
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if !f.dirty() {
+		m.closeEditForm("No changes to save")
+		return m, nil
+	}
+
+	values := f.values()
+	err = m.service.EditTodo(tuiContext(), f.todoID, values.title, values.description, values.priority, dueDate)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.closeEditForm("Todo updated successfully")
+	return m, m.reloadTodos()
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+// newFormTestModel returns a model showing the edit form of the todo
+func newFormTestModel(svc *service.TodoService, todo *model.Todo) (*Model, func(keys ...tea.KeyMsg)) {
+	m := &Model{service: svc, todos: []*model.Todo{todo}}
+	m.openEditForm(todo)
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			m.handleEditFormKey(key)
+		}
+	}
+	return m, press
+}
+
+func TestTodoForm_Navigation(t *testing.T) {
+	due := time.Date(2025, 12, 31, 17, 0, 0, 0, time.Local)
+	m, press := newFormTestModel(nil, &model.Todo{ID: 3, Title: "Ship", Priority: model.PriorityLow, DueDate: &due})
+	f := &m.editForm
+
+	if f.focus != formFieldTitle || f.due.Value() != "2025-12-31 17:00" {
+		t.Fatalf("expected the title focused and the due date filled in, got focus %d and due %q", f.focus, f.due.Value())
+	}
+
+	// Tab and Shift+Tab move between the fields, wrapping around
+	press(tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	if f.focus != formFieldPriority {
+		t.Errorf("expected the priority field after two tabs, got %d", f.focus)
+	}
+	press(tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if f.focus != formFieldDue {
+		t.Errorf("expected Shift+Tab to wrap to the due date field, got %d", f.focus)
+	}
+
+	// Choosing a priority marks the form as modified
+	if f.dirty() {
+		t.Error("expected an unchanged form not to be dirty")
+	}
+	press(tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyRight})
+	if f.priority != model.PriorityMedium || !f.dirty() {
+		t.Errorf("expected medium priority and a dirty form, got %v (dirty=%v)", f.priority, f.dirty())
+	}
+
+	// Esc asks before discarding the changes
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if !f.confirmDiscard || m.viewMode != ViewModeEditTodo {
+		t.Fatal("expected Esc to ask for confirmation")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if f.confirmDiscard || m.viewMode != ViewModeEditTodo {
+		t.Fatal("expected n to keep editing")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.viewMode != ViewModeList || m.message != "Edit cancelled" {
+		t.Errorf("expected y to discard the changes, got view %v and message %q", m.viewMode, m.message)
+	}
+}
+
+func TestTodoForm_Validation(t *testing.T) {
+	m, press := newFormTestModel(nil, &model.Todo{ID: 3, Title: "Ship", Priority: model.PriorityMedium})
+	f := &m.editForm
+
+	// An invalid due date is reported when leaving the field
+	press(tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("someday")}, tea.KeyMsg{Type: tea.KeyTab})
+	if f.errs[formFieldDue] == "" {
+		t.Error("expected an error for the due date")
+	}
+
+	// Saving with an empty title focuses the title and keeps the form open
+	press(tea.KeyMsg{Type: tea.KeyCtrlU}, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.err == nil || f.focus != formFieldTitle || m.viewMode != ViewModeEditTodo {
+		t.Errorf("expected a title error with the title focused, got err %v and focus %d", m.err, f.focus)
+	}
+	if f.errs[formFieldTitle] == "" || f.errs[formFieldDue] == "" {
+		t.Errorf("expected both fields to be marked invalid, got %q", f.errs)
+	}
+}
+
+func TestTodoForm_Save(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+
+	ctx := context.Background()
+	due := time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local)
+	todo, err := svc.AddTodo(ctx, "Ship", "", model.PriorityLow, &due)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	// Changing only the description keeps the due date
+	m, press := newFormTestModel(svc, todo)
+	press(tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("line one")},
+		tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("line two")},
+		tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.viewMode != ViewModeList || m.err != nil {
+		t.Fatalf("expected the form to close after saving, got view %v and error %v", m.viewMode, m.err)
+	}
+
+	saved, err := repo.GetByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if saved.Description != "line one\nline two" {
+		t.Errorf("expected the multi-line description, got %q", saved.Description)
+	}
+	if saved.DueDate == nil || !saved.DueDate.Equal(due) {
+		t.Errorf("expected the due date %v to be kept, got %v", due, saved.DueDate)
+	}
+}
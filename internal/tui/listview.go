@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+// listPageSize is the number of todos the list view loads at a time
+const listPageSize = 100
+
+// maxTodoSearchResults limits the todos looked up for completion and the command palette
+const maxTodoSearchResults = 200
+
+// minListRows is the number of todo rows shown even when the terminal is too short for them
+const minListRows = 3
+
+// reloadTodos loads the list anew, as many todos as are loaded now
+// Keeping the number of loaded todos keeps the cursor and the scroll position.
+func (m Model) reloadTodos() tea.Cmd {
+	return loadTodos(m.service, 0, max(len(m.todos), listPageSize))
+}
+
+// loadMoreTodos loads the next page of todos once the cursor is within a screen of the last loaded todo
+func (m *Model) loadMoreTodos() tea.Cmd {
+	if m.loadingMore || len(m.todos) >= m.totalTodos || m.cursor+m.listRows() < len(m.todos) {
+		return nil
+	}
+	m.loadingMore = true
+	return loadTodos(m.service, len(m.todos), listPageSize)
+}
+
+// jumpToLastTodo moves the cursor to the last todo, loading the rest of the list first
+func (m *Model) jumpToLastTodo() tea.Cmd {
+	if len(m.todos) < m.totalTodos {
+		m.jumpToEnd = true
+		if m.loadingMore {
+			return nil // Jumps once the page being loaded arrives
+		}
+		m.loadingMore = true
+		return loadTodos(m.service, len(m.todos), m.totalTodos-len(m.todos))
+	}
+	m.jumpToEnd = false
+	m.cursor = max(len(m.todos)-1, 0)
+	m.scrollList()
+	return nil
+}
+
+// handleTodosPage adds a page of todos loaded after the first one to the list
+func (m *Model) handleTodosPage(msg todosLoadedMsg) tea.Cmd {
+	m.loadingMore = false
+	if msg.err != nil {
+		m.err = msg.err
+		m.jumpToEnd = false
+		return nil
+	}
+	// A page loaded before the list was reloaded may no longer follow the loaded todos
+	if msg.offset == len(m.todos) {
+		m.todos = append(m.todos, msg.todos...)
+	}
+	m.totalTodos = max(msg.total, len(m.todos))
+	m.deferred = msg.deferred
+
+	if m.jumpToEnd {
+		return m.jumpToLastTodo()
+	}
+	return m.loadMoreTodos()
+}
+
+// handleListPageKey moves the cursor a page up or down (PgUp/PgDn) or to the
+// first or last todo (g/G), loading more todos when needed
+func (m *Model) handleListPageKey(key string) tea.Cmd {
+	switch key {
+	case "pgup":
+		m.cursor = max(m.cursor-m.listRows(), 0)
+	case "pgdown":
+		m.cursor = max(min(m.cursor+m.listRows(), len(m.todos)-1), 0)
+	case "g":
+		m.jumpToEnd = false
+		m.cursor = 0
+	case "G":
+		return m.jumpToLastTodo()
+	}
+	m.scrollList()
+	return m.loadMoreTodos()
+}
+
+// scrollList scrolls the list view so that the cursor is visible
+func (m *Model) scrollList() {
+	m.listOffset, _ = listWindow(m.listOffset, m.cursor, m.listRows(), len(m.todos))
+}
+
+// listRows returns the number of todo rows that fit in the list view
+// All loaded todos fit while the terminal size is unknown.
+func (m Model) listRows() int {
+	if m.height <= 0 {
+		return len(m.todos)
+	}
+	used := screenLines(strings.TrimSuffix(m.renderListTop(), "\n"), m.width) +
+		screenLines(m.renderListBottom(), m.width) +
+		1 // Position line
+	if m.inlineEdit != nil {
+		used++
+	}
+	return max(m.height-used, minListRows)
+}
+
+// listWindow returns the range [start, end) of the todos shown in rows lines
+// The window starts at offset unless that hides the cursor.
+func listWindow(offset, cursor, rows, count int) (int, int) {
+	if rows >= count {
+		return 0, count
+	}
+	start := offset
+	if cursor < start {
+		start = cursor
+	}
+	if cursor >= start+rows {
+		start = cursor - rows + 1
+	}
+	start = max(min(start, count-rows), 0)
+	return start, start + rows
+}
+
+// screenLines returns the number of terminal lines the text takes up, counting wrapped lines
+func screenLines(text string, width int) int {
+	lines := 0
+	for _, line := range strings.Split(text, "\n") {
+		w := lipgloss.Width(line)
+		if width <= 0 || w <= width {
+			lines++
+			continue
+		}
+		lines += (w + width - 1) / width
+	}
+	return lines
+}
+
+// todoSearch holds the todos matching a query, looked up in the repository
+type todoSearch struct {
+	query string
+	done  bool // Whether todos holds the matches of query
+	todos []*model.Todo
+}
+
+// refreshTodoSearches looks up the todos matching the todo argument being typed and the palette query
+// Completion, input hints and the palette cover every todo, not only the loaded pages.
+// A search is repeated only when its query changes or the todos were reloaded.
+func (m *Model) refreshTodoSearches() {
+	if m.service == nil {
+		return
+	}
+
+	if _, partial, ok := todoArgument(m.input.Value()); ok && partial != "" && partial != "." {
+		m.searchTodos(&m.inputMatches, partial, []string{partial}, false)
+	} else {
+		m.inputMatches = todoSearch{}
+	}
+
+	if m.viewMode == ViewModePalette {
+		query := m.paletteInput.Value()
+		m.searchTodos(&m.paletteMatches, query, strings.Fields(query), true)
+	}
+}
+
+// searchTodos fills the search with the todos matching the query, unless it already holds them
+func (m *Model) searchTodos(search *todoSearch, query string, terms []string, fuzzy bool) {
+	if search.done && search.query == query {
+		return
+	}
+	todos, err := m.service.SearchTodos(tuiContext(), terms, fuzzy, maxTodoSearchResults)
+	if err != nil {
+		m.err = err
+	}
+	*search = todoSearch{query: query, done: true, todos: todos}
+}
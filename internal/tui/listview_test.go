@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/config"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+func TestListWindow(t *testing.T) {
+	tests := []struct {
+		name                        string
+		offset, cursor, rows, count int
+		wantStart, wantEnd          int
+	}{
+		{"everything fits", 5, 3, 10, 8, 0, 8},
+		{"cursor inside the window", 10, 15, 10, 100, 10, 20},
+		{"cursor above the window", 10, 4, 10, 100, 4, 14},
+		{"cursor below the window", 10, 25, 10, 100, 16, 26},
+		{"window past the end", 95, 99, 10, 100, 90, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := listWindow(tt.offset, tt.cursor, tt.rows, tt.count)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("listWindow(%d, %d, %d, %d) = [%d, %d), want [%d, %d)",
+					tt.offset, tt.cursor, tt.rows, tt.count, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestListPaging(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+
+	const count = 250
+	for i := 1; i <= count; i++ {
+		if _, err := svc.AddTodo(context.Background(), fmt.Sprintf("Todo %d", i), "", model.PriorityMedium, nil); err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+	}
+
+	m := Model{service: svc, config: &config.Config{}, viewMode: ViewModeList, input: textinput.New(), width: 120, height: 30}
+	// send updates the model with the message and the messages of the commands it returns
+	var send func(msg tea.Msg)
+	send = func(msg tea.Msg) {
+		updated, cmd := m.Update(msg)
+		switch next := updated.(type) {
+		case Model:
+			m = next
+		case *Model:
+			m = *next
+		}
+		if cmd == nil {
+			return
+		}
+		if next := cmd(); next != nil {
+			if _, ok := next.(todosLoadedMsg); ok {
+				send(next)
+			}
+		}
+	}
+
+	// Only the first page is loaded, and only a screenful is shown
+	send(loadTodos(svc, 0, listPageSize)())
+	if len(m.todos) != listPageSize || m.totalTodos != count {
+		t.Fatalf("expected %d of %d todos loaded, got %d of %d", listPageSize, count, len(m.todos), m.totalTodos)
+	}
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > m.height {
+		t.Errorf("expected the view to fit in %d lines, got %d", m.height, lines)
+	}
+	if !strings.Contains(view, "1/250") || strings.Contains(view, "Todo 1 ") {
+		t.Error("expected the position and only the newest todos to be shown")
+	}
+
+	// Moving down a page keeps the cursor on screen
+	rows := m.listRows()
+	send(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.cursor != rows || m.listOffset != 1 {
+		t.Errorf("expected the cursor at %d with the list scrolled by 1, got %d and %d", rows, m.cursor, m.listOffset)
+	}
+
+	// G loads the rest of the list and moves to the last todo
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if len(m.todos) != count || m.cursor != count-1 {
+		t.Fatalf("expected all todos loaded with the cursor on the last one, got %d and %d", len(m.todos), m.cursor)
+	}
+	if view := m.View(); !strings.Contains(view, "250/250") || !strings.Contains(view, "Todo 1 ") {
+		t.Error("expected the oldest todo and the position at the end of the list")
+	}
+
+	// Reloading after a change keeps every loaded todo, and g goes back to the top
+	send(m.reloadTodos()())
+	if len(m.todos) != count || m.cursor != count-1 {
+		t.Errorf("expected the reload to keep %d todos and the cursor, got %d and %d", count, len(m.todos), m.cursor)
+	}
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.cursor != 0 || m.listOffset != 0 {
+		t.Errorf("expected g to go to the first todo, got cursor %d and offset %d", m.cursor, m.listOffset)
+	}
+}
+
+func TestUnloadedTodos(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+	ctx := context.Background()
+
+	oldest, err := svc.AddTodo(ctx, "Renew passport", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	for i := 1; i <= listPageSize; i++ {
+		if _, err := svc.AddTodo(ctx, fmt.Sprintf("Todo %d", i), "", model.PriorityMedium, nil); err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+	}
+	deferred, err := svc.AddTodo(ctx, "Plan the retro", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	start := time.Now().Add(24 * time.Hour)
+	if err := svc.DeferTodo(ctx, deferred.ID, &start); err != nil {
+		t.Fatalf("DeferTodo failed: %v", err)
+	}
+
+	input := textinput.New()
+	input.Focus()
+	m := Model{service: svc, config: &config.Config{}, viewMode: ViewModeList, input: input, width: 120, height: 30}
+	send := func(msgs ...tea.Msg) {
+		for _, msg := range msgs {
+			updated, _ := m.Update(msg)
+			switch next := updated.(type) {
+			case Model:
+				m = next
+			case *Model:
+				m = *next
+			}
+		}
+	}
+	typed := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	send(loadTodos(svc, 0, listPageSize)())
+	for _, todo := range m.todos {
+		if todo.ID == oldest.ID || todo.ID == deferred.ID {
+			t.Fatalf("expected #%d to be left out of the loaded page", todo.ID)
+		}
+	}
+
+	// Tab completes titles of todos that are not loaded
+	send(typed("/done passp"), tea.KeyMsg{Type: tea.KeyTab})
+	if want := fmt.Sprintf("/done %d", oldest.ID); m.input.Value() != want {
+		t.Errorf("expected %q, got %q", want, m.input.Value())
+	}
+
+	// /edit and /pomo reach the deferred todo
+	lookupCommand("/edit").run(&m, []string{fmt.Sprint(deferred.ID)})
+	if m.viewMode != ViewModeEditTodo || m.editForm.todoID != deferred.ID {
+		t.Fatalf("expected the edit form of the deferred todo, got view %v and error %v", m.viewMode, m.err)
+	}
+	m.viewMode = ViewModeList
+	lookupCommand("/pomo").run(&m, []string{fmt.Sprint(deferred.ID)})
+	if m.viewMode != ViewModePomodoro || m.pomoTodoTitle != "Plan the retro" {
+		t.Fatalf("expected a Pomodoro for the deferred todo, got view %v and error %v", m.viewMode, m.err)
+	}
+	m.viewMode = ViewModeList
+
+	// The palette finds the todo and shows it in the detail view
+	send(tea.KeyMsg{Type: tea.KeyCtrlP}, typed("passport"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.viewMode != ViewModeDetail || m.detailTodo == nil || m.detailTodo.ID != oldest.ID {
+		t.Errorf("expected the detail view of #%d, got view %v", oldest.ID, m.viewMode)
+	}
+	if view := m.View(); !strings.Contains(view, "Renew passport") {
+		t.Error("expected the detail view to show the todo")
+	}
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+// detailDescriptionHeight is the number of description lines shown in the detail view
+const detailDescriptionHeight = 8
+
+var (
+	// markdownHeading matches a heading line: "## Title"
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+
+	// markdownListItem matches a list item: "- item", "* item", "+ item" or "1. item"
+	markdownListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+
+	// markdownCheckbox matches the checkbox at the start of a list item: "[ ] " or "[x] "
+	markdownCheckbox = regexp.MustCompile(`^\[([ xX])\]\s+`)
+
+	// markdownLink matches an inline link: "[text](url)"
+	markdownLink = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// renderMarkdown renders Markdown text as styled lines wrapped to width
+// Headings, bullet and numbered lists, checkboxes, fenced code blocks and the
+// inline **bold**, `code` and [text](url) spans are styled. Like Markdown,
+// consecutive text lines form one paragraph.
+func renderMarkdown(text string, width int) []string {
+	var lines []string
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			lines = append(lines, wrapMarkdown(renderInline(strings.Join(paragraph, " "), lipgloss.NewStyle()), width, "", "")...)
+			paragraph = nil
+		}
+	}
+	blank := func() {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+	}
+
+	source := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(source); i++ {
+		line := source[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			// Fenced code block, up to the closing fence or the end of the text
+			flush()
+			blank()
+			for i++; i < len(source) && !strings.HasPrefix(strings.TrimSpace(source[i]), "```"); i++ {
+				lines = append(lines, strings.Split(mdCodeStyle.Width(width).Render(source[i]), "\n")...)
+			}
+			blank()
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			blank()
+			continue
+		}
+
+		if match := markdownHeading.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			blank()
+			style := headerStyle
+			if len(match[1]) > 2 {
+				style = mdSubheadingStyle
+			}
+			lines = append(lines, wrapMarkdown(style.Render(match[2]), width, "", "")...)
+			continue
+		}
+
+		if match := markdownListItem.FindStringSubmatch(line); match != nil {
+			flush()
+			lines = append(lines, renderListItem(match[1], match[2], match[3], width)...)
+			continue
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+	flush()
+
+	// Drop the blank line left after a trailing block
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// renderListItem renders a list item with its marker, indented by its nesting level
+func renderListItem(indent, marker, text string, width int) []string {
+	depth := len(strings.ReplaceAll(indent, "\t", "    ")) / 2
+	prefix := strings.Repeat("  ", depth)
+
+	style := lipgloss.NewStyle()
+	switch {
+	case markdownCheckbox.MatchString(text):
+		if strings.TrimSpace(markdownCheckbox.FindStringSubmatch(text)[1]) == "" {
+			marker = "☐"
+		} else {
+			marker = "☑"
+			style = completedItemStyle
+		}
+		text = markdownCheckbox.ReplaceAllString(text, "")
+	case marker == "-" || marker == "*" || marker == "+":
+		marker = "•"
+	}
+
+	first := prefix + mdBulletStyle.Render(marker) + " "
+	rest := prefix + strings.Repeat(" ", lipgloss.Width(marker)+1)
+	return wrapMarkdown(renderInline(text, style), width, first, rest)
+}
+
+// renderInline styles the inline spans of text, rendering the rest with base
+func renderInline(text string, base lipgloss.Style) string {
+	var b strings.Builder
+	for text != "" {
+		i := strings.IndexAny(text, "`*[")
+		if i < 0 {
+			b.WriteString(base.Render(text))
+			break
+		}
+
+		span, n := inlineSpan(text[i:], base)
+		if n == 0 {
+			// Not the start of a span, keep the character as text
+			b.WriteString(base.Render(text[:i+1]))
+			text = text[i+1:]
+			continue
+		}
+		if i > 0 {
+			b.WriteString(base.Render(text[:i]))
+		}
+		b.WriteString(span)
+		text = text[i+n:]
+	}
+	return b.String()
+}
+
+// inlineSpan renders the span at the start of text and returns its length in bytes
+// The length is 0 if text does not start with a complete span.
+func inlineSpan(text string, base lipgloss.Style) (string, int) {
+	switch {
+	case strings.HasPrefix(text, "`"):
+		if end := strings.Index(text[1:], "`"); end > 0 {
+			return mdCodeStyle.Render(text[1 : end+1]), end + 2
+		}
+
+	case strings.HasPrefix(text, "**"):
+		if end := strings.Index(text[2:], "**"); end > 0 {
+			return base.Bold(true).Render(text[2 : end+2]), end + 4
+		}
+
+	case strings.HasPrefix(text, "["):
+		if match := markdownLink.FindStringSubmatch(text); match != nil {
+			label, url := match[1], match[2]
+			if label == url {
+				return mdLinkStyle.Render(url), len(match[0])
+			}
+			return mdLinkStyle.Render(label) + mdLinkURLStyle.Render(" ("+url+")"), len(match[0])
+		}
+	}
+	return "", 0
+}
+
+// wrapMarkdown wraps styled text to width, starting the first line with first
+// and the following lines with rest
+func wrapMarkdown(text string, width int, first, rest string) []string {
+	textWidth := width - lipgloss.Width(first)
+	if textWidth < 10 {
+		textWidth = 10
+	}
+
+	wrapped := strings.Split(lipgloss.NewStyle().Width(textWidth).Render(text), "\n")
+	for i, line := range wrapped {
+		// Width pads every line, only the wrapping is wanted here
+		line = strings.TrimRight(line, " ")
+		if i == 0 {
+			wrapped[i] = first + line
+		} else {
+			wrapped[i] = rest + line
+		}
+	}
+	return wrapped
+}
+
+// detailDescriptionLines returns the description of the todo rendered for the detail view
+func detailDescriptionLines(todo *model.Todo, widths DynamicWidths) []string {
+	// The description box has 2 columns of padding on each side
+	return renderMarkdown(todo.Description, widths.DetailBox-4)
+}
+
+// clampDetailScroll limits the scroll offset so the last description line stays at the bottom
+func clampDetailScroll(scroll, lines int) int {
+	return max(0, min(scroll, lines-detailDescriptionHeight))
+}
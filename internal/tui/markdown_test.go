@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	text := strings.Join([]string{
+		"## Release plan",
+		"Ship the **new** build",
+		"after `make test` passes.",
+		"",
+		"- [x] Write notes",
+		"- [ ] Tag the release",
+		"  * See [the guide](https://example.com/guide)",
+		"1. Announce",
+		"",
+		"```",
+		"go build ./...",
+		"```",
+	}, "\n")
+
+	want := []string{
+		"Release plan",
+		"Ship the new build after make test passes.",
+		"",
+		"☑ Write notes",
+		"☐ Tag the release",
+		"  • See the guide (https://example.com/guide)",
+		"1. Announce",
+		"",
+		"go build ./...",
+	}
+	got := renderMarkdown(text, 80)
+	for i := range got {
+		got[i] = strings.TrimRight(got[i], " ")
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("renderMarkdown() =\n%q\nexpected\n%q", got, want)
+	}
+}
+
+func TestRenderMarkdown_Wrapping(t *testing.T) {
+	lines := renderMarkdown("- "+strings.Repeat("word ", 12), 30)
+	if len(lines) < 2 {
+		t.Fatalf("expected the list item to wrap, got %q", lines)
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("line %q is %d columns wide; expected at most 30", line, w)
+		}
+	}
+	// Wrapped lines are indented under the item text
+	if !strings.HasPrefix(lines[0], "• word") || !strings.HasPrefix(lines[1], "  word") {
+		t.Errorf("expected a hanging indent, got %q", lines)
+	}
+}
+
+func TestRenderInline_Unclosed(t *testing.T) {
+	text := "2 * 3 = 6, a `stray tick and [not a link]"
+	if got := renderInline(text, lipgloss.NewStyle()); got != text {
+		t.Errorf("expected unclosed spans to stay as text, got %q", got)
+	}
+}
+
+func TestClampDetailScroll(t *testing.T) {
+	tests := []struct {
+		scroll, lines, want int
+	}{
+		{0, 3, 0},
+		{5, 3, 0},
+		{5, 20, 5},
+		{15, 20, 20 - detailDescriptionHeight},
+		{-1, 20, 0},
+	}
+	for _, tt := range tests {
+		if got := clampDetailScroll(tt.scroll, tt.lines); got != tt.want {
+			t.Errorf("clampDetailScroll(%d, %d) = %d; expected %d", tt.scroll, tt.lines, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package tui
 
 import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/config"
+	"github.com/syeeel/koto-cli-go/internal/history"
 	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/notify"
 	"github.com/syeeel/koto-cli-go/internal/service"
 )
 
@@ -30,13 +36,47 @@ const (
 	ViewModeExport
 	// ViewModeImport shows the import screen
 	ViewModeImport
+	// ViewModeWorkLog shows the work log (time entries) of a todo
+	ViewModeWorkLog
+	// ViewModeReport shows the timesheet report
+	ViewModeReport
+	// ViewModeStats shows the productivity statistics dashboard
+	ViewModeStats
+	// ViewModeTrash shows the deleted todos
+	ViewModeTrash
+	// ViewModeArchive shows the archived todos with a search box
+	ViewModeArchive
+	// ViewModePalette shows the command palette over the list
+	ViewModePalette
+)
+
+// Work log view input modes
+const (
+	workLogInputNone = iota // Browsing entries
+	workLogInputEdit        // Editing the minutes of the focused entry
+	workLogInputAdd         // Adding a new entry
 )
 
 // Model represents the Bubbletea model for the TUI
 type Model struct {
 	service  *service.TodoService
-	todos    []*model.Todo
+	config   *config.Config
+	notifier notify.Notifier
+	todos    []*model.Todo // Loaded todos: the first pages of the list
+	deferred int           // Number of deferred todos hidden from todos
 	cursor   int
+
+	// Paging of the list view
+	totalTodos  int  // Number of todos in the list, loaded or not
+	listOffset  int  // Index of the first todo shown in the list view
+	loadingMore bool // Whether the next page of todos is being loaded
+	jumpToEnd   bool // Whether to move the cursor to the last todo once the list is loaded
+
+	// Todos matching the todo argument being typed and the palette query, searched in
+	// the repository since the loaded pages leave out deferred and further todos
+	inputMatches   todoSearch
+	paletteMatches todoSearch
+
 	viewMode ViewMode
 	input    textinput.Model
 	viewport viewport.Model
@@ -53,20 +93,84 @@ type Model struct {
 	addTodoStep        int // 0: title, 1: description, 2: priority
 
 	// Edit todo screen state
-	editTodoID          int64
-	editTodoTitle       string
-	editTodoDescription string
-	editTodoPriority    model.Priority
-	editTodoStep        int // 0: title, 1: description, 2: priority
+	editForm todoForm
+
+	// Field of a todo edited in place in the list view (nil if none)
+	inlineEdit *inlineEdit
+
+	// Operation waiting for confirmation (nil if none)
+	confirm *confirmation
+
+	// Multi-line description input of the add view
+	descInput textarea.Model
 
 	// Pomodoro timer state
-	pomoTodoID      int64 // ID of todo being worked on (0 if general timer)
-	pomoSecondsLeft int   // Remaining time in seconds (25 minutes = 1500 seconds)
-	pomoRunning     bool  // Whether timer is currently running
-	pomoCompleted   bool  // Whether timer has completed and is in alert mode
+	pomoTodoID      int64  // ID of todo being worked on (0 if general timer)
+	pomoTodoTitle   string // Title of the todo being worked on
+	pomoSecondsLeft int    // Remaining time in seconds (25 minutes = 1500 seconds)
+	pomoRunning     bool   // Whether timer is currently running
+	pomoCompleted   bool   // Whether timer has completed and is in alert mode
+	pomoAlertCount  int    // Number of alert sounds played since completion
+	pomoSnoozed     bool   // Whether the completion alert is snoozed
+
+	// Stopwatch state
+	stopwatch        *model.Stopwatch // Running stopwatch (nil if none)
+	stopwatchTitle   string           // Title of the todo the stopwatch runs for
+	stopwatchTicking bool             // Whether the once-per-second refresh is scheduled
+
+	// Selection state (list view)
+	selected     map[int64]bool // IDs of the selected todos
+	selectAnchor int64          // Todo last toggled with space, where a V range starts (0 if none)
 
 	// Detail view state
-	detailTodoID int64 // ID of todo being displayed in detail view
+	detailTodoID    int64              // ID of todo being displayed in detail view
+	detailTodo      *model.Todo        // Todo being displayed (nil if it no longer exists)
+	detailReminders []*model.Reminder  // Active reminders of the displayed todo
+	detailEvents    []*model.TodoEvent // Audit history of the displayed todo (oldest first)
+	showHistory     bool               // Whether the history timeline is shown
+	detailScroll    int                // First description line shown
+
+	// Reminder state
+	activeReminders []*model.Reminder // Reminders that are due and not dismissed (oldest first)
+
+	// Work log view state
+	workLogTodoID    int64            // ID of todo whose work log is displayed
+	workLogs         []*model.WorkLog // Entries of the todo (newest first)
+	workLogCursor    int              // Index of the focused entry
+	workLogInputMode int              // One of the workLogInput* modes
+
+	// Report view state
+	reportFrom time.Time        // First day of the reported range
+	reportTo   time.Time        // Day after the last reported day
+	timesheet  *model.Timesheet // Loaded timesheet (nil while loading)
+
+	// Daily goal state
+	goalProgress      *model.GoalProgress // Today's progress (nil while loading)
+	daySummaryShownOn string              // Day ("2006-01-02") the end-of-day summary was last shown
+	showDaySummary    bool                // Whether the end-of-day summary is displayed
+	// Stats view state
+	stats *model.Stats // Loaded statistics (nil while loading)
+
+	// Trash view state
+	trash       []*model.Todo // Deleted todos (most recently deleted first)
+	trashCursor int           // Index of the focused todo
+
+	// Archive view state
+	archive       []*model.Todo // Archived todos matching the search (most recently archived first)
+	archiveQuery  string        // Search the archive was last loaded for
+	archiveCursor int           // Index of the focused todo
+
+	// Input history state
+	history         *history.History // Entered commands (nil if not loaded)
+	browsingHistory bool             // Whether up/down currently recall entries
+	historyIndex    int              // Index of the recalled entry while browsing
+	historyPrefix   string           // Input typed before browsing, which recalled entries start with
+	historySearch   *historySearch   // Reverse search in progress (nil if none)
+
+	// Command palette state
+	paletteInput  textinput.Model // Query typed in the palette
+	paletteCursor int             // Index of the focused result
+	paletteRecent []string        // Keys of recently picked items (most recent first)
 
 	// Export view state
 	exportFilePath string // Path for export file
@@ -82,8 +186,16 @@ type Model struct {
 	importCount    int    // Number of todos imported
 }
 
+// historySearch is a reverse search through the input history (Ctrl+R)
+type historySearch struct {
+	query    string // Text the entries are searched for
+	index    int    // Index of the match (history length if none)
+	match    string // Matching entry (empty if none)
+	original string // Input before the search, restored when it is cancelled
+}
+
 // NewModel creates a new TUI model
-func NewModel(service *service.TodoService) Model {
+func NewModel(service *service.TodoService, cfg *config.Config, notifier notify.Notifier, hist *history.History) Model {
 	ti := textinput.New()
 	ti.Placeholder = "Enter command (type /help for help)"
 	ti.Focus()
@@ -92,11 +204,14 @@ func NewModel(service *service.TodoService) Model {
 
 	return Model{
 		service:  service,
+		config:   cfg,
+		notifier: notifier,
 		todos:    []*model.Todo{},
 		cursor:   0,
 		viewMode: ViewModeBanner,
 		input:    ti,
 		quitting: false,
+		history:  hist,
 	}
 }
 
@@ -104,6 +219,10 @@ func NewModel(service *service.TodoService) Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		textinput.Blink,
-		loadTodos(m.service),
+		m.reloadTodos(),
+		loadStopwatch(m.service),
+		loadGoalProgress(m.service, m.config.DailyGoal),
+		loadActiveReminders(m.service),
+		clockTick(),
 	)
 }
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+// Palette limits
+const (
+	maxPaletteResults = 10 // Results shown in the palette
+	maxPaletteRecent  = 10 // Recently picked items boosted in the ranking
+)
+
+// paletteItem is a command or todo the command palette can pick
+type paletteItem struct {
+	command *command    // Picked command (nil for todos)
+	todo    *model.Todo // Picked todo (nil for commands)
+	score   int
+}
+
+// key identifies the item in the recently picked items
+func (i paletteItem) key() string {
+	if i.command != nil {
+		return i.command.name
+	}
+	return fmt.Sprintf("#%d", i.todo.ID)
+}
+
+// label returns the text shown for the item
+func (i paletteItem) label() string {
+	if i.command != nil {
+		return i.command.name + "  " + i.command.description
+	}
+	return fmt.Sprintf("#%d %s", i.todo.ID, i.todo.Title)
+}
+
+// paletteResults returns the commands and todos matching the query, best first
+// Every word of the query has to match. Recently picked items (most recent
+// first) rank higher; without a query they are listed before everything else.
+func paletteResults(query string, todos []*model.Todo, recent []string) []paletteItem {
+	var items []paletteItem
+	for _, cmd := range commandRegistry {
+		best, matched := 0, false
+		for _, name := range append([]string{cmd.name}, cmd.aliases...) {
+			if score, ok := fuzzyScore(query, strings.TrimPrefix(name, "/")); ok && (!matched || score > best) {
+				best, matched = score, true
+			}
+		}
+		if matched {
+			items = append(items, paletteItem{command: cmd, score: best})
+		}
+	}
+	for _, todo := range todos {
+		if score, ok := fuzzyScore(query, todo.Title); ok {
+			items = append(items, paletteItem{todo: todo, score: score})
+		}
+	}
+
+	for i := range items {
+		if rank := slices.Index(recent, items[i].key()); rank >= 0 {
+			items[i].score += (maxPaletteRecent - rank) * 10
+		}
+	}
+	slices.SortStableFunc(items, func(a, b paletteItem) int {
+		return b.score - a.score
+	})
+
+	if len(items) > maxPaletteResults {
+		items = items[:maxPaletteResults]
+	}
+	return items
+}
+
+// fuzzyScore scores how well the query matches the text (ok is false if it does not match)
+// Each word of the query must match characters of the text in order. Matches at
+// the start of a word and runs of consecutive characters score higher.
+func fuzzyScore(query, text string) (int, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return 0, true
+	}
+
+	target := []rune(strings.ToLower(text))
+	total := 0
+	for _, word := range words {
+		best, matched := 0, false
+		pattern := []rune(word)
+		for start := range target {
+			if target[start] != pattern[0] {
+				continue
+			}
+			if score, ok := matchFrom(pattern, target, start); ok && (!matched || score > best) {
+				best, matched = score, true
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+		total += best
+	}
+	// Prefer shorter texts among equally good matches
+	return total*10 - len(target), true
+}
+
+// matchFrom matches the pattern as a subsequence of the target starting at start
+func matchFrom(pattern, target []rune, start int) (int, bool) {
+	score, p, last := 0, 0, -1
+	for i := start; i < len(target) && p < len(pattern); i++ {
+		if target[i] != pattern[p] {
+			continue
+		}
+		score++
+		if i == 0 || !unicode.IsLetter(target[i-1]) && !unicode.IsDigit(target[i-1]) {
+			score += 3 // Start of a word
+		}
+		if p > 0 && last == i-1 {
+			score += 2 // Consecutive
+		}
+		last = i
+		p++
+	}
+	return score, p == len(pattern)
+}
+
+// rememberPaletteItem returns the recently picked items with key moved to the front
+func rememberPaletteItem(recent []string, key string) []string {
+	updated := []string{key}
+	for _, k := range recent {
+		if k != key && len(updated) < maxPaletteRecent {
+			updated = append(updated, k)
+		}
+	}
+	return updated
+}
+
+// openPalette shows the command palette with an empty query
+func (m *Model) openPalette() {
+	input := textinput.New()
+	input.Placeholder = "Search commands and todos..."
+	input.Prompt = "❯ "
+	input.CharLimit = 200
+	input.Width = 50
+	input.Focus()
+
+	m.viewMode = ViewModePalette
+	m.paletteInput = input
+	m.paletteCursor = 0
+	m.message = ""
+	m.err = nil
+}
+
+// pickPaletteItem closes the palette and acts on the picked item
+// Todos are focused in the list, or shown in the detail view if they are not loaded. Commands run right away unless they need
+// arguments, which are then typed in the input.
+func (m *Model) pickPaletteItem(item paletteItem) tea.Cmd {
+	m.viewMode = ViewModeList
+	m.paletteRecent = rememberPaletteItem(m.paletteRecent, item.key())
+
+	if item.todo != nil {
+		for i, todo := range m.todos {
+			if todo.ID == item.todo.ID {
+				m.cursor = i
+				m.scrollList()
+				return nil
+			}
+		}
+		// Deferred todos and todos beyond the loaded pages are shown in the detail view
+		return m.openDetailView(item.todo)
+	}
+
+	if strings.HasPrefix(item.command.args, "<") {
+		m.input.SetValue(item.command.name + " ")
+		m.input.CursorEnd()
+		return nil
+	}
+	return item.command.run(m, nil)
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query   string
+		text    string
+		matches bool
+	}{
+		{"rel not", "Write release notes", true},
+		{"not rel", "Write release notes", true},
+		{"wrn", "Write release notes", true},
+		{"REL", "Write release notes", true},
+		{"", "anything", true},
+		{"rel xyz", "Write release notes", false},
+		{"export", "exp", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.text); ok != tt.matches {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v; expected %v", tt.query, tt.text, ok, tt.matches)
+		}
+	}
+
+	// Word starts and consecutive characters rank higher than scattered matches
+	start, _ := fuzzyScore("rel", "Write release notes")
+	scattered, _ := fuzzyScore("rel", "Prepare a cool table")
+	if start <= scattered {
+		t.Errorf("expected word-start match (%d) to beat scattered match (%d)", start, scattered)
+	}
+}
+
+func TestPaletteResults(t *testing.T) {
+	todos := []*model.Todo{
+		{ID: 1, Title: "Prepare a cool table"},
+		{ID: 2, Title: "Write release notes"},
+		{ID: 3, Title: "Expose metrics"},
+	}
+
+	keys := func(items []paletteItem) []string {
+		result := make([]string, len(items))
+		for i, item := range items {
+			result[i] = item.key()
+		}
+		return result
+	}
+
+	results := paletteResults("rel not", todos, nil)
+	if len(results) == 0 || results[0].key() != "#2" {
+		t.Errorf("expected #2 first for \"rel not\", got %v", keys(results))
+	}
+
+	results = paletteResults("export", todos, nil)
+	if len(results) == 0 || results[0].key() != "/export" {
+		t.Errorf("expected /export first for \"export\", got %v", keys(results))
+	}
+
+	// Recently picked items rank higher
+	results = paletteResults("ex", todos, []string{"#3"})
+	if len(results) == 0 || results[0].key() != "#3" {
+		t.Errorf("expected recently picked #3 first, got %v", keys(results))
+	}
+
+	// Without a query the recent items come first and results are limited
+	results = paletteResults("", todos, []string{"/stats"})
+	if len(results) != maxPaletteResults || results[0].key() != "/stats" {
+		t.Errorf("expected %d results starting with /stats, got %v", maxPaletteResults, keys(results))
+	}
+}
+
+func TestRememberPaletteItem(t *testing.T) {
+	recent := rememberPaletteItem(nil, "/export")
+	recent = rememberPaletteItem(recent, "#2")
+	recent = rememberPaletteItem(recent, "/export")
+	if !slices.Equal(recent, []string{"/export", "#2"}) {
+		t.Errorf("expected [/export #2], got %v", recent)
+	}
+
+	for i := 0; i < 2*maxPaletteRecent; i++ {
+		recent = rememberPaletteItem(recent, string(rune('a'+i)))
+	}
+	if len(recent) != maxPaletteRecent {
+		t.Errorf("expected %d recent items, got %d", maxPaletteRecent, len(recent))
+	}
+}
+
+func TestPickPaletteItem(t *testing.T) {
+	m := Model{
+		viewMode: ViewModePalette,
+		todos:    []*model.Todo{{ID: 4, Title: "First"}, {ID: 7, Title: "Second"}},
+	}
+
+	m.pickPaletteItem(paletteItem{todo: m.todos[1]})
+	if m.viewMode != ViewModeList || m.cursor != 1 {
+		t.Errorf("expected the list with the cursor on #7, got view %v cursor %d", m.viewMode, m.cursor)
+	}
+
+	m.viewMode = ViewModePalette
+	m.pickPaletteItem(paletteItem{command: lookupCommand("/log")})
+	if got := m.input.Value(); got != "/log " {
+		t.Errorf("expected a command taking arguments to be typed in the input, got %q", got)
+	}
+	if !slices.Equal(m.paletteRecent, []string{"/log", "#7"}) {
+		t.Errorf("expected picked items to be remembered, got %v", m.paletteRecent)
+	}
+}
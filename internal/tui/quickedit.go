@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
+)
+
+// Fields edited in place in the list view
+const (
+	inlineEditTitle = iota
+	inlineEditDue
+)
+
+// inlineEdit is a field of a todo edited in place in the list view
+type inlineEdit struct {
+	todoID int64
+	field  int // inlineEditTitle or inlineEditDue
+	input  textinput.Model
+}
+
+// priorityName returns the display name of the priority
+func priorityName(priority model.Priority) string {
+	for _, p := range formPriorities {
+		if p.priority == priority {
+			return p.label
+		}
+	}
+	return "Unknown"
+}
+
+// quickEditCmd persists a quick edit of the list view and reports it with an undo hint
+func quickEditCmd(message string, edit func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		if err := edit(tuiContext()); err != nil {
+			return commandExecutedMsg{err: err}
+		}
+		return commandExecutedMsg{message: message + " (press u to undo)"}
+	}
+}
+
+// handleQuickEditKey handles the quick edit keys on the focused todo of the list view
+// + and - raise and lower the priority, c toggles completion, r renames the todo
+// in place and D sets its due date. Changes are saved right away and can be undone.
+func (m *Model) handleQuickEditKey(key string) tea.Cmd {
+	todo, svc := m.todos[m.cursor], m.service
+	m.message = ""
+	m.err = nil
+
+	switch key {
+	case "+", "-":
+		priority := todo.Priority + 1
+		if key == "-" {
+			priority = todo.Priority - 1
+		}
+		if priority < model.PriorityLow || priority > model.PriorityHigh {
+			m.message = fmt.Sprintf("#%d is already %s priority", todo.ID, priorityName(todo.Priority))
+			return nil
+		}
+		return quickEditCmd(fmt.Sprintf("Set priority of #%d to %s", todo.ID, priorityName(priority)), func(ctx context.Context) error {
+			return svc.SetPriority(ctx, todo.ID, priority)
+		})
+
+	case "c":
+		if todo.IsCompleted() {
+			return quickEditCmd(fmt.Sprintf("Reopened #%d %s", todo.ID, todo.Title), func(ctx context.Context) error {
+				return svc.ReopenTodo(ctx, todo.ID)
+			})
+		}
+		return quickEditCmd(fmt.Sprintf("Completed #%d %s", todo.ID, todo.Title), func(ctx context.Context) error {
+			return svc.CompleteTodo(ctx, todo.ID)
+		})
+
+	case "r":
+		m.startInlineEdit(todo, inlineEditTitle)
+	case "D":
+		m.startInlineEdit(todo, inlineEditDue)
+	}
+	return nil
+}
+
+// startInlineEdit opens an input for a field of the todo below its row
+func (m *Model) startInlineEdit(todo *model.Todo, field int) {
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 60
+	if field == inlineEditTitle {
+		input.Prompt = fmt.Sprintf("Rename #%d: ", todo.ID)
+		input.SetValue(todo.Title)
+	} else {
+		input.Prompt = fmt.Sprintf("Due date of #%d: ", todo.ID)
+		input.Placeholder = "e.g. fri, 2025-12-31 17:00, +3d (empty to clear)"
+		input.SetValue(formatFormDate(todo.DueDate))
+	}
+	input.CursorEnd()
+	input.Focus()
+
+	m.inlineEdit = &inlineEdit{todoID: todo.ID, field: field, input: input}
+}
+
+// handleInlineEditKey handles a key while a field is edited in place
+// Enter saves the field and Esc cancels the edit.
+func (m *Model) handleInlineEditKey(msg tea.KeyMsg) tea.Cmd {
+	edit := m.inlineEdit
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return tea.Quit
+
+	case "esc":
+		m.inlineEdit = nil
+		m.err = nil
+		return nil
+
+	case "enter":
+		cmd, err := m.saveInlineEdit(strings.TrimSpace(edit.input.Value()))
+		if err != nil {
+			// Keep the input open to fix the value
+			m.err = err
+			return nil
+		}
+		m.inlineEdit = nil
+		m.err = nil
+		return cmd
+	}
+
+	var cmd tea.Cmd
+	edit.input, cmd = edit.input.Update(msg)
+	return cmd
+}
+
+// saveInlineEdit validates the value of the field edited in place and returns the command saving it
+func (m *Model) saveInlineEdit(value string) (tea.Cmd, error) {
+	id, svc := m.inlineEdit.todoID, m.service
+
+	if m.inlineEdit.field == inlineEditTitle {
+		if value == "" {
+			return nil, errors.New("title cannot be empty")
+		}
+		return quickEditCmd(fmt.Sprintf("Renamed #%d to %q", id, value), func(ctx context.Context) error {
+			return svc.RenameTodo(ctx, id, value)
+		}), nil
+	}
+
+	if value == "" {
+		return quickEditCmd(fmt.Sprintf("Cleared the due date of #%d", id), func(ctx context.Context) error {
+			return svc.SetDueDate(ctx, id, nil)
+		}), nil
+	}
+	dueDate, err := timeutil.ParseDate(value, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid due date %q", value)
+	}
+	return quickEditCmd(fmt.Sprintf("Set due date of #%d to %s", id, dueDate.Format("Mon 2006-01-02 15:04")), func(ctx context.Context) error {
+		return svc.SetDueDate(ctx, id, &dueDate)
+	}), nil
+}
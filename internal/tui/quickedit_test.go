@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/repository"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+func TestQuickEdits(t *testing.T) {
+	repo, err := repository.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	svc := service.NewTodoService(repo)
+	ctx := context.Background()
+
+	todo, err := svc.AddTodo(ctx, "Ship", "", model.PriorityMedium, nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	input := textinput.New()
+	input.Focus()
+	m := Model{service: svc, viewMode: ViewModeList, input: input, todos: []*model.Todo{todo}}
+
+	// press sends the keys and runs the command of the last one, returning its message
+	press := func(keys ...tea.KeyMsg) tea.Msg {
+		var cmd tea.Cmd
+		for _, key := range keys {
+			var updated tea.Model
+			updated, cmd = m.Update(key)
+			m = updated.(Model)
+		}
+		if cmd == nil {
+			return nil
+		}
+		return cmd()
+	}
+	text := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	reload := func() *model.Todo {
+		t.Helper()
+		updated, err := repo.GetByID(ctx, todo.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		m.todos = []*model.Todo{updated}
+		return updated
+	}
+
+	// + raises the priority right away, with an undo hint
+	msg, ok := press(text("+")).(commandExecutedMsg)
+	if !ok || msg.err != nil || !strings.Contains(msg.message, "High") || !strings.Contains(msg.message, "undo") {
+		t.Fatalf("expected a priority change message, got %+v", msg)
+	}
+	if reload().Priority != model.PriorityHigh {
+		t.Error("expected the priority to be saved")
+	}
+	if press(text("+")) != nil || !strings.Contains(m.message, "already High") {
+		t.Errorf("expected no change past high priority, got message %q", m.message)
+	}
+
+	// r renames the todo in place
+	press(text("r"))
+	if m.inlineEdit == nil || m.inlineEdit.input.Value() != "Ship" {
+		t.Fatalf("expected an inline edit with the title, got %+v", m.inlineEdit)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlU}, text("Ship v2"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.inlineEdit != nil || reload().Title != "Ship v2" {
+		t.Errorf("expected the todo to be renamed, got %q", reload().Title)
+	}
+
+	// D keeps the input open for an invalid date, Esc cancels
+	press(text("D"), text("someday"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.inlineEdit == nil || m.err == nil {
+		t.Fatal("expected the due date input to stay open with an error")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.inlineEdit != nil || reload().DueDate != nil {
+		t.Error("expected Esc to cancel without a due date")
+	}
+
+	// c toggles completion
+	press(text("c"))
+	if !reload().IsCompleted() {
+		t.Error("expected c to complete the todo")
+	}
+	press(text("c"))
+	if !reload().IsPending() {
+		t.Error("expected c to reopen the todo")
+	}
+
+	// With text in the input the keys are typed as usual
+	press(text("/"), text("c"))
+	if m.input.Value() != "/c" {
+		t.Errorf("expected the keys to be typed, got %q", m.input.Value())
+	}
+}
@@ -0,0 +1,527 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/service"
+)
+
+// commandHandler runs a command with its arguments
+// It may change the model (e.g. switch to another view) and returns the command to run next.
+type commandHandler func(m *Model, args []string) tea.Cmd
+
+// helpRow is a row of the command table in the help view
+// Rows without usage are notes on the command above them.
+type helpRow struct {
+	usage   string
+	desc    string
+	example string
+}
+
+// command is a slash command in the command registry
+type command struct {
+	name        string   // Name including the slash, e.g. "/done"
+	aliases     []string // Other names accepted for the command
+	args        string   // Arguments shown in usage hints (empty if the command takes none)
+	idArg       bool     // Whether the first argument takes todo IDs (completed with Tab)
+	description string
+	example     string
+	help        []helpRow // Further help rows: variants and notes
+	group       string    // Commands of a group are listed together in the help view
+	run         commandHandler
+}
+
+// usage returns the usage of the command, e.g. "/done <ids>"
+func (c *command) usage() string {
+	if c.args == "" {
+		return c.name
+	}
+	return c.name + " " + c.args
+}
+
+// serviceCommand runs a command handler against the service in the background
+// The handler gets the ID of the focused todo for "." in ID lists.
+func serviceCommand(handle func(ctx context.Context, svc *service.TodoService, args []string, focused int64) commandExecutedMsg) commandHandler {
+	return func(m *Model, args []string) tea.Cmd {
+		svc, focused := m.service, m.focusedID()
+		return func() tea.Msg {
+			return handle(tuiContext(), svc, args, focused)
+		}
+	}
+}
+
+// commandRegistry lists the slash commands in the order of the help view
+var commandRegistry = []*command{
+	{
+		name:        "/add",
+		args:        "[title] [!priority] [@due:date] [#tag] [-- description]",
+		description: "Add a new todo (interactive without a title)",
+		example:     "/add",
+		help: []helpRow{
+			{"", "  → Step 1: Enter title", ""},
+			{"", "  → Step 2: Enter description (optional)", ""},
+			{"", "  → Step 3: Select priority (1-3)", ""},
+			{"", "  → With a title the todo is added right away", "/add Fix login bug !high @due:fri #auth -- Users get logged out"},
+		},
+		group: "add",
+		run:   (*Model).openAddView,
+	},
+	{
+		name:        "/list",
+		aliases:     []string{"/ls"},
+		args:        "[--status=pending|completed|all] [--deferred]",
+		description: "List todos",
+		example:     "/list --status=pending",
+		help: []helpRow{
+			{"", "  → --deferred lists the todos hidden until their start date", "/list --deferred"},
+		},
+		group: "list",
+		run: serviceCommand(func(ctx context.Context, svc *service.TodoService, args []string, _ int64) commandExecutedMsg {
+			return handleListCommand(ctx, svc, args)
+		}),
+	},
+	{
+		name:        "/done",
+		aliases:     []string{"/delete"},
+		args:        "<ids>",
+		idArg:       true,
+		description: "Delete todos",
+		example:     "/done 1",
+		help: []helpRow{
+			{"", "  → IDs: lists and ranges, . for the focused todo", "/done 3,5,9-12"},
+		},
+		group: "todo",
+		run: func(m *Model, args []string) tea.Cmd {
+			deleteTodos := serviceCommand(handleDoneCommand)
+			if len(args) != 1 {
+				return deleteTodos(m, args) // Reports the usage
+			}
+			ids, err := parseIDList(args[0], m.focusedID())
+			if err != nil {
+				return deleteTodos(m, args) // Reports the invalid IDs
+			}
+			return m.confirmOperation(confirmation{
+				kind:    confirmDelete,
+				title:   fmt.Sprintf("Delete %d todo(s)?", len(ids)),
+				details: m.confirmTodoDetails(ids),
+				run: func(m *Model) tea.Cmd {
+					return deleteTodos(m, args)
+				},
+			}, len(ids))
+		},
+	},
+	{
+		name:        "/edit",
+		args:        "<id>",
+		idArg:       true,
+		description: "Edit a todo in a form",
+		example:     "/edit 1",
+		help: []helpRow{
+			{"", "  → Title, description, priority and due date", ""},
+			{"", "  → Tab/Shift+Tab to move, Ctrl+S to save", ""},
+		},
+		group: "todo",
+		run:   (*Model).openEditView,
+	},
+	{
+		name:        "/pomo",
+		args:        "[id]",
+		idArg:       true,
+		description: "Start a 25-minute Pomodoro timer",
+		example:     "/pomo",
+		help: []helpRow{
+			{"", "  → General timer (no task)", "/pomo"},
+			{"", "  → Task-specific timer (records time)", "/pomo 1"},
+		},
+		group: "pomo",
+		run:   (*Model).startPomodoro,
+	},
+	{
+		name:        "/start",
+		args:        "<id>",
+		idArg:       true,
+		description: "Start a stopwatch for open-ended work",
+		example:     "/start 1",
+		help: []helpRow{
+			{"", "  → Starting another todo stops the previous one", ""},
+		},
+		group: "stopwatch",
+		run:   serviceCommand(handleStartCommand),
+	},
+	{
+		name:        "/stop",
+		description: "Stop the stopwatch and record the time",
+		example:     "/stop",
+		group:       "stopwatch",
+		run: serviceCommand(func(ctx context.Context, svc *service.TodoService, args []string, _ int64) commandExecutedMsg {
+			return handleStopCommand(ctx, svc, args)
+		}),
+	},
+	{
+		name:        "/log",
+		args:        "<ids> <duration> [note] [--at=date]",
+		idArg:       true,
+		description: "Log work time manually",
+		example:     "/log 1 1h30m Design review --at=yesterday",
+		help: []helpRow{
+			{"", "  → Negative durations correct mistakes", "/log 1 -15m"},
+			{"", "  → Press t in the detail view to adjust or delete entries", ""},
+		},
+		group: "log",
+		run:   serviceCommand(handleLogCommand),
+	},
+	{
+		name:        "/report",
+		args:        "[--week=this|last] [--from=<date> --to=<date>] [--csv=<filepath>]",
+		description: "Show the weekly timesheet",
+		example:     "/report --week=last",
+		help: []helpRow{
+			{"/report --from=<date> --to=<date>", "Timesheet for a custom date range", "/report --from=2025-01-01 --to=2025-01-31"},
+			{"/report --csv=<filepath>", "Also export the timesheet to CSV", "/report --csv=~/timesheet.csv"},
+		},
+		group: "insights",
+		run:   (*Model).openReportView,
+	},
+	{
+		name:        "/stats",
+		description: "Show productivity statistics",
+		example:     "/stats",
+		group:       "insights",
+		run:         (*Model).openStatsView,
+	},
+	{
+		name:        "/goal",
+		args:        "[pomodoros] [duration] | off",
+		description: "Show or set the daily focus goal",
+		example:     "/goal 8 4h",
+		help: []helpRow{
+			{"", "  → /goal off clears the goal", ""},
+		},
+		group: "insights",
+		run:   (*Model).setGoal,
+	},
+	{
+		name:        "/defer",
+		args:        "<ids> <when|clear>",
+		idArg:       true,
+		description: "Hide todos until they are relevant",
+		example:     "/defer 1 next month",
+		help: []helpRow{
+			{"", "  → /defer <ids> clear shows it again, /list --deferred lists hidden todos", ""},
+		},
+		group: "schedule",
+		run:   serviceCommand(handleDeferCommand),
+	},
+	{
+		name:        "/remind",
+		args:        "<ids> <when|clear>",
+		idArg:       true,
+		description: "Remind me about todos",
+		example:     "/remind 1 tomorrow 9:00",
+		help: []helpRow{
+			{"", "  → Due reminders: z +10m, Z +1h, n tomorrow, x dismiss", ""},
+			{"/remind <ids> clear", "Remove the reminders of todos", "/remind 1 clear"},
+		},
+		group: "schedule",
+		run:   serviceCommand(handleRemindCommand),
+	},
+	{
+		name:        "/trash",
+		description: "Show deleted todos to restore or purge them",
+		example:     "/trash",
+		group:       "history",
+		run:         (*Model).openTrashView,
+	},
+	{
+		name:        "/archive",
+		args:        "[ids]",
+		idArg:       true,
+		description: "Search archived todos and bring them back",
+		example:     "/archive",
+		help: []helpRow{
+			{"/archive <ids>", "Archive completed todos", "/archive 1"},
+		},
+		group: "history",
+		run:   (*Model).openArchive,
+	},
+	{
+		name:        "/bulk",
+		args:        "<operation>",
+		description: "Apply an operation to the selected todos",
+		example:     "/bulk priority high",
+		help: []helpRow{
+			{"", "  → complete, delete, priority <level>, due <date|clear>, export [filepath]", ""},
+			{"", "  → Select with Space, V (range from the last toggled todo), * (all)", ""},
+		},
+		group: "history",
+		run: func(m *Model, args []string) tea.Cmd {
+			ids := m.selectedIDs()
+			if len(ids) == 0 || len(args) == 0 || args[0] == "export" {
+				return bulkCmd(m.service, ids, args)
+			}
+			return m.confirmOperation(confirmation{
+				kind:    confirmBulk,
+				title:   fmt.Sprintf("Apply %q to %d todo(s)?", strings.Join(args, " "), len(ids)),
+				details: m.confirmTodoDetails(ids),
+				run: func(m *Model) tea.Cmd {
+					return bulkCmd(m.service, ids, args)
+				},
+			}, len(ids))
+		},
+	},
+	{
+		name:        "/undo",
+		description: "Undo the last change (or press u)",
+		example:     "/undo",
+		group:       "history",
+		run: func(m *Model, _ []string) tea.Cmd {
+			return undoCmd(m.service)
+		},
+	},
+	{
+		name:        "/redo",
+		description: "Redo the last undone change (or press Ctrl+R)",
+		example:     "/redo",
+		group:       "history",
+		run: func(m *Model, _ []string) tea.Cmd {
+			return redoCmd(m.service)
+		},
+	},
+	{
+		name:        "/export",
+		args:        "[filepath]",
+		description: "Export todos to JSON",
+		example:     "/export ~/todos.json",
+		group:       "data",
+		run:         (*Model).openExportView,
+	},
+	{
+		name:        "/import",
+		args:        "[filepath]",
+		description: "Import todos from JSON",
+		example:     "/import ~/todos.json",
+		group:       "data",
+		run:         (*Model).openImportView,
+	},
+	{
+		name:        "/help",
+		description: "Show this help screen (or press ?)",
+		example:     "/help",
+		group:       "app",
+		run: func(_ *Model, _ []string) tea.Cmd {
+			// The help view is opened in Update, its content is generated from this registry
+			return func() tea.Msg { return helpRequestedMsg{} }
+		},
+	},
+	{
+		name:        "/exit",
+		aliases:     []string{"/quit"},
+		description: "Quit the application",
+		example:     "/exit",
+		group:       "app",
+		run: func(m *Model, _ []string) tea.Cmd {
+			m.quitting = true
+			return tea.Quit
+		},
+	},
+}
+
+// lookupCommand returns the command with the given name or alias (nil if unknown)
+func lookupCommand(name string) *command {
+	for _, cmd := range commandRegistry {
+		if cmd.name == name || slices.Contains(cmd.aliases, name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// commandHelpRows returns the command table of the help view
+// Groups are separated by an empty row.
+func commandHelpRows() []helpRow {
+	var rows []helpRow
+	for i, cmd := range commandRegistry {
+		if i > 0 && cmd.group != commandRegistry[i-1].group {
+			rows = append(rows, helpRow{})
+		}
+		desc := cmd.description
+		if len(cmd.aliases) > 0 {
+			desc += " (also " + strings.Join(cmd.aliases, ", ") + ")"
+		}
+		rows = append(rows, helpRow{cmd.usage(), desc, cmd.example})
+		rows = append(rows, cmd.help...)
+	}
+	return rows
+}
+
+// commandNames returns the names of all commands in registry order
+func commandNames() []string {
+	names := make([]string, len(commandRegistry))
+	for i, cmd := range commandRegistry {
+		names[i] = cmd.name
+	}
+	return names
+}
+
+// matchingCommandNames returns the command names and aliases starting with prefix
+func matchingCommandNames(prefix string) []string {
+	var names []string
+	for _, cmd := range commandRegistry {
+		for _, name := range append([]string{cmd.name}, cmd.aliases...) {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// todoArgument returns the command and the todo ID being typed in the input
+// ok is false unless the input is at the ID argument of a command that takes one.
+// For ID lists the partial ID is the part after the last comma.
+func todoArgument(input string) (cmd *command, partial string, ok bool) {
+	name, rest, found := strings.Cut(input, " ")
+	if !found {
+		return nil, "", false
+	}
+	cmd = lookupCommand(name)
+	if cmd == nil || !cmd.idArg {
+		return nil, "", false
+	}
+	rest = strings.TrimLeft(rest, " ")
+	if strings.Contains(rest, " ") {
+		return nil, "", false // Past the ID argument
+	}
+	if i := strings.LastIndex(rest, ","); i >= 0 {
+		rest = rest[i+1:]
+	}
+	return cmd, rest, true
+}
+
+// matchingTodos returns the todos whose ID starts with partial or whose title contains it
+func matchingTodos(todos []*model.Todo, partial string) []*model.Todo {
+	partial = strings.ToLower(partial)
+	var matches []*model.Todo
+	for _, todo := range todos {
+		if strings.HasPrefix(strconv.FormatInt(todo.ID, 10), partial) ||
+			strings.Contains(strings.ToLower(todo.Title), partial) {
+			matches = append(matches, todo)
+		}
+	}
+	return matches
+}
+
+// completeInput completes the command name or todo ID at the end of the input
+// It returns the completed input and the candidates when the completion is ambiguous.
+// Todo IDs can be completed from a part of the title.
+func completeInput(input string, todos []*model.Todo) (string, []string) {
+	if !strings.HasPrefix(input, "/") {
+		return input, nil
+	}
+
+	if !strings.Contains(input, " ") {
+		names := matchingCommandNames(input)
+		switch len(names) {
+		case 0:
+			return input, nil
+		case 1:
+			if lookupCommand(names[0]).args != "" {
+				return names[0] + " ", nil
+			}
+			return names[0], nil
+		default:
+			return commonPrefix(names), names
+		}
+	}
+
+	_, partial, ok := todoArgument(input)
+	if !ok || partial == "" || partial == "." {
+		return input, nil
+	}
+	matches := matchingTodos(todos, partial)
+	switch len(matches) {
+	case 0:
+		return input, nil
+	case 1:
+		return strings.TrimSuffix(input, partial) + strconv.FormatInt(matches[0].ID, 10), nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, todo := range matches {
+			candidates[i] = fmt.Sprintf("#%d %s", todo.ID, todo.Title)
+		}
+		return input, candidates
+	}
+}
+
+// commonPrefix returns the longest common prefix of the strings
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// maxHintCandidates limits how many candidates an input hint lists
+const maxHintCandidates = 5
+
+// inputHint returns the usage hint for the command being typed (empty if none)
+// While typing the command name it lists the matching commands, afterwards it
+// shows the usage and, at a todo ID, the matching todos.
+func inputHint(input string, todos []*model.Todo) string {
+	if !strings.HasPrefix(input, "/") {
+		return ""
+	}
+
+	if !strings.Contains(input, " ") {
+		names := matchingCommandNames(input)
+		switch len(names) {
+		case 0:
+			return "Unknown command"
+		case 1:
+			cmd := lookupCommand(names[0])
+			return cmd.usage() + " — " + cmd.description
+		default:
+			return joinCandidates(names, "  ")
+		}
+	}
+
+	name, _, _ := strings.Cut(input, " ")
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		return "Unknown command"
+	}
+	hint := cmd.usage() + " — " + cmd.description
+
+	if _, partial, ok := todoArgument(input); ok && partial != "" && partial != "." {
+		if _, err := strconv.ParseInt(partial, 10, 64); err != nil {
+			matches := matchingTodos(todos, partial)
+			candidates := make([]string, len(matches))
+			for i, todo := range matches {
+				candidates[i] = fmt.Sprintf("#%d %s", todo.ID, todo.Title)
+			}
+			if len(candidates) == 0 {
+				hint += "\nNo todo matches " + strconv.Quote(partial)
+			} else {
+				hint += "\nTab: " + joinCandidates(candidates, " · ")
+			}
+		}
+	}
+	return hint
+}
+
+// joinCandidates joins the first candidates of a hint, noting how many are left out
+func joinCandidates(candidates []string, sep string) string {
+	if len(candidates) <= maxHintCandidates {
+		return strings.Join(candidates, sep)
+	}
+	return fmt.Sprintf("%s%s… (%d more)", strings.Join(candidates[:maxHintCandidates], sep), sep, len(candidates)-maxHintCandidates)
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/syeeel/koto-cli-go/internal/model"
+)
+
+func TestCommandRegistry(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, cmd := range commandRegistry {
+		for _, name := range append([]string{cmd.name}, cmd.aliases...) {
+			if !strings.HasPrefix(name, "/") {
+				t.Errorf("command name %q does not start with /", name)
+			}
+			if seen[name] {
+				t.Errorf("command name %q is registered twice", name)
+			}
+			seen[name] = true
+		}
+		if cmd.run == nil {
+			t.Errorf("command %s has no handler", cmd.name)
+		}
+		if cmd.description == "" {
+			t.Errorf("command %s has no description", cmd.name)
+		}
+	}
+
+	if cmd := lookupCommand("/delete"); cmd == nil || cmd.name != "/done" {
+		t.Errorf("expected /delete to be an alias of /done, got %v", cmd)
+	}
+	if lookupCommand("/unknown") != nil {
+		t.Error("expected unknown command to be nil")
+	}
+}
+
+func TestCompleteInput(t *testing.T) {
+	todos := []*model.Todo{
+		{ID: 3, Title: "Buy groceries"},
+		{ID: 12, Title: "Write report"},
+		{ID: 15, Title: "Review report"},
+	}
+
+	tests := []struct {
+		name           string
+		input          string
+		wantInput      string
+		wantCandidates []string
+	}{
+		{"unique command with args", "/don", "/done ", nil},
+		{"unique command without args", "/sto", "/stop", nil},
+		{"ambiguous command", "/st", "/st", []string{"/start", "/stop", "/stats"}},
+		{"common prefix", "/r", "/re", []string{"/report", "/remind", "/redo"}},
+		{"unknown command", "/xyz", "/xyz", nil},
+		{"todo by title", "/done groc", "/done 3", nil},
+		{"todo in an ID list", "/done 1,groc", "/done 1,3", nil},
+		{"ambiguous todo", "/log report", "/log report", []string{"#12 Write report", "#15 Review report"}},
+		{"todo by ID prefix", "/edit 1", "/edit 1", []string{"#12 Write report", "#15 Review report"}},
+		{"past the ID argument", "/log 3 groc", "/log 3 groc", nil},
+		{"command without ID argument", "/bulk groc", "/bulk groc", nil},
+		{"not a command", "groc", "groc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, candidates := completeInput(tt.input, todos)
+			if input != tt.wantInput {
+				t.Errorf("completeInput(%q) = %q; expected %q", tt.input, input, tt.wantInput)
+			}
+			if !slices.Equal(candidates, tt.wantCandidates) {
+				t.Errorf("completeInput(%q) candidates = %v; expected %v", tt.input, candidates, tt.wantCandidates)
+			}
+		})
+	}
+}
+
+func TestInputHint(t *testing.T) {
+	todos := []*model.Todo{{ID: 3, Title: "Buy groceries"}}
+
+	tests := []struct {
+		input    string
+		contains string
+	}{
+		{"/don", "/done <ids> — Delete todos"},
+		{"/log 3 1h", "/log <ids> <duration>"},
+		{"/done groc", "#3 Buy groceries"},
+		{"/done milk", `No todo matches "milk"`},
+		{"/nope", "Unknown command"},
+	}
+
+	for _, tt := range tests {
+		if hint := inputHint(tt.input, todos); !strings.Contains(hint, tt.contains) {
+			t.Errorf("inputHint(%q) = %q; expected it to contain %q", tt.input, hint, tt.contains)
+		}
+	}
+
+	if hint := inputHint("buy milk", todos); hint != "" {
+		t.Errorf("expected no hint outside of commands, got %q", hint)
+	}
+}
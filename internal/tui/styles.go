@@ -120,6 +120,27 @@ var (
 			Foreground(lipgloss.Color("213")).
 			Bold(true).
 			Underline(true)
+
+	// mdSubheadingStyle is the style for level 3 to 6 Markdown headings
+	// Level 1 and 2 headings use headerStyle.
+	mdSubheadingStyle = headerStyle.UnsetUnderline()
+
+	// mdBulletStyle is the style for Markdown list markers and checkboxes
+	mdBulletStyle = lipgloss.NewStyle().
+			Foreground(accentGreen)
+
+	// mdCodeStyle is the style for inline Markdown code and fenced code blocks
+	mdCodeStyle = lipgloss.NewStyle().
+			Foreground(accentGreen)
+
+	// mdLinkStyle is the style for Markdown link texts
+	mdLinkStyle = lipgloss.NewStyle().
+			Foreground(fgDefault).
+			Underline(true)
+
+	// mdLinkURLStyle is the style for the URL shown after a Markdown link text
+	mdLinkURLStyle = lipgloss.NewStyle().
+			Foreground(fgDim)
 )
 
 // DynamicWidths holds calculated widths for responsive layout
@@ -1,23 +1,38 @@
 package tui
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/gen2brain/beeep"
 	"github.com/syeeel/koto-cli-go/internal/model"
+	"github.com/syeeel/koto-cli-go/internal/notify"
+	"github.com/syeeel/koto-cli-go/internal/service"
+	"github.com/syeeel/koto-cli-go/internal/timeutil"
 )
 
 // Update handles messages and updates the model
+// After every message the todos matching the input are looked up again if it changed.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	switch next := updated.(type) {
+	case Model:
+		next.refreshTodoSearches()
+		return next, cmd
+	case *Model:
+		next.refreshTodoSearches()
+		return next, cmd
+	}
+	return updated, cmd
+}
+
+// update handles a message
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -38,6 +53,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// The confirmation dialog takes all keys until it is answered
+		if m.confirm != nil {
+			return m.handleConfirmKey(msg)
+		}
+
 		// Handle view mode specific keys
 		if m.viewMode == ViewModeHelp {
 			switch msg.String() {
@@ -66,41 +86,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Handle add todo view
-		if m.viewMode == ViewModeAddTodo {
+		// Handle command palette
+		if m.viewMode == ViewModePalette {
+			results := paletteResults(m.paletteInput.Value(), m.paletteMatches.todos, m.paletteRecent)
 			switch msg.String() {
 			case "ctrl+c":
 				m.quitting = true
 				return m, tea.Quit
-
-			case "esc":
-				// Cancel and return to list view, or go back to previous step
-				if m.addTodoStep == 0 {
-					// Cancel the whole operation
-					m.viewMode = ViewModeList
-					m.input.Placeholder = "Enter command (type /help for help)"
-					m.input.SetValue("")
-					m.message = "Add todo cancelled"
-				} else {
-					// Go back to previous step
-					m.addTodoStep = 0
-					m.input.Placeholder = "Enter todo title..."
-					m.input.SetValue(m.addTodoTitle)
+			case "esc", "ctrl+p":
+				m.viewMode = ViewModeList
+				return m, nil
+			case "up", "ctrl+k":
+				if m.paletteCursor > 0 {
+					m.paletteCursor--
+				}
+				return m, nil
+			case "down", "ctrl+j":
+				if m.paletteCursor < len(results)-1 {
+					m.paletteCursor++
 				}
 				return m, nil
-
 			case "enter":
-				return m.handleAddTodoEnter()
+				if m.paletteCursor < len(results) {
+					return m, m.pickPaletteItem(results[m.paletteCursor])
+				}
+				return m, nil
 			}
 
-			// Update input for add todo view
-			var cmd tea.Cmd
-			m.input, cmd = m.input.Update(msg)
+			// Typing changes the query, so start again from the best result
+			previous := m.paletteInput.Value()
+			m.paletteInput, cmd = m.paletteInput.Update(msg)
+			if m.paletteInput.Value() != previous {
+				m.paletteCursor = 0
+			}
 			return m, cmd
 		}
 
-		// Handle edit todo view
-		if m.viewMode == ViewModeEditTodo {
+		// Handle add todo view
+		if m.viewMode == ViewModeAddTodo {
 			switch msg.String() {
 			case "ctrl+c":
 				m.quitting = true
@@ -108,30 +131,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case "esc":
 				// Cancel and return to list view, or go back to previous step
-				if m.editTodoStep == 0 {
+				if m.addTodoStep == 0 {
 					// Cancel the whole operation
 					m.viewMode = ViewModeList
 					m.input.Placeholder = "Enter command (type /help for help)"
 					m.input.SetValue("")
-					m.message = "Edit cancelled"
+					m.message = "Add todo cancelled"
 				} else {
-					// Go back to previous step
-					m.editTodoStep = 0
-					m.input.Placeholder = "Edit todo title..."
-					m.input.SetValue(m.editTodoTitle)
+					// Go back to previous step, keeping the description typed so far
+					if m.addTodoStep == 1 {
+						m.addTodoDescription = m.descInput.Value()
+					}
+					m.addTodoStep = 0
+					m.input.Placeholder = "Enter todo title..."
+					m.input.SetValue(m.addTodoTitle)
 				}
 				return m, nil
 
 			case "enter":
-				return m.handleEditTodoEnter()
+				if m.addTodoStep != 1 {
+					return m.handleAddTodoEnter()
+				}
 			}
 
-			// Update input for edit todo view
+			if m.addTodoStep == 1 {
+				return m.handleDescriptionKey(msg)
+			}
+
+			// Update input for add todo view
 			var cmd tea.Cmd
 			m.input, cmd = m.input.Update(msg)
 			return m, cmd
 		}
 
+		// Handle edit todo view
+		if m.viewMode == ViewModeEditTodo {
+			return m.handleEditFormKey(msg)
+		}
+
 		// Handle pomodoro view
 		if m.viewMode == ViewModePomodoro {
 			switch msg.String() {
@@ -139,17 +176,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.quitting = true
 				return m, tea.Quit
 
+			case "s":
+				// Snooze the completion alarm
+				if m.pomoCompleted && !m.pomoSnoozed {
+					minutes := m.config.Notifications.SnoozeMinutes
+					if minutes <= 0 {
+						minutes = notify.DefaultConfig().SnoozeMinutes
+					}
+					m.pomoSnoozed = true
+					m.message = fmt.Sprintf("Alarm snoozed for %d minutes", minutes)
+					return m, snoozePomodoroAlert(minutes)
+				}
+				return m, nil
+
 			case "esc", "enter":
 				// If timer is completed and alarm is ringing, stop it
 				if m.pomoCompleted {
 					m.viewMode = ViewModeList
 					m.pomoCompleted = false
+					m.pomoSnoozed = false
 					if m.pomoTodoID > 0 {
 						m.message = fmt.Sprintf("Pomodoro completed! 25 minutes recorded for todo #%d", m.pomoTodoID)
 					} else {
 						m.message = "Pomodoro completed!"
 					}
-					return m, loadTodos(m.service)
+					return m, m.reloadTodos()
 				}
 
 				// Otherwise, handle early cancellation/stop
@@ -172,12 +223,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.pomoTodoID > 0 && elapsedMinutes > 0 {
 					return m, tea.Batch(
 						recordPartialPomodoro(m.service, m.pomoTodoID, elapsedMinutes),
-						loadTodos(m.service),
+						m.reloadTodos(),
 					)
 				}
 
 				// Return to list view without recording
-				return m, loadTodos(m.service)
+				return m, m.reloadTodos()
 			}
 			return m, nil
 		}
@@ -195,51 +246,242 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 
 			case "e":
-				// Switch to edit mode for this todo
-				m.viewMode = ViewModeEditTodo
-				m.editTodoID = m.detailTodoID
-				// Find the todo to pre-fill the form
-				for _, todo := range m.todos {
-					if todo.ID == m.detailTodoID {
-						m.editTodoTitle = todo.Title
-						m.editTodoDescription = todo.Description
-						m.editTodoPriority = todo.Priority
-						break
-					}
+				// Switch to the edit form for this todo
+				if m.detailTodo != nil {
+					m.openEditForm(m.detailTodo)
 				}
-				m.editTodoStep = 0
-				m.input.Placeholder = "Edit todo title..."
-				m.input.SetValue(m.editTodoTitle)
-				m.err = nil
 				return m, nil
 
 			case "d":
-				// Delete todo
-				ctx := context.Background()
-				err := m.service.DeleteTodo(ctx, m.detailTodoID)
-				if err != nil {
-					m.err = err
-					return m, nil
+				// Delete todo once confirmed
+				id := m.detailTodoID
+				return m, m.confirmOperation(confirmation{
+					kind:    confirmDelete,
+					title:   "Delete this todo?",
+					details: m.confirmTodoDetails([]int64{id}),
+					run: func(m *Model) tea.Cmd {
+						if err := m.service.DeleteTodo(tuiContext(), id); err != nil {
+							m.err = err
+							return nil
+						}
+						// Return to list view with success message
+						m.viewMode = ViewModeList
+						m.message = fmt.Sprintf("Deleted todo #%d (press u to undo)", id)
+						return m.reloadTodos()
+					},
+				}, 1)
+
+			case "j", "down":
+				// Scroll the description down
+				if m.detailTodo != nil {
+					lines := detailDescriptionLines(m.detailTodo, calculateDynamicWidths(m.width))
+					m.detailScroll = clampDetailScroll(m.detailScroll+1, len(lines))
+				}
+				return m, nil
+
+			case "k", "up":
+				// Scroll the description up
+				if m.detailScroll > 0 {
+					m.detailScroll--
+				}
+				return m, nil
+
+			case "h":
+				// Toggle the history timeline
+				m.showHistory = !m.showHistory
+				if m.showHistory {
+					return m, loadDetailEvents(m.service, m.detailTodoID)
 				}
-				// Return to list view with success message
+				return m, nil
+
+			case "t":
+				// Show the work log of this todo
+				m.viewMode = ViewModeWorkLog
+				m.workLogTodoID = m.detailTodoID
+				m.workLogs = nil
+				m.workLogCursor = 0
+				m.workLogInputMode = workLogInputNone
+				m.err = nil
+				return m, loadWorkLogs(m.service, m.workLogTodoID)
+
+			case "s":
+				// Start the stopwatch for this todo and return to list view
 				m.viewMode = ViewModeList
-				m.message = fmt.Sprintf("Deleted todo #%d", m.detailTodoID)
-				return m, loadTodos(m.service)
+				m.err = nil
+				return m, startStopwatchCmd(m.service, m.detailTodoID)
 
 			case "p":
 				// Start Pomodoro timer for this todo
 				m.viewMode = ViewModePomodoro
 				m.pomoTodoID = m.detailTodoID
+				m.pomoTodoTitle = ""
+				if m.detailTodo != nil {
+					m.pomoTodoTitle = m.detailTodo.Title
+				}
 				m.pomoSecondsLeft = 1500 // 25 minutes = 1500 seconds
 				m.pomoRunning = true
 				m.pomoCompleted = false
 				m.err = nil
 				// Start the timer
-				return m, tickPomodoro()
+				return m, m.startPomodoroCmd()
+			}
+			return m, nil
+		}
+
+		// Handle work log view
+		if m.viewMode == ViewModeWorkLog {
+			return m.handleWorkLogKey(msg)
+		}
+
+		// Handle report view
+		if m.viewMode == ViewModeReport {
+			switch msg.String() {
+			case "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+
+			case "esc", "enter", "q":
+				m.viewMode = ViewModeList
+				m.timesheet = nil
+				return m, nil
+
+			case "left", "h", "right", "l":
+				// Shift the range by its own length
+				days := int(m.reportTo.Sub(m.reportFrom).Hours()/24 + 0.5)
+				if msg.String() == "left" || msg.String() == "h" {
+					days = -days
+				}
+				m.reportFrom = m.reportFrom.AddDate(0, 0, days)
+				m.reportTo = m.reportTo.AddDate(0, 0, days)
+				m.message = ""
+				m.err = nil
+				return m, loadTimesheet(m.service, m.reportFrom, m.reportTo, "")
+
+			case "c":
+				// Export the displayed range to CSV
+				csvPath := fmt.Sprintf("%s/.koto/timesheet_%s_%s.csv",
+					os.Getenv("HOME"),
+					m.reportFrom.Format("20060102"),
+					m.reportTo.AddDate(0, 0, -1).Format("20060102"))
+				return m, loadTimesheet(m.service, m.reportFrom, m.reportTo, csvPath)
+			}
+			return m, nil
+		}
+
+		// Handle stats view
+		if m.viewMode == ViewModeStats {
+			switch msg.String() {
+			case "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+
+			case "esc", "enter", "q":
+				m.viewMode = ViewModeList
+				m.stats = nil
+				return m, nil
+
+			case "r":
+				// Refresh
+				return m, loadStats(m.service)
+			}
+			return m, nil
+		}
+
+		// Handle trash view
+		if m.viewMode == ViewModeTrash {
+			switch msg.String() {
+			case "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+
+			case "esc", "q":
+				m.viewMode = ViewModeList
+				m.trash = nil
+				m.err = nil
+				return m, nil
+
+			case "up", "k":
+				if m.trashCursor > 0 {
+					m.trashCursor--
+				}
+				return m, nil
+
+			case "down", "j":
+				if m.trashCursor < len(m.trash)-1 {
+					m.trashCursor++
+				}
+				return m, nil
+
+			case "r":
+				// Restore the focused todo
+				if m.trashCursor < len(m.trash) {
+					return m, restoreFromTrashCmd(m.service, m.trash[m.trashCursor].ID)
+				}
+				return m, nil
+
+			case "X":
+				// Permanently delete the focused todo once confirmed
+				if m.trashCursor < len(m.trash) {
+					todo := m.trash[m.trashCursor]
+					return m, m.confirmOperation(confirmation{
+						kind:    confirmPurge,
+						title:   "Permanently delete this todo? This cannot be undone.",
+						details: []string{fmt.Sprintf("#%d %s", todo.ID, todo.Title)},
+						run: func(m *Model) tea.Cmd {
+							return purgeTodoCmd(m.service, todo.ID)
+						},
+					}, 1)
+				}
+				return m, nil
 			}
 			return m, nil
 		}
 
+		// Handle archive view
+		if m.viewMode == ViewModeArchive {
+			switch msg.String() {
+			case "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+
+			case "esc":
+				m.viewMode = ViewModeList
+				m.archive = nil
+				m.input.Placeholder = "Enter command (type /help for help)"
+				m.input.SetValue("")
+				m.err = nil
+				return m, nil
+
+			case "up":
+				if m.archiveCursor > 0 {
+					m.archiveCursor--
+				}
+				return m, nil
+
+			case "down":
+				if m.archiveCursor < len(m.archive)-1 {
+					m.archiveCursor++
+				}
+				return m, nil
+
+			case "enter":
+				// Bring the focused todo back to the list
+				if m.archiveCursor < len(m.archive) {
+					return m, unarchiveCmd(m.service, m.archive[m.archiveCursor].ID)
+				}
+				return m, nil
+			}
+
+			// Everything else edits the search, which reloads the archive when it changes
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			if query := m.input.Value(); query != m.archiveQuery {
+				m.archiveQuery = query
+				return m, tea.Batch(cmd, loadArchive(m.service, query))
+			}
+			return m, cmd
+		}
+
 		// Handle export view
 		if m.viewMode == ViewModeExport {
 			switch msg.String() {
@@ -298,7 +540,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.importFilePath = ""
 					m.importSuccess = false
 					m.importMessage = ""
-					return m, loadTodos(m.service)
+					return m, m.reloadTodos()
 				}
 				if m.importStep > 0 {
 					// Go back to previous step
@@ -322,7 +564,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.importFilePath = ""
 					m.importSuccess = false
 					m.importMessage = ""
-					return m, loadTodos(m.service)
+					return m, m.reloadTodos()
 				}
 				// Execute import step
 				return m.handleImportEnter()
@@ -338,44 +580,145 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Handle list view keys
+		if m.inlineEdit != nil {
+			return m, m.handleInlineEditKey(msg)
+		}
+		if m.historySearch != nil && m.handleHistorySearchKey(msg) {
+			return m, nil
+		}
+		// Any key but up/down ends browsing the history, so the next up starts from the typed text
+		if key := msg.String(); key != "up" && key != "down" {
+			m.browsingHistory = false
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
 
 		case "esc":
-			// Clear input and message
+			// Clear input and message, and dismiss the end-of-day summary
+			// With the input already empty, the selection is cleared as well
+			if m.input.Value() == "" {
+				m.selected = nil
+				m.selectAnchor = 0
+			}
 			m.input.SetValue("")
 			m.message = ""
 			m.err = nil
+			m.showDaySummary = false
 			return m, nil
 
 		case "enter":
 			return m.handleEnter()
 
+		case "u", "ctrl+r":
+			// Undo / redo while the input is empty
+			if m.input.Value() == "" {
+				m.err = nil
+				if msg.String() == "u" {
+					return m, undoCmd(m.service)
+				}
+				return m, redoCmd(m.service)
+			}
+			// Ctrl+R while typing searches the input history
+			if msg.String() == "ctrl+r" && m.history != nil {
+				m.startHistorySearch()
+				return m, nil
+			}
+
+		case " ", "V", "*":
+			// Select todos while the input is empty
+			if m.input.Value() == "" && len(m.todos) > 0 {
+				m.handleSelectKey(msg.String())
+				return m, nil
+			}
+
+		case "+", "-", "c", "r", "D":
+			// Quick edits of the focused todo while the input is empty
+			if m.input.Value() == "" && len(m.todos) > 0 {
+				return m, m.handleQuickEditKey(msg.String())
+			}
+
+		case "z", "Z", "n", "x":
+			// Snooze or dismiss the oldest active reminder while the input is empty
+			if m.input.Value() == "" && len(m.activeReminders) > 0 {
+				return m, m.handleReminderKey(msg.String())
+			}
+
 		case "up", "k":
+			// With text in the input, up recalls older commands starting with it
+			if msg.String() == "up" && m.history != nil && (m.input.Value() != "" || m.browsingHistory) {
+				m.recallHistory(-1)
+				return m, nil
+			}
 			if m.cursor > 0 {
 				m.cursor--
 			}
+			m.scrollList()
 			return m, nil
 
 		case "down", "j":
+			if msg.String() == "down" && m.browsingHistory {
+				m.recallHistory(1)
+				return m, nil
+			}
 			if m.cursor < len(m.todos)-1 {
 				m.cursor++
 			}
-			return m, nil
+			m.scrollList()
+			return m, m.loadMoreTodos()
+
+		case "pgup", "pgdown":
+			return m, m.handleListPageKey(msg.String())
+
+		case "g", "G":
+			// Jump to the first or last todo while the input is empty
+			if m.input.Value() == "" && len(m.todos) > 0 {
+				return m, m.handleListPageKey(msg.String())
+			}
 
 		case "?":
-			m.viewMode = ViewModeHelp
-			// Initialize viewport for help view
-			m.viewport = viewport.New(m.width, m.height-2)
-			m.viewport.SetContent(m.renderHelpContent())
+			m.openHelpView()
+			return m, nil
+
+		case "ctrl+p":
+			m.openPalette()
+			return m, nil
+
+		case "tab":
+			// Complete the command name or todo ID being typed
+			if completed, _ := completeInput(m.input.Value(), m.inputMatches.todos); completed != m.input.Value() {
+				m.input.SetValue(completed)
+				m.input.CursorEnd()
+			}
 			return m, nil
 		}
 
 	case todosLoadedMsg:
+		if msg.offset > 0 {
+			return m, m.handleTodosPage(msg)
+		}
 		m.todos = msg.todos
+		m.totalTodos = max(msg.total, len(m.todos))
+		m.deferred = msg.deferred
 		m.err = msg.err
+		// The todos changed, so search them again and refresh the displayed todo
+		m.inputMatches.done = false
+		m.paletteMatches.done = false
+		if m.detailTodo != nil && (m.viewMode == ViewModeDetail || m.viewMode == ViewModeWorkLog) {
+			m.detailTodo, _ = m.findTodo(m.detailTodoID) // nil once it is deleted
+		}
+		// Keep only the selected todos that are still visible
+		if len(m.selected) > 0 {
+			visible := make(map[int64]bool, len(m.selected))
+			for _, todo := range m.todos {
+				if m.selected[todo.ID] {
+					visible[todo.ID] = true
+				}
+			}
+			m.selected = visible
+		}
 		// Adjust cursor if it's out of bounds
 		if m.cursor >= len(m.todos) && len(m.todos) > 0 {
 			m.cursor = len(m.todos) - 1
@@ -383,13 +726,178 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(m.todos) == 0 {
 			m.cursor = 0
 		}
+		m.scrollList()
+		// Todos are reloaded after every change, so refresh the goal and reminders as well
+		cmds := []tea.Cmd{loadGoalProgress(m.service, m.config.DailyGoal), loadActiveReminders(m.service)}
+		if m.viewMode == ViewModeDetail {
+			cmds = append(cmds, loadDetailReminders(m.service, m.detailTodoID))
+			if m.showHistory {
+				cmds = append(cmds, loadDetailEvents(m.service, m.detailTodoID))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case trashLoadedMsg:
+		m.trash = msg.todos
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		if m.trashCursor >= len(m.trash) {
+			m.trashCursor = max(len(m.trash)-1, 0)
+		}
+		return m, nil
+
+	case archiveLoadedMsg:
+		if msg.query != m.archiveQuery {
+			return m, nil // Stale result of an earlier search
+		}
+		m.archive = msg.todos
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		if m.archiveCursor >= len(m.archive) {
+			m.archiveCursor = max(len(m.archive)-1, 0)
+		}
+		return m, nil
+
+	case archiveChangedMsg:
+		m.message = msg.message
+		m.err = msg.err
+		return m, tea.Batch(loadArchive(m.service, m.archiveQuery), m.reloadTodos())
+
+	case trashChangedMsg:
+		m.message = msg.message
+		m.err = msg.err
+		return m, tea.Batch(loadTrash(m.service), m.reloadTodos())
+
+	case remindersLoadedMsg:
+		if msg.err == nil {
+			m.activeReminders = msg.reminders
+		}
+		return m, nil
+
+	case detailRemindersLoadedMsg:
+		if msg.err == nil && msg.todoID == m.detailTodoID {
+			m.detailReminders = msg.reminders
+		}
+		return m, nil
+
+	case detailEventsLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else if msg.todoID == m.detailTodoID {
+			m.detailEvents = msg.events
+		}
+		return m, nil
+
+	case goalProgressLoadedMsg:
+		if msg.progress != nil {
+			m.goalProgress = msg.progress
+		}
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+
+	case clockTickMsg:
+		now := time.Time(msg)
+		if m.config.DailyGoal.IsSet() && daySummaryDue(now, m.config.DaySummaryTime, m.daySummaryShownOn) {
+			m.daySummaryShownOn = now.Format("2006-01-02")
+			m.showDaySummary = true
+		}
+		offsets, _ := m.config.Reminders.Offsets() // Invalid offsets leave only overdue reminders
+		// Reloading todos reveals deferred todos that have started and
+		// refreshes the goal progress and active reminders as well
+		return m, tea.Batch(
+			m.reloadTodos(),
+			sendDueReminders(m.service, m.notifier, offsets),
+			clockTick(),
+		)
+
+	case statsLoadedMsg:
+		if msg.stats != nil {
+			m.stats = msg.stats
+		}
+		m.err = msg.err
+		return m, nil
+
+	case timesheetLoadedMsg:
+		if msg.timesheet != nil {
+			m.timesheet = msg.timesheet
+		}
+		m.message = msg.message
+		m.err = msg.err
+		return m, nil
+
+	case workLogsLoadedMsg:
+		m.workLogs = msg.logs
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		// Adjust cursor if it's out of bounds
+		if m.workLogCursor >= len(m.workLogs) {
+			m.workLogCursor = len(m.workLogs) - 1
+		}
+		if m.workLogCursor < 0 {
+			m.workLogCursor = 0
+		}
+		return m, nil
+
+	case bulkExecutedMsg:
+		if msg.err == nil {
+			m.selected = nil
+			m.selectAnchor = 0
+		}
+		return m.Update(commandExecutedMsg{message: msg.message, err: msg.err})
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		// Only use the text if the description is still being edited
+		switch {
+		case m.viewMode == ViewModeAddTodo && m.addTodoStep == 1:
+			m.descInput.SetValue(msg.content)
+			m.err = nil
+		case m.viewMode == ViewModeEditTodo && m.editForm.focus == formFieldDescription:
+			m.editForm.description.SetValue(msg.content)
+			m.err = nil
+		}
+		return m, nil
+
+	case helpRequestedMsg:
+		if m.viewMode == ViewModeList {
+			m.openHelpView()
+		}
 		return m, nil
 
 	case commandExecutedMsg:
 		m.message = msg.message
 		m.err = msg.err
-		// Reload todos after command execution
-		return m, loadTodos(m.service)
+		// Reload todos and stopwatch after command execution
+		return m, tea.Batch(m.reloadTodos(), loadStopwatch(m.service))
+
+	case stopwatchLoadedMsg:
+		m.stopwatch = msg.stopwatch
+		m.stopwatchTitle = msg.title
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		// Start refreshing the elapsed time once per second
+		if m.stopwatch != nil && !m.stopwatchTicking {
+			m.stopwatchTicking = true
+			return m, tickStopwatch()
+		}
+		return m, nil
+
+	case stopwatchTickMsg:
+		// Stop ticking once the stopwatch has been stopped
+		if m.stopwatch == nil {
+			m.stopwatchTicking = false
+			return m, nil
+		}
+		return m, tickStopwatch()
 
 	case pomodoroTickMsg:
 		// Only process ticks if timer is running and in Pomodoro view
@@ -400,9 +908,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.pomoSecondsLeft <= 0 {
 				m.pomoRunning = false
 				m.pomoCompleted = true
-				// Record work duration, play beep, and start alert ticking
+				m.pomoAlertCount = 0
+				m.pomoSnoozed = false
+				// Record work duration, notify, and start alert ticking
 				return m, tea.Batch(
-					completePomodoroWithRecording(m.service, m.pomoTodoID),
+					completePomodoroWithRecording(m.service, m.notifier, m.pomoTodoID),
 					tickPomodoroAlert(),
 				)
 			}
@@ -415,14 +925,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case pomodoroCompleteMsg:
 		// Timer completed - stay in Pomodoro view with alarm
 		// Reload todos to show updated work duration
-		return m, loadTodos(m.service)
+		return m, m.reloadTodos()
 
 	case pomodoroAlertTickMsg:
 		// Only process alert ticks if timer is completed and in Pomodoro view
-		if m.pomoCompleted && m.viewMode == ViewModePomodoro {
-			// Play beep sound (880Hz, 500ms) - high and long
-			_ = beeep.Beep(880.0, 500)
-			// Continue alert ticking
+		// Snoozed alarms resume with pomodoroSnoozeEndMsg
+		if m.pomoCompleted && !m.pomoSnoozed && m.viewMode == ViewModePomodoro {
+			// Stop once the configured number of repetitions has been played
+			m.pomoAlertCount++
+			if repeat := m.config.Notifications.RepeatCount; repeat > 0 && m.pomoAlertCount >= repeat {
+				return m, nil
+			}
+			_ = m.notifier.Beep()
+			// Continue alert ticking
+			return m, tickPomodoroAlert()
+		}
+		return m, nil
+
+	case pomodoroSnoozeEndMsg:
+		// Sound the alarm again unless it was dismissed while snoozed
+		if m.pomoCompleted && m.pomoSnoozed && m.viewMode == ViewModePomodoro {
+			m.pomoSnoozed = false
+			m.pomoAlertCount = 0
+			m.message = ""
+			_ = m.notifier.Notify("🍅 Break is over", "Snooze finished. Ready for the next Pomodoro?")
+			_ = m.notifier.Beep()
 			return m, tickPomodoroAlert()
 		}
 		return m, nil
@@ -444,225 +971,339 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 	if value == "" {
 		// Check if there are todos and cursor is valid
 		if len(m.todos) > 0 && m.cursor >= 0 && m.cursor < len(m.todos) {
-			// Get the focused todo
-			focusedTodo := m.todos[m.cursor]
-			// Switch to detail view
-			m.viewMode = ViewModeDetail
-			m.detailTodoID = focusedTodo.ID
+			return m, m.openDetailView(m.todos[m.cursor])
 		}
 		return m, nil
 	}
 
-	// Check if command is /add - switch to add todo view
-	if value == "/add" {
-		m.viewMode = ViewModeAddTodo
-		m.addTodoStep = 0
-		m.addTodoTitle = ""
-		m.addTodoDescription = ""
-		m.input.Placeholder = "Enter todo title..."
-		m.input.SetValue("")
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "/") {
+		m.err = errors.New("commands must start with /")
 		return m, nil
 	}
 
-	// Check if command is /edit <id> - switch to edit todo view
-	if strings.HasPrefix(value, "/edit ") {
-		parts := strings.Fields(value)
-		if len(parts) == 2 {
-			id, err := strconv.ParseInt(parts[1], 10, 64)
-			if err != nil {
-				m.err = errors.New("invalid todo ID")
-				return m, nil
-			}
+	// Remember the command, even a mistyped one, so that it can be recalled and fixed
+	if m.history != nil {
+		m.err = m.history.Add(value)
+	}
 
-			// Find the todo with the given ID
-			var targetTodo *model.Todo
-			for _, todo := range m.todos {
-				if todo.ID == id {
-					targetTodo = todo
-					break
-				}
-			}
+	parts := strings.Fields(value)
+	cmd := lookupCommand(parts[0])
+	if cmd == nil {
+		m.err = fmt.Errorf("unknown command: %s (press Tab to complete)", parts[0])
+		return m, nil
+	}
+	if cmd.args == "" && len(parts) > 1 {
+		m.err = fmt.Errorf("usage: %s", cmd.usage())
+		return m, nil
+	}
 
-			if targetTodo == nil {
-				m.err = errors.New("todo not found")
-				return m, nil
-			}
+	return m, cmd.run(m, parts[1:])
+}
 
-			// Switch to edit mode with existing data
-			m.viewMode = ViewModeEditTodo
-			m.editTodoID = id
-			m.editTodoTitle = targetTodo.Title
-			m.editTodoDescription = targetTodo.Description
-			m.editTodoPriority = targetTodo.Priority
-			m.editTodoStep = 0
-			m.input.Placeholder = "Edit todo title..."
-			m.input.SetValue(targetTodo.Title)
-			m.err = nil
-			return m, nil
+// openAddView switches to the add todo view (/add), or adds the todo described
+// on the line right away (/add <title> [!priority] [@due:date] [#tag] [-- description])
+func (m *Model) openAddView(args []string) tea.Cmd {
+	if len(args) > 0 {
+		return serviceCommand(handleQuickAddCommand)(m, args)
+	}
+
+	m.viewMode = ViewModeAddTodo
+	m.addTodoStep = 0
+	m.addTodoTitle = ""
+	m.addTodoDescription = ""
+	m.input.Placeholder = "Enter todo title..."
+	m.input.SetValue("")
+	return nil
+}
+
+// openEditView switches to the edit todo view (/edit <id>)
+func (m *Model) openEditView(args []string) tea.Cmd {
+	if len(args) != 1 {
+		m.err = errors.New("usage: /edit <id>")
+		return nil
+	}
+
+	id, err := parseSingleID(args[0], m.focusedID())
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	// Deferred todos and todos beyond the loaded pages can be edited as well
+	targetTodo, err := m.findTodo(id)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.openEditForm(targetTodo)
+	return nil
+}
+
+// startPomodoro starts a Pomodoro timer, for a todo if an ID is given (/pomo [id])
+func (m *Model) startPomodoro(args []string) tea.Cmd {
+	todoID, todoTitle := int64(0), ""
+
+	// Parse optional todo ID
+	if len(args) == 1 {
+		id, err := parseSingleID(args[0], m.focusedID())
+		if err != nil {
+			m.err = err
+			return nil
 		}
+
+		// Verify todo exists (deferred todos and todos beyond the loaded pages included)
+		todo, err := m.findTodo(id)
+		if err != nil {
+			m.err = err
+			return nil
+		}
+
+		todoID, todoTitle = id, todo.Title
+	} else if len(args) > 1 {
+		m.err = errors.New("usage: /pomo [todo_id]")
+		return nil
 	}
 
-	// Check if command is /pomo [id] - start Pomodoro timer
-	if value == "/pomo" || strings.HasPrefix(value, "/pomo ") {
-		parts := strings.Fields(value)
-		todoID := int64(0)
+	// Switch to Pomodoro mode
+	m.viewMode = ViewModePomodoro
+	m.pomoTodoID = todoID
+	m.pomoTodoTitle = todoTitle
+	m.pomoSecondsLeft = 1500 // 25 minutes = 1500 seconds
+	m.pomoRunning = true
+	m.err = nil
 
-		// Parse optional todo ID
-		if len(parts) == 2 {
-			id, err := strconv.ParseInt(parts[1], 10, 64)
-			if err != nil {
-				m.err = errors.New("invalid todo ID")
-				return m, nil
-			}
+	// Start the timer
+	return m.startPomodoroCmd()
+}
 
-			// Verify todo exists
-			var found bool
-			for _, todo := range m.todos {
-				if todo.ID == id {
-					found = true
-					break
-				}
-			}
+// openReportView switches to the report view (/report [flags])
+func (m *Model) openReportView(args []string) tea.Cmd {
+	from, to, csvPath, err := parseReportArgs(args, time.Now())
+	if err != nil {
+		m.err = err
+		return nil
+	}
 
-			if !found {
-				m.err = errors.New("todo not found")
-				return m, nil
-			}
+	m.viewMode = ViewModeReport
+	m.reportFrom = from
+	m.reportTo = to
+	m.timesheet = nil
+	return loadTimesheet(m.service, from, to, csvPath)
+}
 
-			todoID = id
-		} else if len(parts) > 2 {
-			m.err = errors.New("usage: /pomo [todo_id]")
-			return m, nil
+// setGoal shows or sets the daily goal (/goal [targets])
+func (m *Model) setGoal(args []string) tea.Cmd {
+	if len(args) == 0 {
+		if !m.config.DailyGoal.IsSet() {
+			m.message = "No daily goal set. Try /goal 8 4h"
+		} else {
+			m.message = "Daily goal: " + m.config.DailyGoal.String()
 		}
+		return nil
+	}
 
-		// Switch to Pomodoro mode
-		m.viewMode = ViewModePomodoro
-		m.pomoTodoID = todoID
-		m.pomoSecondsLeft = 1500 // 25 minutes = 1500 seconds
-		m.pomoRunning = true
-		m.err = nil
+	goal, err := parseGoalArgs(args)
+	if err != nil {
+		m.err = err
+		return nil
+	}
 
-		// Start the timer
-		return m, tickPomodoro()
+	previous := m.config.DailyGoal
+	m.config.DailyGoal = goal
+	if err := m.config.Save(); err != nil {
+		m.config.DailyGoal = previous
+		m.err = err
+		return nil
 	}
 
-	// Check if command is /export - switch to export view
-	if value == "/export" {
-		m.viewMode = ViewModeExport
-		m.exportSuccess = false
-		m.exportFilePath = time.Now().Format("20060102_150405") // Use as part of default filename
-		m.exportMessage = ""
-		m.input.Placeholder = "Enter export file path (or press Enter for default)..."
-		m.input.SetValue("")
-		m.err = nil
-		return m, nil
+	if goal.IsSet() {
+		m.message = "Daily goal set: " + goal.String()
+	} else {
+		m.message = "Daily goal cleared"
 	}
+	return loadGoalProgress(m.service, goal)
+}
 
-	// Check if command is /import - switch to import view
-	if value == "/import" {
-		m.viewMode = ViewModeImport
-		m.importStep = 0
-		m.importFilePath = ""
-		m.importPreview = 0
-		m.importSuccess = false
-		m.importMessage = ""
-		m.importCount = 0
-		m.input.Placeholder = "Enter import file path..."
-		m.input.SetValue("")
-		m.err = nil
-		return m, nil
+// openTrashView switches to the trash view (/trash)
+func (m *Model) openTrashView(_ []string) tea.Cmd {
+	m.viewMode = ViewModeTrash
+	m.trash = nil
+	m.trashCursor = 0
+	m.message = ""
+	m.err = nil
+	return loadTrash(m.service)
+}
+
+// openArchive switches to the archive view (/archive) or archives todos (/archive <ids>)
+func (m *Model) openArchive(args []string) tea.Cmd {
+	if len(args) > 0 {
+		return serviceCommand(handleArchiveCommand)(m, args)
 	}
 
-	return m, parseAndExecuteCommand(m.service, value)
+	m.viewMode = ViewModeArchive
+	m.archive = nil
+	m.archiveQuery = ""
+	m.archiveCursor = 0
+	m.input.Placeholder = "Search archived todos"
+	m.input.SetValue("")
+	m.message = ""
+	m.err = nil
+	return loadArchive(m.service, "")
 }
 
-// handleAddTodoEnter processes the enter key press in add todo view
-func (m *Model) handleAddTodoEnter() (tea.Model, tea.Cmd) {
-	value := m.input.Value()
+// openStatsView switches to the stats view (/stats)
+func (m *Model) openStatsView(_ []string) tea.Cmd {
+	m.viewMode = ViewModeStats
+	m.stats = nil
+	m.message = ""
+	m.err = nil
+	return loadStats(m.service)
+}
 
-	switch m.addTodoStep {
-	case 0: // Title input
-		if value == "" {
-			m.err = errors.New("title cannot be empty")
-			return m, nil
-		}
-		// Save title and move to description step
-		m.addTodoTitle = value
-		m.addTodoStep = 1
-		m.input.SetValue("")
-		m.input.Placeholder = "Enter description (optional, press Enter to skip)..."
-		m.err = nil
-		return m, nil
+// openExportView switches to the export view (/export [filepath])
+// A given file path is filled in, ready to be confirmed with Enter.
+func (m *Model) openExportView(args []string) tea.Cmd {
+	m.viewMode = ViewModeExport
+	m.exportSuccess = false
+	m.exportFilePath = time.Now().Format("20060102_150405") // Use as part of default filename
+	m.exportMessage = ""
+	m.input.Placeholder = "Enter export file path (or press Enter for default)..."
+	m.input.SetValue(strings.Join(args, " "))
+	m.err = nil
+	return nil
+}
 
-	case 1: // Description input
-		// Save description and move to priority step
-		m.addTodoDescription = value
-		m.addTodoStep = 2
-		m.input.SetValue("")
-		m.input.Placeholder = "Select priority: 1 (Low), 2 (Medium), 3 (High)..."
-		m.err = nil
-		return m, nil
+// openImportView switches to the import view (/import [filepath])
+// A given file path is filled in, ready to be confirmed with Enter.
+func (m *Model) openImportView(args []string) tea.Cmd {
+	m.viewMode = ViewModeImport
+	m.importStep = 0
+	m.importFilePath = ""
+	m.importPreview = 0
+	m.importSuccess = false
+	m.importMessage = ""
+	m.importCount = 0
+	m.input.Placeholder = "Enter import file path..."
+	m.input.SetValue(strings.Join(args, " "))
+	m.err = nil
+	return nil
+}
 
-	case 2: // Priority selection
-		// Parse priority
-		var priority model.Priority
-		switch value {
-		case "1", "l", "L", "low", "Low":
-			priority = model.PriorityLow
-		case "2", "m", "M", "medium", "Medium", "":
-			priority = model.PriorityMedium // Default to Medium
-		case "3", "h", "H", "high", "High":
-			priority = model.PriorityHigh
-		default:
-			m.err = errors.New("invalid priority (use 1, 2, or 3)")
-			return m, nil
-		}
-		m.addTodoPriority = priority
+// openHelpView switches to the help view
+func (m *Model) openHelpView() {
+	m.viewMode = ViewModeHelp
+	// Initialize viewport for help view
+	m.viewport = viewport.New(m.width, m.height-2)
+	m.viewport.SetContent(m.renderHelpContent())
+}
 
-		// Create the todo
-		ctx := context.Background()
-		_, err := m.service.AddTodo(ctx, m.addTodoTitle, m.addTodoDescription, m.addTodoPriority, nil)
-		if err != nil {
-			m.err = err
-			return m, nil
+// startPomodoroCmd starts the Pomodoro ticks, stopping a running stopwatch first
+// so that time spent on a task-specific Pomodoro is not counted twice
+func (m Model) startPomodoroCmd() tea.Cmd {
+	if m.pomoTodoID > 0 && m.stopwatch != nil {
+		return tea.Batch(stopStopwatchCmd(m.service), tickPomodoro())
+	}
+	return tickPomodoro()
+}
+
+// handleSelectKey changes the selection in the list view
+// Space toggles the focused todo, V selects every todo between the todo last
+// toggled and the cursor, and * selects all visible todos (or clears the
+// selection if they are all selected already).
+func (m *Model) handleSelectKey(key string) {
+	if m.selected == nil {
+		m.selected = make(map[int64]bool)
+	}
+	focused := m.todos[m.cursor]
+
+	switch key {
+	case " ":
+		if m.selected[focused.ID] {
+			delete(m.selected, focused.ID)
+		} else {
+			m.selected[focused.ID] = true
+		}
+		m.selectAnchor = focused.ID
+
+	case "V":
+		from := m.cursor
+		for i, todo := range m.todos {
+			if todo.ID == m.selectAnchor {
+				from = i
+				break
+			}
 		}
+		for i := min(from, m.cursor); i <= max(from, m.cursor); i++ {
+			m.selected[m.todos[i].ID] = true
+		}
+		m.selectAnchor = focused.ID
 
-		// Reset and return to list view
-		m.viewMode = ViewModeList
-		m.input.Placeholder = "Enter command (type /help for help)"
-		m.input.SetValue("")
-		m.message = "Todo added successfully"
-		m.err = nil
+	case "*":
+		if len(m.selected) == len(m.todos) {
+			m.selected = nil
+			m.selectAnchor = 0
+			return
+		}
+		for _, todo := range m.todos {
+			m.selected[todo.ID] = true
+		}
+	}
+}
 
-		// Reload todos
-		return m, loadTodos(m.service)
+// selectedIDs returns the IDs of the selected todos in list order
+func (m Model) selectedIDs() []int64 {
+	ids := make([]int64, 0, len(m.selected))
+	for _, todo := range m.todos {
+		if m.selected[todo.ID] {
+			ids = append(ids, todo.ID)
+		}
 	}
+	return ids
+}
 
-	return m, nil
+// handleReminderKey snoozes or dismisses the oldest active reminder
+// z snoozes for 10 minutes, Z for an hour, n until tomorrow morning and x dismisses.
+func (m *Model) handleReminderKey(key string) tea.Cmd {
+	reminder := m.activeReminders[0]
+	// Hide it right away; the reload after the command brings it back if it failed
+	m.activeReminders = m.activeReminders[1:]
+
+	switch key {
+	case "z":
+		return snoozeReminderCmd(m.service, reminder.ID, snoozeFor(10*time.Minute))
+	case "Z":
+		return snoozeReminderCmd(m.service, reminder.ID, snoozeFor(time.Hour))
+	case "n":
+		return snoozeReminderCmd(m.service, reminder.ID, snoozeUntilTomorrowMorning)
+	default:
+		return dismissReminderCmd(m.service, reminder.ID)
+	}
 }
 
-// handleEditTodoEnter processes the enter key press in edit todo view
-func (m *Model) handleEditTodoEnter() (tea.Model, tea.Cmd) {
+// handleAddTodoEnter processes the enter key press in add todo view
+func (m *Model) handleAddTodoEnter() (tea.Model, tea.Cmd) {
 	value := m.input.Value()
 
-	switch m.editTodoStep {
+	switch m.addTodoStep {
 	case 0: // Title input
 		if value == "" {
 			m.err = errors.New("title cannot be empty")
 			return m, nil
 		}
 		// Save title and move to description step
-		m.editTodoTitle = value
-		m.editTodoStep = 1
-		m.input.SetValue(m.editTodoDescription)
-		m.input.Placeholder = "Edit description (optional, press Enter to save)..."
+		m.addTodoTitle = value
+		m.addTodoStep = 1
+		m.input.SetValue("")
+		m.descInput = newDescriptionInput(m.addTodoDescription)
 		m.err = nil
 		return m, nil
 
 	case 1: // Description input
 		// Save description and move to priority step
-		m.editTodoDescription = value
-		m.editTodoStep = 2
+		m.addTodoDescription = m.descInput.Value()
+		m.addTodoStep = 2
 		m.input.SetValue("")
 		m.input.Placeholder = "Select priority: 1 (Low), 2 (Medium), 3 (High)..."
 		m.err = nil
@@ -682,11 +1323,11 @@ func (m *Model) handleEditTodoEnter() (tea.Model, tea.Cmd) {
 			m.err = errors.New("invalid priority (use 1, 2, or 3)")
 			return m, nil
 		}
-		m.editTodoPriority = priority
+		m.addTodoPriority = priority
 
-		// Update the todo
-		ctx := context.Background()
-		err := m.service.EditTodo(ctx, m.editTodoID, m.editTodoTitle, m.editTodoDescription, m.editTodoPriority, nil)
+		// Create the todo
+		ctx := tuiContext()
+		_, err := m.service.AddTodo(ctx, m.addTodoTitle, m.addTodoDescription, m.addTodoPriority, nil)
 		if err != nil {
 			m.err = err
 			return m, nil
@@ -696,11 +1337,11 @@ func (m *Model) handleEditTodoEnter() (tea.Model, tea.Cmd) {
 		m.viewMode = ViewModeList
 		m.input.Placeholder = "Enter command (type /help for help)"
 		m.input.SetValue("")
-		m.message = "Todo updated successfully"
+		m.message = "Todo added successfully"
 		m.err = nil
 
 		// Reload todos
-		return m, loadTodos(m.service)
+		return m, m.reloadTodos()
 	}
 
 	return m, nil
@@ -724,7 +1365,7 @@ func (m *Model) handleExportEnter() (tea.Model, tea.Cmd) {
 	}
 
 	// Execute export
-	ctx := context.Background()
+	ctx := tuiContext()
 	err := m.service.ExportToJSON(ctx, filePath)
 	if err != nil {
 		m.err = err
@@ -747,6 +1388,30 @@ func (m *Model) handleExportEnter() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// confirmImport asks before importing the previewed file
+func (m *Model) confirmImport() tea.Cmd {
+	return m.confirmOperation(confirmation{
+		kind:    confirmImport,
+		title:   fmt.Sprintf("Import %d todo(s)?", m.importPreview),
+		details: []string{"From " + m.importFilePath},
+		run: func(m *Model) tea.Cmd {
+			m.importStep = 2
+			err := m.service.ImportFromJSON(tuiContext(), m.importFilePath)
+
+			// Move to completion step
+			m.importStep = 3
+			if err != nil {
+				m.importSuccess = false
+				m.importMessage = err.Error()
+			} else {
+				m.importSuccess = true
+				m.importCount = m.importPreview
+			}
+			return nil
+		},
+	}, m.importPreview)
+}
+
 // handleImportEnter processes the enter key press in import view
 func (m *Model) handleImportEnter() (tea.Model, tea.Cmd) {
 	switch m.importStep {
@@ -787,27 +1452,275 @@ func (m *Model) handleImportEnter() (tea.Model, tea.Cmd) {
 		m.importPreview = len(todos)
 		m.importStep = 1
 		m.err = nil
-		return m, nil
+		return m, m.confirmImport()
 
 	case 1:
-		// Step 2: Confirmation - execute import
-		m.importStep = 2
-		ctx := context.Background()
-		err := m.service.ImportFromJSON(ctx, m.importFilePath)
+		// Step 2: Confirmation - ask again if the dialog was cancelled
+		return m, m.confirmImport()
 
-		// Move to completion step
-		m.importStep = 3
-		if err != nil {
-			m.importSuccess = false
-			m.importMessage = err.Error()
-		} else {
-			m.importSuccess = true
-			m.importCount = m.importPreview
+	default:
+		// Should not reach here
+		return m, nil
+	}
+}
+
+// handleWorkLogKey processes key presses in the work log view
+func (m *Model) handleWorkLogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// While entering a duration, keys go to the input field
+	if m.workLogInputMode != workLogInputNone {
+		switch msg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "esc":
+			m.workLogInputMode = workLogInputNone
+			m.input.Placeholder = "Enter command (type /help for help)"
+			m.input.SetValue("")
+			m.err = nil
+			return m, nil
+
+		case "enter":
+			return m.handleWorkLogEnter()
+		}
+
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "esc", "q":
+		// Return to detail view
+		m.viewMode = ViewModeDetail
+		m.err = nil
+		return m, m.reloadTodos()
+
+	case "up", "k":
+		if m.workLogCursor > 0 {
+			m.workLogCursor--
 		}
 		return m, nil
 
-	default:
-		// Should not reach here
+	case "down", "j":
+		if m.workLogCursor < len(m.workLogs)-1 {
+			m.workLogCursor++
+		}
+		return m, nil
+
+	case "a":
+		// Add a new entry for this todo
+		m.workLogInputMode = workLogInputAdd
+		m.input.Placeholder = "Duration and optional note (e.g. 45m Design review)..."
+		m.input.SetValue("")
+		m.err = nil
+		return m, nil
+
+	case "e":
+		// Adjust the focused entry
+		entry := m.focusedWorkLog()
+		if entry == nil {
+			return m, nil
+		}
+		if entry.IsCorrection() {
+			m.err = service.ErrInvalidCorrection
+			return m, nil
+		}
+		effective := model.EffectiveWorkMinutes(m.workLogs)[entry.ID]
+		m.workLogInputMode = workLogInputEdit
+		m.input.Placeholder = fmt.Sprintf("New duration for entry #%d (e.g. 45m, 1h30m)...", entry.ID)
+		m.input.SetValue(fmt.Sprintf("%dm", effective))
+		m.err = nil
 		return m, nil
+
+	case "d":
+		// Void the focused entry (recorded as a correction)
+		entry := m.focusedWorkLog()
+		if entry == nil {
+			return m, nil
+		}
+		if err := m.service.DeleteWorkLog(tuiContext(), entry.ID); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.message = fmt.Sprintf("Deleted work log entry #%d", entry.ID)
+		m.err = nil
+		return m, tea.Batch(loadWorkLogs(m.service, m.workLogTodoID), m.reloadTodos())
+	}
+
+	return m, nil
+}
+
+// handleWorkLogEnter submits the duration entered in the work log view
+func (m *Model) handleWorkLogEnter() (tea.Model, tea.Cmd) {
+	ctx := tuiContext()
+	fields := strings.Fields(m.input.Value())
+	if len(fields) == 0 {
+		m.err = errors.New("duration cannot be empty")
+		return m, nil
+	}
+
+	minutes, err := timeutil.ParseMinutes(fields[0])
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	switch m.workLogInputMode {
+	case workLogInputAdd:
+		note := strings.Join(fields[1:], " ")
+		if _, err := m.service.LogWork(ctx, m.workLogTodoID, minutes, note, time.Now()); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.message = fmt.Sprintf("Logged %s for todo #%d", formatSignedMinutes(minutes), m.workLogTodoID)
+
+	case workLogInputEdit:
+		entry := m.focusedWorkLog()
+		if entry == nil {
+			return m, nil
+		}
+		if err := m.service.AdjustWorkLog(ctx, entry.ID, minutes); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.message = fmt.Sprintf("Adjusted work log entry #%d", entry.ID)
+	}
+
+	m.workLogInputMode = workLogInputNone
+	m.input.Placeholder = "Enter command (type /help for help)"
+	m.input.SetValue("")
+	m.err = nil
+	return m, tea.Batch(loadWorkLogs(m.service, m.workLogTodoID), m.reloadTodos())
+}
+
+// recallHistory replaces the input with an older (-1) or newer (1) history entry
+// Only entries starting with the text typed before browsing are recalled. Going
+// past the newest entry brings back the typed text.
+func (m *Model) recallHistory(direction int) {
+	if !m.browsingHistory {
+		m.browsingHistory = true
+		m.historyPrefix = m.input.Value()
+		m.historyIndex = m.history.Len()
+	}
+
+	var entry string
+	var index int
+	var ok bool
+	if direction < 0 {
+		entry, index, ok = m.history.Previous(m.historyPrefix, m.historyIndex)
+	} else {
+		entry, index, ok = m.history.Next(m.historyPrefix, m.historyIndex)
+	}
+
+	switch {
+	case ok:
+		m.historyIndex = index
+		m.input.SetValue(entry)
+	case direction > 0:
+		m.browsingHistory = false
+		m.input.SetValue(m.historyPrefix)
+	default:
+		return // Already at the oldest match
+	}
+	m.input.CursorEnd()
+}
+
+// startHistorySearch starts a reverse search for the text in the input
+func (m *Model) startHistorySearch() {
+	m.browsingHistory = false
+	m.historySearch = &historySearch{
+		query:    m.input.Value(),
+		original: m.input.Value(),
+	}
+	m.findHistoryMatch(m.history.Len())
+}
+
+// findHistoryMatch shows the newest entry before index matching the search query
+// If there is none, a new query shows no match while Ctrl+R keeps the current one.
+func (m *Model) findHistoryMatch(before int) {
+	search := m.historySearch
+	entry, index, ok := m.history.Search(search.query, before)
+	if !ok {
+		if before == m.history.Len() {
+			search.index = before
+			search.match = ""
+		}
+		return
+	}
+	search.index = index
+	search.match = entry
+	m.input.SetValue(entry)
+	m.input.CursorEnd()
+}
+
+// handleHistorySearchKey handles a key during a reverse history search
+// Typing refines the query, Ctrl+R finds older matches and Esc restores the input.
+// Other keys end the search with the match in the input and are handled as usual,
+// so Enter runs the match. It reports whether the key was consumed.
+func (m *Model) handleHistorySearchKey(msg tea.KeyMsg) bool {
+	search := m.historySearch
+	switch msg.Type {
+	case tea.KeyCtrlR:
+		m.findHistoryMatch(search.index)
+	case tea.KeyEsc, tea.KeyCtrlG:
+		m.input.SetValue(search.original)
+		m.input.CursorEnd()
+		m.historySearch = nil
+	case tea.KeyBackspace:
+		if query := []rune(search.query); len(query) > 0 {
+			search.query = string(query[:len(query)-1])
+			m.findHistoryMatch(m.history.Len())
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		search.query += string(msg.Runes)
+		m.findHistoryMatch(m.history.Len())
+	default:
+		m.historySearch = nil
+		return false
+	}
+	return true
+}
+
+// focusedID returns the ID of the todo under the cursor in the list (0 if none)
+func (m Model) focusedID() int64 {
+	if m.cursor < 0 || m.cursor >= len(m.todos) {
+		return 0
+	}
+	return m.todos[m.cursor].ID
+}
+
+// findTodo returns the todo with the given ID, from the loaded todos or else from the service
+// Deferred todos and todos beyond the loaded pages are not loaded but can still be addressed.
+func (m Model) findTodo(id int64) (*model.Todo, error) {
+	for _, todo := range m.todos {
+		if todo.ID == id {
+			return todo, nil
+		}
+	}
+	return m.service.GetTodo(tuiContext(), id)
+}
+
+// openDetailView shows the detail view of the todo
+func (m *Model) openDetailView(todo *model.Todo) tea.Cmd {
+	m.viewMode = ViewModeDetail
+	m.detailTodoID = todo.ID
+	m.detailTodo = todo
+	m.detailReminders = nil
+	m.detailEvents = nil
+	m.showHistory = false
+	m.detailScroll = 0
+	return loadDetailReminders(m.service, todo.ID)
+}
+
+// focusedWorkLog returns the work log entry under the cursor (nil if none)
+func (m Model) focusedWorkLog() *model.WorkLog {
+	if m.workLogCursor < 0 || m.workLogCursor >= len(m.workLogs) {
+		return nil
 	}
+	return m.workLogs[m.workLogCursor]
 }
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
@@ -20,6 +21,10 @@ func (m Model) View() string {
 		return m.renderMinWidthErrorView()
 	}
 
+	if m.confirm != nil {
+		return m.renderConfirmView()
+	}
+
 	switch m.viewMode {
 	case ViewModeBanner:
 		return m.renderBannerView()
@@ -37,6 +42,18 @@ func (m Model) View() string {
 		return m.renderExportView()
 	case ViewModeImport:
 		return m.renderImportView()
+	case ViewModeWorkLog:
+		return m.renderWorkLogView()
+	case ViewModeReport:
+		return m.renderReportView()
+	case ViewModeStats:
+		return m.renderStatsView()
+	case ViewModeTrash:
+		return m.renderTrashView()
+	case ViewModeArchive:
+		return m.renderArchiveView()
+	case ViewModePalette:
+		return m.renderPaletteView()
 	default:
 		return m.renderListView()
 	}
@@ -78,6 +95,38 @@ func (m Model) renderMinWidthErrorView() string {
 // renderListView renders the main todo list view
 func (m Model) renderListView() string {
 	var s strings.Builder
+	s.WriteString(m.renderListTop())
+
+	// Todo items, scrolled to keep the cursor visible
+	if len(m.todos) > 0 {
+		widths := calculateDynamicWidths(m.width)
+		start, end := listWindow(m.listOffset, m.cursor, m.listRows(), len(m.todos))
+		for i := start; i < end; i++ {
+			todo := m.todos[i]
+			s.WriteString(m.renderTodoItem(i, todo, widths))
+			s.WriteString("\n")
+			// Field edited in place, right below its todo
+			if m.inlineEdit != nil && m.inlineEdit.todoID == todo.ID {
+				s.WriteString("   ✎ " + m.inlineEdit.input.View())
+				s.WriteString(helpStyle.UnsetMarginTop().Render("  (Enter to save, Esc to cancel)"))
+				s.WriteString("\n")
+			}
+		}
+
+		// Position in the list, when it does not fit on the screen
+		if start > 0 || end < len(m.todos) || len(m.todos) < m.totalTodos {
+			s.WriteString(m.renderListPosition())
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString(m.renderListBottom())
+	return s.String()
+}
+
+// renderListTop renders the list view down to the header of the todo list
+func (m Model) renderListTop() string {
+	var s strings.Builder
 
 	// Calculate dynamic widths
 	widths := calculateDynamicWidths(m.width)
@@ -86,6 +135,32 @@ func (m Model) renderListView() string {
 	s.WriteString(titleStyle.Render(" 📝 koto - ToDo Manager "))
 	s.WriteString("\n\n")
 
+	// Daily goal progress
+	if goal := m.renderGoalProgress(30); goal != "" {
+		s.WriteString(goal)
+		s.WriteString("\n\n")
+	}
+
+	// End-of-day summary
+	if m.showDaySummary && m.goalProgress != nil {
+		s.WriteString(m.renderDaySummary())
+		s.WriteString("\n\n")
+	}
+
+	// Due reminders
+	if len(m.activeReminders) > 0 {
+		s.WriteString(m.renderReminderBanner())
+		s.WriteString("\n\n")
+	}
+
+	// Deferred todos hidden from the list
+	if m.deferred > 0 {
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(fgDim).
+			Render(fmt.Sprintf("💤 %d deferred todo(s) hidden (/list --deferred to see them)", m.deferred)))
+		s.WriteString("\n")
+	}
+
 	// Todo list
 	if len(m.todos) == 0 {
 		s.WriteString(emptyStyle.Render("  No todos yet. Use /add to create your first todo!  "))
@@ -103,18 +178,56 @@ func (m Model) renderListView() string {
 		headerWithStyle := headerStyle.Render(header)
 		s.WriteString(headerWithStyle)
 		s.WriteString("\n")
+	}
 
-		// Todo items (no separator lines between items for cleaner look)
-		for i, todo := range m.todos {
-			s.WriteString(m.renderTodoItem(i, todo, widths))
-			s.WriteString("\n")
-		}
+	return s.String()
+}
+
+// renderListPosition renders the position of the cursor in the list with the paging keys
+func (m Model) renderListPosition() string {
+	position := fmt.Sprintf("  %d/%d", m.cursor+1, max(m.totalTodos, len(m.todos)))
+	if m.loadingMore {
+		position += " (loading…)"
+	}
+	return lipgloss.NewStyle().Foreground(accentGreen).Render(position) +
+		lipgloss.NewStyle().Foreground(fgDim).Render("  PgUp/PgDn: page | g/G: first/last")
+}
+
+// renderListBottom renders the list view below the todo list
+func (m Model) renderListBottom() string {
+	var s strings.Builder
+
+	// Selection
+	if len(m.selected) > 0 {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(accentGreen).
+			Bold(true).
+			Render(fmt.Sprintf("● %d selected", len(m.selected))))
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(fgDim).
+			Render("  /bulk complete | delete | priority <level> | due <date> | export [file]  (Esc to clear)"))
+		s.WriteString("\n")
+	}
+
+	// Running stopwatch
+	if m.stopwatch != nil {
+		s.WriteString("\n")
+		s.WriteString(m.renderStopwatchStatus())
+		s.WriteString("\n")
 	}
 
 	// Input field
 	s.WriteString("\n")
 	s.WriteString(m.input.View())
 	s.WriteString("\n")
+	if m.historySearch != nil {
+		s.WriteString(helpStyle.Render(m.renderHistorySearch()))
+		s.WriteString("\n")
+	} else if hint := inputHint(m.input.Value(), m.inputMatches.todos); hint != "" {
+		s.WriteString(helpStyle.Render(hint))
+		s.WriteString("\n")
+	}
 
 	// Status messages
 	if m.message != "" {
@@ -131,19 +244,83 @@ func (m Model) renderListView() string {
 
 	// Help text
 	s.WriteString("\n")
-	s.WriteString(helpStyle.Render("Commands: /add, /list, /done, /edit, /pomo, /help | Navigate: ↑/↓ or j/k | Help: ? | Quit: /exit or Ctrl+C"))
+	s.WriteString(helpStyle.Render("Commands: " + strings.Join(commandNames(), ", ") + " | Complete: Tab | Navigate: ↑/↓ or j/k | Undo: u | Help: ? | Quit: /exit or Ctrl+C"))
 
 	return s.String()
 }
 
+// renderHistorySearch renders the state of the reverse history search
+func (m Model) renderHistorySearch() string {
+	match := m.historySearch.match
+	if match == "" {
+		match = "no match"
+	}
+	return fmt.Sprintf("(reverse-i-search)`%s': %s | Ctrl+R: Older | Enter: Run | Esc: Cancel", m.historySearch.query, match)
+}
+
+// renderStopwatchStatus renders the status line of the running stopwatch
+func (m Model) renderStopwatchStatus() string {
+	title := ""
+	if m.stopwatchTitle != "" {
+		title = " " + truncateStringByWidth(m.stopwatchTitle, 40)
+	}
+
+	status := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("213")).
+		Bold(true).
+		Render(fmt.Sprintf("⏱️  Tracking #%d%s  %s", m.stopwatch.TodoID, title, formatElapsed(m.stopwatch.Elapsed(time.Now()))))
+	hint := lipgloss.NewStyle().
+		Foreground(fgDim).
+		Render("  (/stop to record)")
+	return status + hint
+}
+
+// renderReminderBanner renders the oldest due reminder with the snooze keys
+func (m Model) renderReminderBanner() string {
+	reminder := m.activeReminders[0]
+
+	text := fmt.Sprintf("🔔 #%d %s  (since %s)", reminder.TodoID, truncateStringByWidth(reminder.TodoTitle, 40), reminder.RemindAt.Format("15:04"))
+	if more := len(m.activeReminders) - 1; more > 0 {
+		text += fmt.Sprintf("  +%d more", more)
+	}
+
+	status := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Bold(true).
+		Render(text)
+	hint := lipgloss.NewStyle().
+		Foreground(fgDim).
+		Render("  z: +10m | Z: +1h | n: tomorrow | x: dismiss")
+	return status + hint
+}
+
+// formatElapsed formats an elapsed duration as m:ss or h:mm:ss
+func formatElapsed(d time.Duration) string {
+	total := int(d / time.Second)
+	hours := total / 3600
+	minutes := (total % 3600) / 60
+	seconds := total % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
 // renderTodoItem renders a single todo item in table format
 func (m Model) renderTodoItem(index int, todo *model.Todo, widths DynamicWidths) string {
-	// No. (ID) - dynamic width
+	// No. (ID) - dynamic width, marked when the todo is selected
 	no := fmt.Sprintf("%d", todo.ID)
+	if m.selected[todo.ID] {
+		no = "●" + no
+	}
 	no = padStringToWidth(no, widths.NoCol)
 
-	// Title - dynamic width
-	title := truncateStringByWidth(todo.Title, widths.TitleCol)
+	// Title - dynamic width, followed by the tags
+	title := todo.Title
+	if len(todo.Tags) > 0 {
+		title += "  " + formatTags(todo.Tags)
+	}
+	title = truncateStringByWidth(title, widths.TitleCol)
 	title = padStringToWidth(title, widths.TitleCol)
 
 	// Priority - dynamic width
@@ -176,11 +353,14 @@ func (m Model) renderTodoItem(index int, todo *model.Todo, widths DynamicWidths)
 			Render(priorityPadded)
 	}
 
-	// Total time - dynamic width
+	// Total time - dynamic width (live elapsed time while the stopwatch runs)
 	totalTime := todo.GetWorkDurationFormatted()
 	if totalTime == "" {
 		totalTime = "-"
 	}
+	if m.stopwatch != nil && m.stopwatch.TodoID == todo.ID {
+		totalTime = "▶ " + formatElapsed(m.stopwatch.Elapsed(time.Now()))
+	}
 	totalTime = padStringToWidth(totalTime, widths.WorkTimeCol)
 
 	// Create Date (format: YYYY-MM-DD) - dynamic width
@@ -263,53 +443,23 @@ func (m Model) renderHelpContent() string {
 	s.WriteString(cmdHeader)
 	s.WriteString("\n\n")
 
-	commands := []struct {
-		command string
-		desc    string
-		example string
-	}{
-		{"/add", "Add a new todo (interactive)", "/add"},
-		{"", "  → Step 1: Enter title", ""},
-		{"", "  → Step 2: Enter description (optional)", ""},
-		{"", "  → Step 3: Select priority (1-3)", ""},
-		{"", "", ""},
-		{"/list", "List all todos", "/list"},
-		{"/list --status=<pending|completed>", "List by status", "/list --status=pending"},
-		{"", "", ""},
-		{"/done <id>", "Delete a todo", "/done 1"},
-		{"/edit <id>", "Edit a todo (interactive)", "/edit 1"},
-		{"", "  → Step 1: Edit title", ""},
-		{"", "  → Step 2: Edit description (optional)", ""},
-		{"", "  → Step 3: Select priority (1-3)", ""},
-		{"", "", ""},
-		{"/pomo [id]", "Start a 25-minute Pomodoro timer", "/pomo"},
-		{"", "  → General timer (no task)", "/pomo"},
-		{"", "  → Task-specific timer (records time)", "/pomo 1"},
-		{"", "", ""},
-		{"/export [filepath]", "Export todos to JSON", "/export ~/todos.json"},
-		{"/import <filepath>", "Import todos from JSON", "/import ~/todos.json"},
-		{"", "", ""},
-		{"/help", "Show this help screen", "/help"},
-		{"/exit", "Quit the application", "/exit"},
-	}
-
 	// Command items with transparent background
 	cmdStyle := lipgloss.NewStyle().Foreground(accentGreen).Bold(true)
 	descStyle := lipgloss.NewStyle().Foreground(fgDefault)
 	exampleLabelStyle := lipgloss.NewStyle().Foreground(fgDim).Italic(true)
 
-	for _, cmd := range commands {
-		if cmd.command == "" {
+	for _, row := range commandHelpRows() {
+		if row.usage == "" && row.desc == "" {
 			s.WriteString("\n")
 			continue
 		}
 		s.WriteString(fmt.Sprintf("  %s  %s\n",
-			cmdStyle.Render(fmt.Sprintf("%-45s", cmd.command)),
-			descStyle.Render(cmd.desc)))
-		if cmd.example != "" {
+			cmdStyle.Render(fmt.Sprintf("%-45s", row.usage)),
+			descStyle.Render(row.desc)))
+		if row.example != "" {
 			s.WriteString(fmt.Sprintf("    %s %s\n",
 				exampleLabelStyle.Render("Example:"),
-				descStyle.Render(cmd.example)))
+				descStyle.Render(row.example)))
 		}
 	}
 
@@ -322,9 +472,23 @@ func (m Model) renderHelpContent() string {
 	keyStyle := lipgloss.NewStyle().Foreground(accentGreen).Bold(true)
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("↑/k      "), descStyle.Render("Move cursor up")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("↓/j      "), descStyle.Render("Move cursor down")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("PgUp/PgDn"), descStyle.Render("Move cursor a page up / down")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("g/G      "), descStyle.Render("Go to the first / last todo (input empty)")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Enter    "), descStyle.Render("Execute command")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Tab      "), descStyle.Render("Complete the command or todo ID")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Ctrl+P   "), descStyle.Render("Open the command palette")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("↑/↓      "), descStyle.Render("Recall earlier commands (while typing)")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Esc      "), descStyle.Render("Clear input")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("?        "), descStyle.Render("Toggle help")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("u        "), descStyle.Render("Undo the last change")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Ctrl+R   "), descStyle.Render("Redo the last undone change (input empty), search the command history (while typing)")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("+/-      "), descStyle.Render("Raise / lower the priority of the focused todo")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("c        "), descStyle.Render("Complete / reopen the focused todo")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("r        "), descStyle.Render("Rename the focused todo in place")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("D        "), descStyle.Render("Set the due date of the focused todo")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Space    "), descStyle.Render("Select / unselect the focused todo")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("V        "), descStyle.Render("Select from the last toggled todo to the cursor")))
+	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("*        "), descStyle.Render("Select all / clear the selection")))
 	s.WriteString(fmt.Sprintf("  %s  %s\n", keyStyle.Render("Ctrl+C   "), descStyle.Render("Quit")))
 
 	s.WriteString("\n")
@@ -380,6 +544,12 @@ func (m Model) renderBannerView() string {
 	s.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, todoBox))
 	s.WriteString("\n")
 
+	// Render daily goal progress (centered)
+	if goal := m.renderGoalProgress(20); goal != "" {
+		s.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, goal))
+		s.WriteString("\n\n")
+	}
+
 	// Render "press any key" prompt
 	prompt := bannerPromptStyle.Render("Press any key to continue...")
 	s.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, prompt))
@@ -486,8 +656,12 @@ func (m Model) renderAddTodoView() string {
 		s.WriteString("\n\n")
 	}
 
-	// Input field
-	s.WriteString(m.input.View())
+	// Input field (the description spans several lines)
+	if m.addTodoStep == 1 {
+		s.WriteString(m.descInput.View())
+	} else {
+		s.WriteString(m.input.View())
+	}
 	s.WriteString("\n")
 
 	// Error message if any
@@ -499,9 +673,12 @@ func (m Model) renderAddTodoView() string {
 
 	// Help text
 	s.WriteString("\n")
-	if m.addTodoStep == 0 {
+	switch m.addTodoStep {
+	case 0:
 		s.WriteString(helpStyle.Render("Press Enter to continue | Esc to cancel"))
-	} else {
+	case 1:
+		s.WriteString(helpStyle.Render("Enter for a new line | Ctrl+S or Tab to continue | Ctrl+E to open $EDITOR | Esc to go back"))
+	default:
 		s.WriteString(helpStyle.Render("Press Enter to save | Esc to go back"))
 	}
 
@@ -511,69 +688,87 @@ func (m Model) renderAddTodoView() string {
 // renderEditTodoView renders the edit todo screen
 func (m Model) renderEditTodoView() string {
 	var s strings.Builder
+	f := m.editForm
 
-	// Title with dark background
-	s.WriteString(titleStyle.Render(fmt.Sprintf(" ✏️  Edit Todo #%d ", m.editTodoID)))
-	s.WriteString("\n\n")
-
-	// Show current step
-	var stepIndicator string
-	switch m.editTodoStep {
-	case 0:
-		stepIndicator = headerStyle.Render(" Step 1/3: Edit Title ")
-	case 1:
-		stepIndicator = headerStyle.Render(" Step 2/3: Edit Description (Optional) ")
-	default:
-		stepIndicator = headerStyle.Render(" Step 3/3: Select Priority ")
+	// Title with dark background, marked while there are unsaved changes
+	s.WriteString(titleStyle.Render(fmt.Sprintf(" ✏️  Edit Todo #%d ", f.todoID)))
+	if f.dirty() {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("  ● modified"))
 	}
-	s.WriteString(stepIndicator)
 	s.WriteString("\n\n")
 
-	// Show previously entered data
-	if m.editTodoStep >= 1 {
-		s.WriteString(messageStyle.Render(fmt.Sprintf("Title: %s", m.editTodoTitle)))
-		s.WriteString("\n\n")
-	}
-	if m.editTodoStep == 2 {
-		if m.editTodoDescription != "" {
-			s.WriteString(messageStyle.Render(fmt.Sprintf("Description: %s", m.editTodoDescription)))
-		} else {
-			s.WriteString(emptyStyle.Render("Description: (none)"))
+	for field := 0; field < formFieldCount; field++ {
+		// Field label, highlighted for the focused field
+		label := "  " + formFieldLabels[field]
+		labelStyle := lipgloss.NewStyle().Foreground(fgDim)
+		if field == f.focus {
+			label = "▸ " + formFieldLabels[field]
+			labelStyle = lipgloss.NewStyle().Foreground(accentGreen).Bold(true)
 		}
-		s.WriteString("\n\n")
-		// Show priority options
-		s.WriteString(lipgloss.NewStyle().Foreground(accentGreen).Render("Priority Options:"))
+		s.WriteString(labelStyle.Render(label))
 		s.WriteString("\n")
-		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Render("  1 = Low"))
+
+		switch field {
+		case formFieldTitle:
+			s.WriteString(f.title.View())
+		case formFieldDescription:
+			s.WriteString(f.description.View())
+		case formFieldPriority:
+			s.WriteString(f.renderPriorityChoices())
+		case formFieldDue:
+			s.WriteString(f.due.View())
+		}
 		s.WriteString("\n")
-		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render("  2 = Medium (default)"))
+
+		if f.errs[field] != "" {
+			s.WriteString(errorStyle.Render("  " + f.errs[field]))
+			s.WriteString("\n")
+		}
 		s.WriteString("\n")
-		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  3 = High"))
-		s.WriteString("\n\n")
 	}
 
-	// Input field
-	s.WriteString(m.input.View())
-	s.WriteString("\n")
-
 	// Error message if any
 	if m.err != nil {
-		s.WriteString("\n")
 		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
 		s.WriteString("\n")
 	}
 
-	// Help text
-	s.WriteString("\n")
-	if m.editTodoStep == 0 {
-		s.WriteString(helpStyle.Render("Press Enter to continue | Esc to cancel"))
-	} else {
-		s.WriteString(helpStyle.Render("Press Enter to save | Esc to go back"))
+	// Help text, or the discard confirmation
+	if f.confirmDiscard {
+		s.WriteString(errorStyle.Render("Discard your changes? (y/n)"))
+		return s.String()
+	}
+	help := "Tab/Shift+Tab to move | Ctrl+S to save | Esc to cancel"
+	switch f.focus {
+	case formFieldDescription:
+		help += " | Enter for a new line | Ctrl+E to open $EDITOR"
+	case formFieldPriority:
+		help += " | ←/→ or 1-3 to choose"
 	}
+	s.WriteString(helpStyle.Render(help))
 
 	return s.String()
 }
 
+// renderPriorityChoices renders the priority choices of the edit form with the current one highlighted
+func (f todoForm) renderPriorityChoices() string {
+	colors := map[model.Priority]lipgloss.Color{
+		model.PriorityLow:    lipgloss.Color("82"),
+		model.PriorityMedium: lipgloss.Color("220"),
+		model.PriorityHigh:   lipgloss.Color("196"),
+	}
+
+	choices := make([]string, len(formPriorities))
+	for i, p := range formPriorities {
+		if p.priority == f.priority {
+			choices[i] = lipgloss.NewStyle().Foreground(colors[p.priority]).Bold(true).Render("[" + p.label + "]")
+		} else {
+			choices[i] = lipgloss.NewStyle().Foreground(fgDim).Render(" " + p.label + " ")
+		}
+	}
+	return "  " + strings.Join(choices, " ")
+}
+
 // renderPomodoroView renders the Pomodoro timer screen
 func (m Model) renderPomodoroView() string {
 	var s strings.Builder
@@ -611,15 +806,7 @@ func (m Model) renderPomodoroView() string {
 
 	// Show which todo is being worked on
 	if m.pomoTodoID > 0 {
-		// Find the todo
-		var todoTitle string
-		for _, todo := range m.todos {
-			if todo.ID == m.pomoTodoID {
-				todoTitle = todo.Title
-				break
-			}
-		}
-
+		todoTitle := m.pomoTodoTitle
 		if todoTitle != "" {
 			taskInfoBox := lipgloss.NewStyle().
 				BorderStyle(simpleBorder).
@@ -659,12 +846,17 @@ func (m Model) renderPomodoroView() string {
 
 	// Status indicator
 	statusText := ""
-	if m.pomoCompleted {
+	if m.pomoCompleted && m.pomoSnoozed {
+		statusText = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("213")).
+			Bold(true).
+			Render("💤 Alarm snoozed. Press Enter or Esc to dismiss")
+	} else if m.pomoCompleted {
 		// Timer completed - show alarm message
 		statusText = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196")).
 			Bold(true).
-			Render("🔔 Timer Complete! Press Enter or Esc to stop alarm")
+			Render("🔔 Timer Complete! Press Enter or Esc to stop alarm, s to snooze")
 	} else if m.pomoRunning {
 		statusText = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("213")).
@@ -901,14 +1093,8 @@ func (m Model) renderDetailView() string {
 	// Calculate dynamic widths
 	widths := calculateDynamicWidths(m.width)
 
-	// Find the todo to display
-	var targetTodo *model.Todo
-	for _, todo := range m.todos {
-		if todo.ID == m.detailTodoID {
-			targetTodo = todo
-			break
-		}
-	}
+	// Todo to display
+	targetTodo := m.detailTodo
 
 	if targetTodo == nil {
 		errorBox := lipgloss.NewStyle().
@@ -936,6 +1122,9 @@ func (m Model) renderDetailView() string {
 		Foreground(fgDefault).
 		Render(targetTodo.Title)
 	titleBoxContent := titleLabel + "\n" + titleContent
+	if len(targetTodo.Tags) > 0 {
+		titleBoxContent += "\n" + lipgloss.NewStyle().Foreground(accentGreen).Render(formatTags(targetTodo.Tags))
+	}
 	titleBoxStyle := lipgloss.NewStyle().
 		BorderStyle(simpleBorder).
 		BorderForeground(lipgloss.Color("#585b70")).
@@ -952,9 +1141,18 @@ func (m Model) renderDetailView() string {
 		Render("Description")
 	var descContent string
 	if targetTodo.Description != "" {
+		// Show a window of the rendered Markdown, scrolled with j/k
+		lines := detailDescriptionLines(targetTodo, widths)
+		start := clampDetailScroll(m.detailScroll, len(lines))
+		end := min(start+detailDescriptionHeight, len(lines))
 		descContent = lipgloss.NewStyle().
 			Foreground(fgDefault).
-			Render(targetTodo.Description)
+			Render(strings.Join(lines[start:end], "\n"))
+		if len(lines) > detailDescriptionHeight {
+			descContent += "\n" + lipgloss.NewStyle().
+				Foreground(fgDim).
+				Render(fmt.Sprintf("lines %d-%d of %d (j/k to scroll)", start+1, end, len(lines)))
+		}
 	} else {
 		descContent = emptyStyle.Render("(no description)")
 	}
@@ -1053,12 +1251,78 @@ func (m Model) renderDetailView() string {
 	s.WriteString(threeColumnRow)
 	s.WriteString("\n\n")
 
+	// Reminders
+	if len(m.detailReminders) > 0 {
+		times := make([]string, 0, len(m.detailReminders))
+		for _, reminder := range m.detailReminders {
+			times = append(times, reminder.RemindAt.Format("Mon 2006-01-02 15:04"))
+		}
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Render("🔔 Reminders: " + strings.Join(times, ", ")))
+		s.WriteString("\n\n")
+	}
+
+	// History timeline
+	if m.showHistory {
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("213")).
+			Bold(true).
+			Render("🕘 History"))
+		s.WriteString("\n")
+		if len(m.detailEvents) == 0 {
+			s.WriteString(emptyStyle.Render("(no recorded changes)"))
+			s.WriteString("\n")
+		}
+		for _, event := range m.detailEvents {
+			s.WriteString(lipgloss.NewStyle().Foreground(fgDim).Render(event.CreatedAt.Format("2006-01-02 15:04")))
+			s.WriteString("  ")
+			s.WriteString(lipgloss.NewStyle().Foreground(fgDefault).Render(formatTodoEvent(event)))
+			s.WriteString("  ")
+			s.WriteString(lipgloss.NewStyle().Foreground(fgDim).Render("(" + string(event.Source) + ")"))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
 	// Help text
-	s.WriteString(helpStyle.Render("Press Enter to return | e to edit | d to done | p to pomodoro"))
+	s.WriteString(helpStyle.Render("Press Enter to return | j/k to scroll | e to edit | d to done | p to pomodoro | s to stopwatch | t to time log | h to history"))
 
 	return s.String()
 }
 
+// formatTags formats tags as "#auth #ui"
+func formatTags(tags []string) string {
+	return "#" + strings.Join(tags, " #")
+}
+
+// formatTodoEvent describes a change in the history timeline
+func formatTodoEvent(event *model.TodoEvent) string {
+	switch event.Field {
+	case model.EventFieldCreated:
+		return fmt.Sprintf("created %q", truncateStringByWidth(event.NewValue, 40))
+	case model.EventFieldDeletedAt:
+		if event.NewValue == "" {
+			return "restored from trash"
+		}
+		return "moved to trash"
+	case model.EventFieldArchivedAt:
+		if event.NewValue == "" {
+			return "unarchived"
+		}
+		return "archived"
+	}
+
+	value := func(v string) string {
+		if v == "" {
+			return "—"
+		}
+		return truncateStringByWidth(v, 30)
+	}
+	field := strings.ReplaceAll(event.Field, "_", " ")
+	return fmt.Sprintf("%s: %s → %s", field, value(event.OldValue), value(event.NewValue))
+}
+
 // renderExportView renders the export screen
 func (m Model) renderExportView() string {
 	var s strings.Builder
@@ -1263,3 +1527,651 @@ func (m Model) renderImportView() string {
 
 	return s.String()
 }
+
+// formatSignedMinutes formats minutes with an explicit sign (e.g. "+1h 30m", "-15m")
+func formatSignedMinutes(minutes int) string {
+	switch {
+	case minutes > 0:
+		return "+" + model.FormatMinutes(minutes)
+	case minutes < 0:
+		return "-" + model.FormatMinutes(-minutes)
+	default:
+		return "0m"
+	}
+}
+
+// renderWorkLogView renders the work log (time entries) of a todo
+func (m Model) renderWorkLogView() string {
+	var s strings.Builder
+
+	// The work log is opened from the detail view of its todo
+	targetTodo := m.detailTodo
+
+	// Title with dark background
+	s.WriteString(titleStyle.Render(fmt.Sprintf(" ⏱️  Work Log #%d ", m.workLogTodoID)))
+	s.WriteString("\n\n")
+
+	if targetTodo != nil {
+		total := targetTodo.GetWorkDurationFormatted()
+		if total == "" {
+			total = "0m"
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(fgDefault).Render(targetTodo.Title))
+		s.WriteString("  ")
+		s.WriteString(messageStyle.Render("Total: " + total))
+		s.WriteString("\n\n")
+	}
+
+	if len(m.workLogs) == 0 {
+		s.WriteString(emptyStyle.Render("  No work logged yet. Press a to add an entry.  "))
+		s.WriteString("\n")
+	} else {
+		header := fmt.Sprintf(" %s   %s   %s   %s   %s ",
+			padStringToWidth("Entry", 6),
+			padStringToWidth("Date", 16),
+			padStringToWidth("Source", 10),
+			padStringToWidth("Time", 16),
+			"Note")
+		s.WriteString(headerStyle.Render(header))
+		s.WriteString("\n")
+
+		effective := model.EffectiveWorkMinutes(m.workLogs)
+		for i, entry := range m.workLogs {
+			// Originals show their effective time after corrections
+			timeText := formatSignedMinutes(entry.Minutes)
+			note := entry.Note
+			voided := false
+			if entry.IsCorrection() {
+				note = fmt.Sprintf("↳ #%d %s", *entry.CorrectsID, note)
+			} else if current := effective[entry.ID]; current != entry.Minutes {
+				timeText = fmt.Sprintf("%s → %s", timeText, formatSignedMinutes(current))
+				voided = current == 0
+			}
+
+			row := fmt.Sprintf(" %s   %s   %s   %s   %s ",
+				padStringToWidth(fmt.Sprintf("#%d", entry.ID), 6),
+				padStringToWidth(entry.LoggedAt.Format("2006-01-02 15:04"), 16),
+				padStringToWidth(string(entry.Source), 10),
+				padStringToWidth(timeText, 16),
+				note)
+
+			switch {
+			case i == m.workLogCursor:
+				s.WriteString(lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#1e1e2e")).
+					Background(fgSelected).
+					Bold(true).
+					Render(row))
+			case voided:
+				s.WriteString(completedItemStyle.Render(row))
+			case entry.IsCorrection():
+				s.WriteString(lipgloss.NewStyle().Foreground(fgDim).Render(row))
+			default:
+				s.WriteString(todoItemStyle.Render(row))
+			}
+			s.WriteString("\n")
+		}
+	}
+
+	// Input field while adding or adjusting an entry
+	if m.workLogInputMode != workLogInputNone {
+		s.WriteString("\n")
+		s.WriteString(m.input.View())
+		s.WriteString("\n")
+	}
+
+	// Status messages
+	if m.message != "" && m.err == nil {
+		s.WriteString("\n")
+		s.WriteString(messageStyle.Render(m.message))
+		s.WriteString("\n")
+	}
+
+	if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString("\n")
+	if m.workLogInputMode != workLogInputNone {
+		s.WriteString(helpStyle.Render("Press Enter to save | Esc to cancel"))
+	} else {
+		s.WriteString(helpStyle.Render("↑/↓ to select | a to add | e to adjust | d to delete | Esc to return"))
+	}
+
+	return s.String()
+}
+
+// renderTrashView renders the deleted todos
+func (m Model) renderTrashView() string {
+	var s strings.Builder
+
+	// Title with dark background
+	s.WriteString(titleStyle.Render(" 🗑️  Trash "))
+	s.WriteString("\n\n")
+
+	if len(m.trash) == 0 {
+		s.WriteString(emptyStyle.Render("  The trash is empty.  "))
+		s.WriteString("\n")
+	} else {
+		header := fmt.Sprintf(" %s   %s   %s ",
+			padStringToWidth("No.", 6),
+			padStringToWidth("Deleted", 16),
+			"Title")
+		s.WriteString(headerStyle.Render(header))
+		s.WriteString("\n")
+
+		for i, todo := range m.trash {
+			row := fmt.Sprintf(" %s   %s   %s ",
+				padStringToWidth(fmt.Sprintf("#%d", todo.ID), 6),
+				padStringToWidth(todo.DeletedAt.Format("2006-01-02 15:04"), 16),
+				truncateStringByWidth(todo.Title, 50))
+
+			if i == m.trashCursor {
+				s.WriteString(lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#1e1e2e")).
+					Background(fgSelected).
+					Bold(true).
+					Render(row))
+			} else {
+				s.WriteString(todoItemStyle.Render(row))
+			}
+			s.WriteString("\n")
+		}
+	}
+
+	if days := m.config.TrashRetentionDays; days > 0 {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(fgDim).
+			Render(fmt.Sprintf("Deleted todos are removed permanently after %d days.", days)))
+		s.WriteString("\n")
+	}
+
+	// Status messages
+	if m.message != "" && m.err == nil {
+		s.WriteString("\n")
+		s.WriteString(messageStyle.Render(m.message))
+		s.WriteString("\n")
+	}
+
+	if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("↑/↓ to select | r to restore | X to delete permanently | Esc to return"))
+
+	return s.String()
+}
+
+// renderArchiveView renders the archived todos matching the search
+func (m Model) renderArchiveView() string {
+	var s strings.Builder
+
+	// Title with dark background
+	s.WriteString(titleStyle.Render(" 📦 Archive "))
+	s.WriteString("\n\n")
+
+	// Search field
+	s.WriteString(m.input.View())
+	s.WriteString("\n\n")
+
+	if len(m.archive) == 0 {
+		if m.archiveQuery != "" {
+			s.WriteString(emptyStyle.Render("  No archived todos match the search.  "))
+		} else {
+			s.WriteString(emptyStyle.Render("  The archive is empty.  "))
+		}
+		s.WriteString("\n")
+	} else {
+		header := fmt.Sprintf(" %s   %s   %s ",
+			padStringToWidth("No.", 6),
+			padStringToWidth("Archived", 16),
+			"Title")
+		s.WriteString(headerStyle.Render(header))
+		s.WriteString("\n")
+
+		for i, todo := range m.archive {
+			row := fmt.Sprintf(" %s   %s   %s ",
+				padStringToWidth(fmt.Sprintf("#%d", todo.ID), 6),
+				padStringToWidth(todo.ArchivedAt.Format("2006-01-02 15:04"), 16),
+				truncateStringByWidth(todo.Title, 50))
+
+			if i == m.archiveCursor {
+				s.WriteString(lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#1e1e2e")).
+					Background(fgSelected).
+					Bold(true).
+					Render(row))
+			} else {
+				s.WriteString(todoItemStyle.Render(row))
+			}
+			s.WriteString("\n")
+		}
+	}
+
+	if days := m.config.AutoArchiveDays; days > 0 {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().
+			Foreground(fgDim).
+			Render(fmt.Sprintf("Completed todos are archived automatically after %d days.", days)))
+		s.WriteString("\n")
+	}
+
+	// Status messages
+	if m.message != "" && m.err == nil {
+		s.WriteString("\n")
+		s.WriteString(messageStyle.Render(m.message))
+		s.WriteString("\n")
+	}
+
+	if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("Type to search | ↑/↓ to select | Enter to unarchive | Esc to return"))
+
+	return s.String()
+}
+
+// renderReportView renders the timesheet report screen
+func (m Model) renderReportView() string {
+	var s strings.Builder
+
+	// Calculate dynamic widths
+	widths := calculateDynamicWidths(m.width)
+
+	// Title with dark background
+	s.WriteString(titleStyle.Render(" 🧾 Timesheet "))
+	s.WriteString("\n\n")
+
+	lastDay := m.reportTo.AddDate(0, 0, -1)
+	s.WriteString(headerStyle.Render(fmt.Sprintf(" %s – %s ",
+		m.reportFrom.Format("Mon 2006-01-02"),
+		lastDay.Format("Mon 2006-01-02"))))
+	s.WriteString("\n\n")
+
+	ts := m.timesheet
+	switch {
+	case ts == nil:
+		s.WriteString(emptyStyle.Render("  Loading...  "))
+		s.WriteString("\n")
+	case len(ts.Rows) == 0:
+		s.WriteString(emptyStyle.Render("  No work tracked in this period.  "))
+		s.WriteString("\n")
+	case len(ts.Days) <= 7:
+		s.WriteString(m.renderTimesheetMatrix(ts, widths))
+	default:
+		s.WriteString(m.renderTimesheetSummary(ts, widths))
+	}
+
+	// Status messages
+	if m.message != "" {
+		s.WriteString("\n")
+		s.WriteString(messageStyle.Render(m.message))
+		s.WriteString("\n")
+	}
+
+	if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("←/→ or h/l to change period | c to export CSV | Esc to return"))
+
+	return s.String()
+}
+
+// formatTimesheetCell formats minutes for a timesheet cell ("-" when empty)
+func formatTimesheetCell(minutes int) string {
+	if formatted := model.FormatMinutes(minutes); formatted != "" {
+		return formatted
+	}
+	return "-"
+}
+
+// renderTimesheetMatrix renders a timesheet as a todo × day table (up to a week)
+func (m Model) renderTimesheetMatrix(ts *model.Timesheet, widths DynamicWidths) string {
+	var s strings.Builder
+
+	const dayCol = 8
+	const totalCol = 9
+	titleCol := widths.ContentWidth - len(ts.Days)*(dayCol+1) - totalCol - 4
+	if titleCol < 20 {
+		titleCol = 20
+	}
+
+	// Header
+	header := " " + padStringToWidth("Todo", titleCol)
+	for _, day := range ts.Days {
+		header += " " + padStringToWidth(day.Format("Mon 02"), dayCol)
+	}
+	header += " " + padStringToWidth("Total", totalCol) + " "
+	s.WriteString(headerStyle.Render(header))
+	s.WriteString("\n")
+
+	// One row per todo
+	for _, row := range ts.Rows {
+		title := fmt.Sprintf("#%d %s", row.TodoID, row.Title)
+		line := " " + padStringToWidth(truncateStringByWidth(title, titleCol), titleCol)
+		for _, minutes := range row.Minutes {
+			line += " " + padStringToWidth(formatTimesheetCell(minutes), dayCol)
+		}
+		line += " " + padStringToWidth(formatTimesheetCell(row.Total), totalCol) + " "
+		s.WriteString(todoItemStyle.Render(line))
+		s.WriteString("\n")
+	}
+
+	// Totals
+	totals := " " + padStringToWidth("Total", titleCol)
+	for _, minutes := range ts.DayTotals {
+		totals += " " + padStringToWidth(formatTimesheetCell(minutes), dayCol)
+	}
+	totals += " " + padStringToWidth(formatTimesheetCell(ts.Total), totalCol) + " "
+	s.WriteString(messageStyle.Render(totals))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderTimesheetSummary renders longer ranges as per-day and per-todo totals
+func (m Model) renderTimesheetSummary(ts *model.Timesheet, widths DynamicWidths) string {
+	var s strings.Builder
+
+	const valueCol = 9
+	titleCol := widths.ContentWidth - valueCol - 4
+	if titleCol < 20 {
+		titleCol = 20
+	}
+
+	// Per-day totals (days without tracked work are skipped)
+	s.WriteString(headerStyle.Render(" " + padStringToWidth("Day", titleCol) + " " + padStringToWidth("Total", valueCol) + " "))
+	s.WriteString("\n")
+	for i, day := range ts.Days {
+		if ts.DayTotals[i] == 0 {
+			continue
+		}
+		line := " " + padStringToWidth(day.Format("Mon 2006-01-02"), titleCol) + " " + padStringToWidth(formatTimesheetCell(ts.DayTotals[i]), valueCol) + " "
+		s.WriteString(todoItemStyle.Render(line))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	// Per-todo totals
+	s.WriteString(headerStyle.Render(" " + padStringToWidth("Todo", titleCol) + " " + padStringToWidth("Total", valueCol) + " "))
+	s.WriteString("\n")
+	for _, row := range ts.Rows {
+		title := fmt.Sprintf("#%d %s", row.TodoID, row.Title)
+		line := " " + padStringToWidth(truncateStringByWidth(title, titleCol), titleCol) + " " + padStringToWidth(formatTimesheetCell(row.Total), valueCol) + " "
+		s.WriteString(todoItemStyle.Render(line))
+		s.WriteString("\n")
+	}
+
+	totals := " " + padStringToWidth("Total", titleCol) + " " + padStringToWidth(formatTimesheetCell(ts.Total), valueCol) + " "
+	s.WriteString(messageStyle.Render(totals))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// sparkBlocks are the characters used to draw sparklines, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// renderSparkline renders values as a sparkline scaled to the largest value
+// Zero values are drawn as a space so that inactive days stand out
+func renderSparkline(values []int) string {
+	highest := 0
+	for _, v := range values {
+		if v > highest {
+			highest = v
+		}
+	}
+
+	var s strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			s.WriteRune(' ')
+			continue
+		}
+		level := (v*len(sparkBlocks) - 1) / highest
+		s.WriteRune(sparkBlocks[level])
+	}
+	return s.String()
+}
+
+// formatCompletionTime formats an average completion time (e.g. "45m", "5h", "2.5 days")
+func formatCompletionTime(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1f days", d.Hours()/24)
+	}
+}
+
+// renderStatsView renders the productivity statistics dashboard
+func (m Model) renderStatsView() string {
+	var s strings.Builder
+
+	// Title with dark background
+	s.WriteString(titleStyle.Render(" 📊 Statistics "))
+	s.WriteString("\n\n")
+
+	st := m.stats
+	if st == nil {
+		s.WriteString(emptyStyle.Render("  Loading...  "))
+		s.WriteString("\n")
+	} else {
+		sum := func(values []int) int {
+			total := 0
+			for _, v := range values {
+				total += v
+			}
+			return total
+		}
+		const labelCol = 22
+
+		// Per-day activity
+		s.WriteString(headerStyle.Render(fmt.Sprintf(" Last %d days ", len(st.Days))))
+		s.WriteString("\n")
+		if len(st.Days) > 0 {
+			s.WriteString(helpStyle.Render(fmt.Sprintf("  %s%s … %s",
+				padStringToWidth("", labelCol),
+				st.Days[0].Format("01/02"),
+				st.Days[len(st.Days)-1].Format("01/02"))))
+			s.WriteString("\n")
+		}
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s  %d total",
+			padStringToWidth("Completed per day", labelCol),
+			messageStyle.Render(renderSparkline(st.CompletedPerDay)),
+			sum(st.CompletedPerDay))))
+		s.WriteString("\n")
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s  %d total",
+			padStringToWidth("Pomodoros per day", labelCol),
+			messageStyle.Render(renderSparkline(st.PomodorosPerDay)),
+			sum(st.PomodorosPerDay))))
+		s.WriteString("\n\n")
+
+		// Focus time
+		s.WriteString(headerStyle.Render(" Focus time "))
+		s.WriteString("\n")
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s",
+			padStringToWidth("This week", labelCol), formatTimesheetCell(st.FocusThisWeek))))
+		s.WriteString("\n")
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s",
+			padStringToWidth("Last week", labelCol), formatTimesheetCell(st.FocusLastWeek))))
+		s.WriteString("\n")
+		if st.FocusLastWeek > 0 {
+			change := (st.FocusThisWeek - st.FocusLastWeek) * 100 / st.FocusLastWeek
+			s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%+d%%",
+				padStringToWidth("Change", labelCol), change)))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+
+		// Completion
+		s.WriteString(headerStyle.Render(" Completion "))
+		s.WriteString("\n")
+		avg := "-"
+		if st.CompletedTotal > 0 {
+			avg = fmt.Sprintf("%s (%d todos)", formatCompletionTime(st.AvgCompletion), st.CompletedTotal)
+		}
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s",
+			padStringToWidth("Average time to done", labelCol), avg)))
+		s.WriteString("\n")
+		streak := fmt.Sprintf("%d days", st.CurrentStreak)
+		if st.CurrentStreak > 0 && !st.StreakIncludesToday {
+			streak += " (complete a todo today to keep it going)"
+		}
+		s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s",
+			padStringToWidth("Current streak", labelCol), streak)))
+		s.WriteString("\n\n")
+
+		// Open work by priority
+		s.WriteString(headerStyle.Render(" Open todos by priority "))
+		s.WriteString("\n")
+		priorities := []struct {
+			priority model.Priority
+			label    string
+			color    lipgloss.Color
+		}{
+			{model.PriorityHigh, "High", lipgloss.Color("196")},
+			{model.PriorityMedium, "Medium", lipgloss.Color("220")},
+			{model.PriorityLow, "Low", lipgloss.Color("82")},
+		}
+		open := 0
+		for _, p := range priorities {
+			open += st.OpenByPriority[p.priority]
+		}
+		for _, p := range priorities {
+			count := st.OpenByPriority[p.priority]
+			bar := ""
+			if open > 0 {
+				bar = strings.Repeat("█", count*20/open)
+			}
+			s.WriteString(todoItemStyle.Render(fmt.Sprintf("  %s%s %d",
+				padStringToWidth(p.label, labelCol),
+				lipgloss.NewStyle().Foreground(p.color).Render(bar),
+				count)))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("r to refresh | Esc to return"))
+
+	return s.String()
+}
+
+// renderGoalProgress renders today's progress towards the daily goal on one line
+// Returns an empty string if no goal is set
+func (m Model) renderGoalProgress(barWidth int) string {
+	p := m.goalProgress
+	if p == nil || !p.Goal.IsSet() {
+		return ""
+	}
+
+	var parts []string
+	if p.Goal.Pomodoros > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d 🍅", p.Pomodoros, p.Goal.Pomodoros))
+	}
+	if p.Goal.Minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s", formatTimesheetCell(p.Minutes), model.FormatMinutes(p.Goal.Minutes)))
+	}
+
+	line := "🎯 Today " + strings.Join(parts, " · ") + "  " + m.renderPinkProgressBar(p.Percent(), barWidth)
+	if p.Streak > 0 {
+		line += fmt.Sprintf("  🔥 %d day streak", p.Streak)
+	}
+	return line
+}
+
+// renderDaySummary renders the gentle end-of-day summary box
+func (m Model) renderDaySummary() string {
+	p := m.goalProgress
+
+	var s strings.Builder
+	s.WriteString("🌙 End of day\n\n")
+	s.WriteString(fmt.Sprintf("Pomodoros: %d", p.Pomodoros))
+	if p.Goal.Pomodoros > 0 {
+		s.WriteString(fmt.Sprintf(" of %d", p.Goal.Pomodoros))
+	}
+	s.WriteString(fmt.Sprintf("\nFocus time: %s", formatTimesheetCell(p.Minutes)))
+	if p.Goal.Minutes > 0 {
+		s.WriteString(" of " + model.FormatMinutes(p.Goal.Minutes))
+	}
+	s.WriteString("\n\n")
+
+	if p.Met() {
+		s.WriteString(fmt.Sprintf("Goal met — that's %d in a row. Time to rest!", p.Streak))
+	} else {
+		s.WriteString("Not quite there today, and that's okay. Rest well — tomorrow is a fresh start.")
+	}
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("Esc to dismiss"))
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("213")).
+		Padding(0, 2).
+		Render(s.String())
+}
+
+// renderPaletteView renders the command palette as a box near the top of the screen
+func (m Model) renderPaletteView() string {
+	var s strings.Builder
+
+	s.WriteString(m.paletteInput.View())
+	s.WriteString("\n\n")
+
+	results := paletteResults(m.paletteInput.Value(), m.paletteMatches.todos, m.paletteRecent)
+	if len(results) == 0 {
+		s.WriteString(emptyStyle.Render("No matching commands or todos"))
+		s.WriteString("\n")
+	}
+	for i, item := range results {
+		kind := "todo   "
+		if item.command != nil {
+			kind = "command"
+		}
+		row := fmt.Sprintf(" %s  %s ", kind, truncateStringByWidth(item.label(), 52))
+		if i == m.paletteCursor {
+			s.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#1e1e2e")).
+				Background(fgSelected).
+				Bold(true).
+				Render(row))
+		} else {
+			s.WriteString(todoItemStyle.Render(row))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("↑/↓: Move | Enter: Run or jump | Esc: Close"))
+
+	box := createResponsiveBoxStyle(70, lipgloss.RoundedBorder(), accentGreen).Render(s.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Top, "\n"+box)
+}
@@ -2,8 +2,10 @@ package tui
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mattn/go-runewidth"
+	"github.com/syeeel/koto-cli-go/internal/model"
 )
 
 func TestTruncateTodoTitle(t *testing.T) {
@@ -218,3 +220,79 @@ func TestPadStringToWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSignedMinutes(t *testing.T) {
+	tests := []struct {
+		minutes  int
+		expected string
+	}{
+		{90, "+1h 30m"},
+		{-15, "-15m"},
+		{0, "0m"},
+	}
+
+	for _, tt := range tests {
+		if result := formatSignedMinutes(tt.minutes); result != tt.expected {
+			t.Errorf("formatSignedMinutes(%d) = %q; expected %q", tt.minutes, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{0, "0:00"},
+		{59 * time.Second, "0:59"},
+		{12*time.Minute + 34*time.Second, "12:34"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+
+	for _, tt := range tests {
+		if result := formatElapsed(tt.elapsed); result != tt.expected {
+			t.Errorf("formatElapsed(%v) = %q; expected %q", tt.elapsed, result, tt.expected)
+		}
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		expected string
+	}{
+		{"empty", nil, ""},
+		{"all zero", []int{0, 0}, "  "},
+		{"scaled", []int{1, 2, 4, 8}, "▁▂▄█"},
+		{"single", []int{0, 3, 0}, " █ "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderSparkline(tt.values); result != tt.expected {
+				t.Errorf("renderSparkline(%v) = %q; expected %q", tt.values, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatTodoEvent(t *testing.T) {
+	tests := []struct {
+		event    model.TodoEvent
+		expected string
+	}{
+		{model.TodoEvent{Field: model.EventFieldCreated, NewValue: "Write report"}, `created "Write report"`},
+		{model.TodoEvent{Field: model.EventFieldPriority, OldValue: "low", NewValue: "high"}, "priority: low → high"},
+		{model.TodoEvent{Field: model.EventFieldDueDate, NewValue: "2025-03-14 17:00"}, "due date: — → 2025-03-14 17:00"},
+		{model.TodoEvent{Field: model.EventFieldDeletedAt, NewValue: "2025-03-14 17:00"}, "moved to trash"},
+		{model.TodoEvent{Field: model.EventFieldDeletedAt, OldValue: "2025-03-14 17:00"}, "restored from trash"},
+		{model.TodoEvent{Field: model.EventFieldArchivedAt, NewValue: "2025-03-14 17:00"}, "archived"},
+	}
+
+	for _, tt := range tests {
+		if result := formatTodoEvent(&tt.event); result != tt.expected {
+			t.Errorf("formatTodoEvent(%+v) = %q; expected %q", tt.event, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+-- Migration: Add work_logs table for auditable work time tracking
+-- Every change to a todo's work_duration is recorded as an entry here.
+-- Corrections are appended as new entries referencing the entry they adjust
+-- (corrects_id), so the history is never overwritten.
+
+CREATE TABLE IF NOT EXISTS work_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    minutes INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT 'manual',
+    corrects_id INTEGER,
+    logged_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_logs_todo_id ON work_logs(todo_id);
+CREATE INDEX IF NOT EXISTS idx_work_logs_logged_at ON work_logs(logged_at);
+
+-- Backfill existing totals so previously tracked time keeps an entry
+INSERT INTO work_logs (todo_id, minutes, note, source, logged_at, created_at)
+SELECT id, work_duration, 'Recorded before work logs were introduced', 'pomodoro', updated_at, updated_at
+FROM todos
+WHERE work_duration > 0 AND id NOT IN (SELECT todo_id FROM work_logs);
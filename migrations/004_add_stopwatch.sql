@@ -0,0 +1,9 @@
+-- Migration: Add stopwatch table for free-running time tracking
+-- Holds at most one row: the todo currently being tracked and when tracking started.
+-- Stopping the stopwatch records the elapsed time as a work_logs entry.
+
+CREATE TABLE IF NOT EXISTS stopwatch (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    todo_id INTEGER NOT NULL,
+    started_at DATETIME NOT NULL
+);
@@ -0,0 +1,9 @@
+-- Migration: Add completed_at column for productivity statistics
+-- Records when a todo was completed (NULL while pending)
+-- Todos completed before this migration use their last update time
+
+ALTER TABLE todos ADD COLUMN completed_at DATETIME;
+
+UPDATE todos SET completed_at = updated_at WHERE status = 1 AND completed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_completed_at ON todos(completed_at);
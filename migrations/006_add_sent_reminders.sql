@@ -0,0 +1,12 @@
+-- Migration: Add sent_reminders table for the reminder daemon
+-- Records which due date reminders were already delivered so that each one
+-- is sent only once. Rows are keyed by the due date, so changing the due date
+-- of a todo re-arms its reminders. offset_minutes is 0 for overdue notices.
+
+CREATE TABLE IF NOT EXISTS sent_reminders (
+    todo_id INTEGER NOT NULL,
+    due_unix INTEGER NOT NULL,
+    offset_minutes INTEGER NOT NULL,
+    sent_at DATETIME NOT NULL,
+    PRIMARY KEY (todo_id, due_unix, offset_minutes)
+);
@@ -0,0 +1,16 @@
+-- Migration: Add reminders table for per-todo reminders
+-- A todo can have several reminders ("remind me at 15:00").
+-- sent_at is set once a notification was delivered; snoozing moves remind_at
+-- and clears sent_at. Dismissed reminders keep their row with dismissed_at set.
+
+CREATE TABLE IF NOT EXISTS reminders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    remind_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    dismissed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_todo_id ON reminders(todo_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_remind_at ON reminders(remind_at);
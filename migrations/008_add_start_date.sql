@@ -0,0 +1,6 @@
+-- Migration: Add start_date column for deferring todos
+-- Deferred todos are hidden from the default list until their start date (NULL if not deferred)
+
+ALTER TABLE todos ADD COLUMN start_date DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_todos_start_date ON todos(start_date);
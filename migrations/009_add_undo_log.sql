@@ -0,0 +1,18 @@
+-- Migration: Add undo_log table for undo/redo
+-- Each row is a change that can be undone; undone_at is set while it is undone.
+-- Todo changes keep JSON snapshots of the todo before and after the change,
+-- work time changes keep the effective minutes of the work log entry.
+
+CREATE TABLE IF NOT EXISTS undo_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    todo_id INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    before_json TEXT,
+    after_json TEXT,
+    work_log_id INTEGER,
+    minutes_before INTEGER NOT NULL DEFAULT 0,
+    minutes_after INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    undone_at DATETIME
+);
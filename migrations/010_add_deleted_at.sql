@@ -0,0 +1,7 @@
+-- Migration: Add deleted_at column for the trash
+-- Deleting a todo sets deleted_at instead of removing the row (NULL while not deleted).
+-- Todos in the trash are purged permanently after the configured retention period.
+
+ALTER TABLE todos ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos(deleted_at);
@@ -0,0 +1,9 @@
+-- Migration: Add archived_at column for the archive
+-- Archived todos (NULL while not archived) are hidden from the default list.
+-- The partial index covers the default list query, which skips archived and deleted todos.
+
+ALTER TABLE todos ADD COLUMN archived_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_todos_archived_at ON todos(archived_at);
+CREATE INDEX IF NOT EXISTS idx_todos_active ON todos(created_at)
+    WHERE archived_at IS NULL AND deleted_at IS NULL;
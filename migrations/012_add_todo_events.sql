@@ -0,0 +1,15 @@
+-- Migration: Add todo_events table for the audit history
+-- Each row is a field-level change of a todo with its old and new value
+-- (as display strings, empty when unset) and where it was made (tui, cli, api, import).
+
+CREATE TABLE IF NOT EXISTS todo_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_events_todo_id ON todo_events(todo_id);
@@ -0,0 +1,5 @@
+-- Migration: Add group_id column to undo_log for bulk changes
+-- The changes of a bulk operation share the ID of their first entry as group_id
+-- and are undone and redone together. NULL for a single change.
+
+ALTER TABLE undo_log ADD COLUMN group_id INTEGER;
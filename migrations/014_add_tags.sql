@@ -0,0 +1,5 @@
+-- Migration: Add tags column to todos
+-- Tags are stored lowercase without the leading "#", separated by spaces
+-- (tags never contain spaces). An empty string means no tags.
+
+ALTER TABLE todos ADD COLUMN tags TEXT NOT NULL DEFAULT '';